@@ -0,0 +1,163 @@
+// Command catalog-doctor checks every product row against the invariants
+// the aggregate enforces, and reports the rows that break them: the ones
+// ProductRepository.FindByID refuses with a corruption error. It exits with
+// status 1 when any remain.
+//
+//	catalog-doctor                 report, one "id<TAB>field: problem" per line
+//	catalog-doctor -quarantine     also record them in product_quarantine, and
+//	                               release products that are clean again
+//	catalog-doctor -fix            apply the repairs that have only one sensible
+//	                               outcome (see domain.ProductRecord.Repair)
+//
+// It reads the same environment as cmd/server (STORAGE_BACKEND, the
+// SPANNER_* variables, DATABASE_URL, SQLITE_PATH).
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"cloud.google.com/go/spanner"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/postgres"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/sqlite"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/check_integrity"
+	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/catalog-proj/internal/pkg/envcfg"
+	"github.com/tshubham2/catalog-proj/internal/services"
+	sqlitedriver "github.com/tshubham2/commitplan/drivers/sqlite"
+)
+
+// finding is a -json output line.
+type finding struct {
+	ProductID string `json:"product_id"`
+	Field     string `json:"field"`
+	Problem   string `json:"problem"`
+	Repaired  bool   `json:"repaired,omitempty"`
+}
+
+func main() {
+	backend := flag.String("backend", envcfg.String("STORAGE_BACKEND", services.BackendSpanner), "spanner, postgres or sqlite")
+	productID := flag.String("product", "", "check only this product (default: every product)")
+	pageSize := flag.Int("page-size", 100, "product IDs read per query when checking every product")
+	quarantine := flag.Bool("quarantine", false, "record corrupt products in product_quarantine")
+	fix := flag.Bool("fix", false, "apply safe repairs")
+	asJSON := flag.Bool("json", false, "print one JSON object per finding")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	records, quarantineRepo, cm, closeAll, err := open(ctx, *backend)
+	if err != nil {
+		log.Fatalf("failed to open %s: %v", *backend, err)
+	}
+	defer closeAll()
+	checker := check_integrity.NewInteractor(records, quarantineRepo, cm, clock.RealClock{})
+
+	out := json.NewEncoder(os.Stdout)
+	emit := func(id string, v domain.Violation, repaired bool) {
+		if *asJSON {
+			_ = out.Encode(finding{ProductID: id, Field: v.Field, Problem: v.Err.Error(), Repaired: repaired})
+			return
+		}
+		if repaired {
+			fmt.Printf("%s\trepaired %s\n", id, v)
+			return
+		}
+		fmt.Printf("%s\t%s\n", id, v)
+	}
+
+	var checked, corrupt, repaired int
+	check := func(id string) {
+		report, err := checker.Execute(ctx, check_integrity.Request{
+			ProductID:  id,
+			Repair:     *fix,
+			Quarantine: *quarantine,
+		})
+		if errors.Is(err, domain.ErrProductNotFound) && *productID == "" {
+			return // deleted since it was listed
+		}
+		if err != nil {
+			log.Fatalf("check %s: %v", id, err)
+		}
+		checked++
+		for _, v := range report.Repaired {
+			emit(id, v, true)
+		}
+		if len(report.Repaired) > 0 {
+			repaired++
+		}
+		for _, v := range report.Violations {
+			emit(id, v, false)
+		}
+		if !report.OK() {
+			corrupt++
+		}
+	}
+
+	if *productID != "" {
+		check(*productID)
+	} else {
+		after := ""
+		for {
+			ids, err := records.ListIDs(ctx, after, *pageSize)
+			if err != nil {
+				log.Fatalf("list products: %v", err)
+			}
+			for _, id := range ids {
+				check(id)
+			}
+			if len(ids) < *pageSize {
+				break
+			}
+			after = ids[len(ids)-1]
+		}
+	}
+
+	log.Printf("checked %d products: %d repaired, %d still corrupt", checked, repaired, corrupt)
+	if corrupt > 0 {
+		os.Exit(1)
+	}
+}
+
+// open connects to the backend's database.
+func open(ctx context.Context, backend string) (contracts.ProductRecordStore, contracts.QuarantineRepository, committer.Committer, func(), error) {
+	switch backend {
+	case services.BackendSpanner:
+		client, err := spanner.NewClient(ctx, envcfg.SpannerDatabasePath())
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		return repo.NewProductRepo(client), repo.NewQuarantineRepo(), committer.NewSpannerCommitter(client), client.Close, nil
+	case services.BackendPostgres:
+		db, err := sql.Open("pgx", envcfg.String("DATABASE_URL", "postgres://localhost:5432/catalog?sslmode=disable"))
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		return postgres.NewProductRepo(db), postgres.NewQuarantineRepo(), committer.NewPostgresCommitter(db), func() { db.Close() }, nil
+	case services.BackendSQLite:
+		db, err := sql.Open("sqlite", sqlitedriver.DSN(envcfg.String("SQLITE_PATH", "catalog.db")))
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		return sqlite.NewProductRepo(db), sqlite.NewQuarantineRepo(), committer.NewSQLiteCommitter(db), func() { db.Close() }, nil
+	case services.BackendMemory:
+		return nil, nil, nil, nil, errors.New("the memory backend doesn't outlive the server")
+	}
+	return nil, nil, nil, nil, fmt.Errorf("unknown storage backend %q", backend)
+}
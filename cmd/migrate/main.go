@@ -0,0 +1,161 @@
+// Command migrate applies the schema migrations of the configured storage
+// backend and tracks them in schema_migrations.
+//
+//	migrate [flags] up        apply pending migrations (-dry-run lists them)
+//	migrate [flags] status    show every migration and whether it's applied
+//	migrate [flags] verify    exit 1 unless the schema is current
+//	migrate [flags] baseline  record migrations up to -version as applied
+//	                          without running them, for databases created
+//	                          before migrations were tracked
+//
+// It reads the same environment as cmd/server (STORAGE_BACKEND, the
+// SPANNER_* variables, DATABASE_URL, SQLITE_PATH). Migrations are built
+// into the binary; -dir reads them from disk instead. Any command refuses
+// to go on when an applied migration's file has changed.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"cloud.google.com/go/spanner"
+	database "cloud.google.com/go/spanner/admin/database/apiv1"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+
+	"github.com/tshubham2/catalog-proj/internal/migrate"
+	"github.com/tshubham2/catalog-proj/internal/pkg/envcfg"
+	"github.com/tshubham2/catalog-proj/internal/services"
+	"github.com/tshubham2/catalog-proj/migrations"
+	sqlitedriver "github.com/tshubham2/commitplan/drivers/sqlite"
+)
+
+func main() {
+	backend := flag.String("backend", envcfg.String("STORAGE_BACKEND", services.BackendSpanner), "spanner, postgres or sqlite")
+	dir := flag.String("dir", "", "read migrations from this directory instead of the built-in ones")
+	dryRun := flag.Bool("dry-run", false, "up: list the pending migrations and their statements, apply nothing")
+	version := flag.Int64("version", 0, "baseline: record migrations up to this version")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: migrate [flags] up|status|verify|baseline\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	target, files, closeTarget, err := open(ctx, *backend)
+	if err != nil {
+		log.Fatalf("failed to open %s: %v", *backend, err)
+	}
+	defer closeTarget()
+	if *dir != "" {
+		files = os.DirFS(*dir)
+	}
+	ms, err := migrate.Load(files)
+	if err != nil {
+		log.Fatal(err)
+	}
+	runner := migrate.NewRunner(target, ms)
+
+	switch cmd := flag.Arg(0); cmd {
+	case "up":
+		applied, err := runner.Up(ctx, *dryRun)
+		for _, m := range applied {
+			if *dryRun {
+				fmt.Printf("-- %s\n", m.Name)
+				for _, stmt := range m.Statements() {
+					fmt.Printf("%s;\n\n", stmt)
+				}
+				continue
+			}
+			fmt.Printf("applied %s\n", m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is current")
+		}
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range statuses {
+			at := ""
+			if !s.AppliedAt.IsZero() {
+				at = s.AppliedAt.Format("2006-01-02 15:04:05Z07:00")
+			}
+			fmt.Printf("%-8s %-40s %s\n", s.State, s.Name, at)
+		}
+	case "verify":
+		if err := runner.Verify(ctx); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("schema is current")
+	case "baseline":
+		if *version <= 0 {
+			log.Fatal("baseline needs -version")
+		}
+		marked, err := runner.Baseline(ctx, *version)
+		for _, m := range marked {
+			fmt.Printf("marked %s\n", m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// open connects to the backend's database and returns its target and
+// built-in migrations.
+func open(ctx context.Context, backend string) (migrate.Target, fs.FS, func(), error) {
+	switch backend {
+	case services.BackendSpanner:
+		client, err := spanner.NewClient(ctx, envcfg.SpannerDatabasePath())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		admin, err := database.NewDatabaseAdminClient(ctx)
+		if err != nil {
+			client.Close()
+			return nil, nil, nil, err
+		}
+		closeAll := func() {
+			admin.Close()
+			client.Close()
+		}
+		return migrate.NewSpannerTarget(client, admin), migrations.Spanner(), closeAll, nil
+	case services.BackendPostgres:
+		db, err := sql.Open("pgx", envcfg.String("DATABASE_URL", "postgres://localhost:5432/catalog?sslmode=disable"))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return migrate.NewPostgresTarget(db), migrations.Postgres(), func() { db.Close() }, nil
+	case services.BackendSQLite:
+		db, err := sql.Open("sqlite", sqlitedriver.DSN(envcfg.String("SQLITE_PATH", "catalog.db")))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return migrate.NewSQLiteTarget(db), migrations.SQLite(), func() { db.Close() }, nil
+	case services.BackendMemory:
+		return nil, nil, nil, errors.New("the memory backend has no schema to migrate")
+	}
+	return nil, nil, nil, fmt.Errorf("unknown storage backend %q", backend)
+}
@@ -0,0 +1,54 @@
+// Command modelgen writes the internal/models table packages from the
+// Spanner migrations built into the binary; see internal/models.Specs.
+// With -check it writes nothing and exits 1 if any package is stale.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tshubham2/catalog-proj/internal/models"
+	"github.com/tshubham2/catalog-proj/internal/models/modelgen"
+	"github.com/tshubham2/catalog-proj/migrations"
+)
+
+func main() {
+	out := flag.String("out", "internal/models", "directory holding the m_* packages")
+	check := flag.Bool("check", false, "report stale packages instead of writing them")
+	flag.Parse()
+
+	schema, err := modelgen.Parse(migrations.Spanner())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	stale := 0
+	for _, spec := range models.Specs {
+		src, err := modelgen.Generate(schema, spec)
+		if err != nil {
+			log.Fatal(err)
+		}
+		path := filepath.Join(*out, spec.Package, modelgen.FileName)
+		if *check {
+			current, err := os.ReadFile(path)
+			if err != nil || !bytes.Equal(current, src) {
+				fmt.Printf("%s is stale\n", path)
+				stale++
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(path, src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if stale > 0 {
+		log.Fatalf("%d generated model packages are stale; run go generate ./internal/models", stale)
+	}
+}
@@ -0,0 +1,92 @@
+// Command outbox-retention purges settled outbox events once and exits. It
+// does what the retention loop in cmd/server does on every tick, for
+// deployments that schedule it externally (cron, Cloud Scheduler) instead.
+//
+// It reads the same environment as cmd/server (STORAGE_BACKEND, the
+// SPANNER_* variables, DATABASE_URL, SQLITE_PATH).
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"cloud.google.com/go/spanner"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/postgres"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/sqlite"
+	"github.com/tshubham2/catalog-proj/internal/outbox"
+	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+	"github.com/tshubham2/catalog-proj/internal/pkg/envcfg"
+	"github.com/tshubham2/catalog-proj/internal/services"
+	sqlitedriver "github.com/tshubham2/commitplan/drivers/sqlite"
+)
+
+func main() {
+	def := outbox.DefaultRetentionConfig()
+	var cfg outbox.RetentionConfig
+	backend := flag.String("backend", envcfg.String("STORAGE_BACKEND", services.BackendSpanner), "spanner, postgres or sqlite")
+	flag.DurationVar(&cfg.ProcessedTTL, "processed-ttl",
+		envcfg.Duration("OUTBOX_PROCESSED_TTL", def.ProcessedTTL), "keep processed events this long")
+	flag.DurationVar(&cfg.DeadLetterTTL, "dead-letter-ttl",
+		envcfg.Duration("OUTBOX_DEAD_LETTER_TTL", def.DeadLetterTTL), "keep dead-lettered events this long")
+	flag.DurationVar(&cfg.Window, "window",
+		envcfg.Duration("OUTBOX_RETENTION_WINDOW", def.Window), "span of processed_at covered by one delete")
+	flag.BoolVar(&cfg.PurgeHistory, "purge-history",
+		envcfg.String("OUTBOX_RETENTION_PURGE_HISTORY", "false") == "true",
+		"delete events even though products can no longer be replayed from them")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store, closeAll, err := open(ctx, *backend)
+	if err != nil {
+		log.Fatalf("failed to open %s: %v", *backend, err)
+	}
+	defer closeAll()
+
+	retention := outbox.NewRetention(store, clock.RealClock{}, cfg)
+	stats, err := retention.PurgeOnce(ctx)
+	log.Printf("purged %d processed and %d dead-lettered events", stats.Processed, stats.DeadLetters)
+	if err != nil {
+		log.Fatalf("retention failed: %v", err)
+	}
+}
+
+// open connects to the backend's database.
+func open(ctx context.Context, backend string) (contracts.OutboxRetentionStore, func(), error) {
+	switch backend {
+	case services.BackendSpanner:
+		client, err := spanner.NewClient(ctx, envcfg.SpannerDatabasePath())
+		if err != nil {
+			return nil, nil, err
+		}
+		return repo.NewOutboxRepo(client), client.Close, nil
+	case services.BackendPostgres:
+		db, err := sql.Open("pgx", envcfg.String("DATABASE_URL", "postgres://localhost:5432/catalog?sslmode=disable"))
+		if err != nil {
+			return nil, nil, err
+		}
+		return postgres.NewOutboxRepo(db), func() { db.Close() }, nil
+	case services.BackendSQLite:
+		db, err := sql.Open("sqlite", sqlitedriver.DSN(envcfg.String("SQLITE_PATH", "catalog.db")))
+		if err != nil {
+			return nil, nil, err
+		}
+		return sqlite.NewOutboxRepo(db), func() { db.Close() }, nil
+	case services.BackendMemory:
+		return nil, nil, errors.New("the memory backend doesn't outlive the server")
+	}
+	return nil, nil, fmt.Errorf("unknown storage backend %q", backend)
+}
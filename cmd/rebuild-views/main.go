@@ -0,0 +1,130 @@
+// Command rebuild-views rebuilds product_views from scratch: every product's
+// view is replaced with the replay of its outbox events, whatever it held
+// before. Products whose history can't be replayed, because retention
+// purged it or it predates CloudEvents envelopes, are copied from their
+// products row and reported. Run it once before serving reads from
+// product_views, and whenever the projection's logic changes.
+//
+// It reads the same environment as cmd/server (STORAGE_BACKEND, the
+// SPANNER_* variables, DATABASE_URL, SQLITE_PATH). The relay can keep
+// running meanwhile: both write a view in a transaction that reads it.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"cloud.google.com/go/spanner"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/postgres"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/sqlite"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/project_views"
+	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/catalog-proj/internal/pkg/envcfg"
+	"github.com/tshubham2/catalog-proj/internal/services"
+	sqlitedriver "github.com/tshubham2/commitplan/drivers/sqlite"
+)
+
+// products is what the command needs of a product repository: IDs to walk
+// and rows to fall back on.
+type products interface {
+	contracts.ProductRepository
+	contracts.ProductRecordStore
+}
+
+func main() {
+	backend := flag.String("backend", envcfg.String("STORAGE_BACKEND", services.BackendSpanner), "spanner, postgres or sqlite")
+	productID := flag.String("product", "", "rebuild only this product (default: every product)")
+	pageSize := flag.Int("page-size", 100, "product IDs read per query when rebuilding every product")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	productRepo, views, events, cm, closeAll, err := open(ctx, *backend)
+	if err != nil {
+		log.Fatalf("failed to open %s: %v", *backend, err)
+	}
+	defer closeAll()
+	projector := project_views.NewInteractor(
+		views, repo.NewEventStoreOn(events, usecases.DecodeEvent), productRepo,
+		usecases.DecodeEvent, cm, clock.RealClock{},
+	)
+
+	var rebuilt, fromRow int
+	rebuild := func(id string) {
+		report, err := projector.Rebuild(ctx, id)
+		if err != nil {
+			log.Fatalf("rebuild %s: %v", id, err)
+		}
+		rebuilt++
+		if report.FromRow {
+			fromRow++
+			fmt.Printf("%s\tcopied from products row: %s\n", id, report.ReplayError)
+		}
+	}
+
+	if *productID != "" {
+		rebuild(*productID)
+	} else {
+		after := ""
+		for {
+			ids, err := productRepo.ListIDs(ctx, after, *pageSize)
+			if err != nil {
+				log.Fatalf("list products: %v", err)
+			}
+			for _, id := range ids {
+				rebuild(id)
+			}
+			if len(ids) < *pageSize {
+				break
+			}
+			after = ids[len(ids)-1]
+		}
+	}
+
+	log.Printf("rebuilt %d product views, %d copied from their products row", rebuilt, fromRow)
+}
+
+// open connects to the backend's database.
+func open(ctx context.Context, backend string) (products, contracts.ProductViewStore, repo.AggregateEvents, committer.Committer, func(), error) {
+	switch backend {
+	case services.BackendSpanner:
+		client, err := spanner.NewClient(ctx, envcfg.SpannerDatabasePath())
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		return repo.NewProductRepo(client), repo.NewProductViewRepo(client), repo.NewOutboxRepo(client),
+			committer.NewSpannerCommitter(client), client.Close, nil
+	case services.BackendPostgres:
+		db, err := sql.Open("pgx", envcfg.String("DATABASE_URL", "postgres://localhost:5432/catalog?sslmode=disable"))
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		return postgres.NewProductRepo(db), postgres.NewProductViewRepo(db), postgres.NewOutboxRepo(db),
+			committer.NewPostgresCommitter(db), func() { db.Close() }, nil
+	case services.BackendSQLite:
+		db, err := sql.Open("sqlite", sqlitedriver.DSN(envcfg.String("SQLITE_PATH", "catalog.db")))
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		return sqlite.NewProductRepo(db), sqlite.NewProductViewRepo(db), sqlite.NewOutboxRepo(db),
+			committer.NewSQLiteCommitter(db), func() { db.Close() }, nil
+	case services.BackendMemory:
+		return nil, nil, nil, nil, nil, errors.New("the memory backend doesn't outlive the server")
+	}
+	return nil, nil, nil, nil, nil, fmt.Errorf("unknown storage backend %q", backend)
+}
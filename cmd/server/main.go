@@ -2,39 +2,153 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"io/fs"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"cloud.google.com/go/spanner"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
+	_ "modernc.org/sqlite"
 
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/cached"
+	"github.com/tshubham2/catalog-proj/internal/migrate"
+	"github.com/tshubham2/catalog-proj/internal/outbox"
+	"github.com/tshubham2/catalog-proj/internal/pkg/envcfg"
 	"github.com/tshubham2/catalog-proj/internal/services"
+	"github.com/tshubham2/catalog-proj/internal/transport/grpc/requestmeta"
+	"github.com/tshubham2/catalog-proj/migrations"
 	pb "github.com/tshubham2/catalog-proj/proto/product/v1"
+	sqlitedriver "github.com/tshubham2/commitplan/drivers/sqlite"
 )
 
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	dbPath := spannerDatabasePath()
-	client, err := spanner.NewClient(ctx, dbPath)
+	backend := envcfg.String("STORAGE_BACKEND", services.BackendSpanner)
+	var (
+		client *spanner.Client
+		pg     *sql.DB
+		lite   *sql.DB
+	)
+	switch backend {
+	case services.BackendSpanner:
+		var err error
+		client, err = spanner.NewClient(ctx, envcfg.SpannerDatabasePath())
+		if err != nil {
+			log.Fatalf("failed to create spanner client: %v", err)
+		}
+		defer client.Close()
+	case services.BackendPostgres:
+		var err error
+		pg, err = sql.Open("pgx", envcfg.String("DATABASE_URL", "postgres://localhost:5432/catalog?sslmode=disable"))
+		if err != nil {
+			log.Fatalf("failed to open postgres: %v", err)
+		}
+		defer pg.Close()
+		log.Printf("using %s storage backend", backend)
+	case services.BackendSQLite:
+		var err error
+		lite, err = sql.Open("sqlite", sqlitedriver.DSN(envcfg.String("SQLITE_PATH", "catalog.db")))
+		if err != nil {
+			log.Fatalf("failed to open sqlite: %v", err)
+		}
+		defer lite.Close()
+		log.Printf("using %s storage backend", backend)
+	default:
+		log.Printf("using %s storage backend", backend)
+	}
+
+	if envcfg.String("MIGRATIONS_VERIFY", "false") == "true" {
+		if err := verifySchema(ctx, backend, client, pg, lite); err != nil {
+			log.Fatalf("schema check failed (run cmd/migrate): %v", err)
+		}
+	}
+
+	relayCfg := outbox.DefaultConfig()
+	relayCfg.PollInterval = envcfg.Duration("OUTBOX_POLL_INTERVAL", relayCfg.PollInterval)
+	relayCfg.BatchSize = envcfg.Int("OUTBOX_BATCH_SIZE", relayCfg.BatchSize)
+	relayCfg.Owner = envcfg.String("OUTBOX_RELAY_ID", relayCfg.Owner)
+	relayCfg.Lease = envcfg.Duration("OUTBOX_LEASE", relayCfg.Lease)
+	relayCfg.Workers = envcfg.Int("OUTBOX_WORKERS", relayCfg.Workers)
+	relayCfg.Retry.MaxAttempts = envcfg.Int("OUTBOX_MAX_ATTEMPTS", relayCfg.Retry.MaxAttempts)
+	relayCfg.Retry.BaseDelay = envcfg.Duration("OUTBOX_RETRY_BASE_DELAY", relayCfg.Retry.BaseDelay)
+	relayCfg.Retry.MaxDelay = envcfg.Duration("OUTBOX_RETRY_MAX_DELAY", relayCfg.Retry.MaxDelay)
+
+	retentionCfg := outbox.DefaultRetentionConfig()
+	retentionCfg.Interval = envcfg.Duration("OUTBOX_RETENTION_INTERVAL", retentionCfg.Interval)
+	retentionCfg.ProcessedTTL = envcfg.Duration("OUTBOX_PROCESSED_TTL", retentionCfg.ProcessedTTL)
+	retentionCfg.DeadLetterTTL = envcfg.Duration("OUTBOX_DEAD_LETTER_TTL", retentionCfg.DeadLetterTTL)
+	retentionCfg.Window = envcfg.Duration("OUTBOX_RETENTION_WINDOW", retentionCfg.Window)
+	retentionCfg.PurgeHistory = envcfg.String("OUTBOX_RETENTION_PURGE_HISTORY", "false") == "true"
+
+	publisherCfg := outbox.PublisherConfig{
+		Kind:     envcfg.String("OUTBOX_PUBLISHER", outbox.PublisherLog),
+		FilePath: envcfg.String("OUTBOX_FILE_PATH", "outbox-events.jsonl"),
+		Webhook: outbox.WebhookConfig{
+			URL:     envcfg.String("OUTBOX_WEBHOOK_URL", ""),
+			Mode:    envcfg.String("OUTBOX_WEBHOOK_MODE", outbox.ModeStructured),
+			Secret:  envcfg.String("OUTBOX_WEBHOOK_SECRET", ""),
+			Timeout: envcfg.Duration("OUTBOX_WEBHOOK_TIMEOUT", 10*time.Second),
+		},
+	}
+
+	var cacheCfg *cached.Config
+	if envcfg.String("PRODUCT_CACHE_ENABLED", "false") == "true" {
+		cfg := cached.DefaultConfig()
+		cfg.Size = envcfg.Int("PRODUCT_CACHE_SIZE", cfg.Size)
+		cfg.TTL = envcfg.Duration("PRODUCT_CACHE_TTL", cfg.TTL)
+		cacheCfg = &cfg
+	}
+
+	container, err := services.NewContainer(client, services.Options{
+		Backend:         backend,
+		Postgres:        pg,
+		SQLite:          lite,
+		ReadModel:       envcfg.String("READ_MODEL", services.ReadModelProducts),
+		Cache:           cacheCfg,
+		PublisherConfig: publisherCfg,
+		Relay:           relayCfg,
+		Retention:       retentionCfg,
+	})
 	if err != nil {
-		log.Fatalf("failed to create spanner client: %v", err)
+		log.Fatalf("failed to wire services: %v", err)
 	}
-	defer client.Close()
+	defer container.Close()
 
-	container := services.NewContainer(client)
+	if envcfg.String("OUTBOX_RELAY_ENABLED", "true") == "true" {
+		go container.Relay.Run(ctx)
+		log.Println("outbox relay started")
+	}
+	if envcfg.String("OUTBOX_RETENTION_ENABLED", "true") == "true" {
+		go container.Retention.Run(ctx)
+		log.Println("outbox retention started")
+	}
+
+	// expvar registers /debug/vars on the default mux.
+	if addr := envcfg.String("METRICS_ADDR", ""); addr != "" {
+		go func() {
+			if err := http.ListenAndServe(addr, nil); err != nil {
+				log.Printf("metrics server error: %v", err)
+			}
+		}()
+		log.Printf("metrics listening on %s", addr)
+	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(requestmeta.UnaryServerInterceptor()))
 	pb.RegisterProductServiceServer(grpcServer, container.Handler)
+	pb.RegisterOutboxAdminServiceServer(grpcServer, container.OutboxAdmin)
 	reflection.Register(grpcServer)
 
-	port := envOrDefault("PORT", "50051")
+	port := envcfg.String("PORT", "50051")
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
 		log.Fatalf("failed to listen on port %s: %v", port, err)
@@ -55,16 +169,26 @@ func main() {
 	}
 }
 
-func spannerDatabasePath() string {
-	project := envOrDefault("SPANNER_PROJECT", "test-project")
-	instance := envOrDefault("SPANNER_INSTANCE", "test-instance")
-	database := envOrDefault("SPANNER_DATABASE", "test-database")
-	return fmt.Sprintf("projects/%s/instances/%s/databases/%s", project, instance, database)
-}
-
-func envOrDefault(key, fallback string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
+// verifySchema checks that every built-in migration of the backend has been
+// applied, unchanged. The memory backend has no schema.
+func verifySchema(ctx context.Context, backend string, client *spanner.Client, pg, lite *sql.DB) error {
+	var (
+		target migrate.Target
+		files  fs.FS
+	)
+	switch backend {
+	case services.BackendSpanner:
+		target, files = migrate.NewSpannerTarget(client, nil), migrations.Spanner()
+	case services.BackendPostgres:
+		target, files = migrate.NewPostgresTarget(pg), migrations.Postgres()
+	case services.BackendSQLite:
+		target, files = migrate.NewSQLiteTarget(lite), migrations.SQLite()
+	default:
+		return nil
+	}
+	ms, err := migrate.Load(files)
+	if err != nil {
+		return err
 	}
-	return fallback
+	return migrate.NewRunner(target, ms).Verify(ctx)
 }
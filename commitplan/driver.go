@@ -0,0 +1,72 @@
+package commitplan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// TxFunc builds a plan from reads made inside a transaction. Drivers hand it
+// a ctx that carries the transaction, so readers of the same backend can find
+// it. It may run more than once when the backend retries the transaction.
+type TxFunc func(ctx context.Context) (*Plan, error)
+
+// Driver applies plans to one storage backend.
+type Driver interface {
+	// Apply writes the plan atomically, after checking its preconditions.
+	// An empty plan writes nothing.
+	Apply(ctx context.Context, plan *Plan) error
+
+	// RunInTransaction runs fn in a read-write transaction and commits the
+	// plan it returns in that same transaction. Errors returned by fn end
+	// the transaction without writing anything.
+	RunInTransaction(ctx context.Context, fn TxFunc) error
+}
+
+// Opener builds a driver on top of a backend handle, such as a
+// *spanner.Client. Each driver documents the handle it expects.
+type Opener func(conn interface{}) (Driver, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Opener{}
+)
+
+// Register makes a driver available under name. Drivers call it from an
+// init function. It panics if name is taken or open is nil.
+func Register(name string, open Opener) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if open == nil {
+		panic("commitplan: Register opener is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("commitplan: Register called twice for driver " + name)
+	}
+	registry[name] = open
+}
+
+// Open builds the named driver on conn. The driver's package must be
+// imported, usually for its side effects only.
+func Open(name string, conn interface{}) (Driver, error) {
+	registryMu.RLock()
+	open, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("commitplan: unknown driver %q (forgotten import?)", name)
+	}
+	return open(conn)
+}
+
+// Drivers returns the names of the registered drivers, sorted.
+func Drivers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,220 @@
+// Package memory is a commitplan driver over tables kept in process memory.
+// Importing it registers the driver as "memory"; commitplan.Open expects a
+// *DB. Nothing survives a restart.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/tshubham2/commitplan"
+)
+
+// Name is the name the driver is registered under.
+const Name = "memory"
+
+func init() {
+	commitplan.Register(Name, func(conn interface{}) (commitplan.Driver, error) {
+		db, ok := conn.(*DB)
+		if !ok || db == nil {
+			return nil, fmt.Errorf("commitplan/memory: want a *memory.DB, got %T", conn)
+		}
+		return NewCommitter(db), nil
+	})
+}
+
+var (
+	// ErrAlreadyExists is returned when an insert hits an existing key.
+	ErrAlreadyExists = errors.New("commitplan/memory: row already exists")
+	// ErrNotFound is returned when an update targets a missing row.
+	ErrNotFound = errors.New("commitplan/memory: row not found")
+)
+
+// Row maps column names to values. Rows handed out by DB are copies.
+type Row map[string]interface{}
+
+func (r Row) clone() Row {
+	out := make(Row, len(r))
+	for c, v := range r {
+		out[c] = v
+	}
+	return out
+}
+
+// DB holds the tables. Tables spring into existence on first write; a row
+// is keyed by the values of the key columns its mutations name.
+type DB struct {
+	// txMu serialises writers, so a transaction's reads can't be changed
+	// under it before it commits.
+	txMu sync.Mutex
+	// mu guards tables; readers only take this one.
+	mu     sync.RWMutex
+	tables map[string]map[string]Row
+}
+
+func NewDB() *DB {
+	return &DB{tables: map[string]map[string]Row{}}
+}
+
+// Get returns the row with the given key.
+func (db *DB) Get(table string, key commitplan.Key) (Row, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	row, ok := db.tables[table][encodeKey(key.Values)]
+	if !ok {
+		return nil, false
+	}
+	return row.clone(), true
+}
+
+// Scan calls fn with every row of table, in no particular order, until fn
+// returns false. fn must not write to db.
+func (db *DB) Scan(table string, fn func(Row) bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	for _, row := range db.tables[table] {
+		if !fn(row.clone()) {
+			return
+		}
+	}
+}
+
+var _ commitplan.Driver = (*Committer)(nil)
+
+type Committer struct {
+	db *DB
+}
+
+func NewCommitter(db *DB) *Committer {
+	return &Committer{db: db}
+}
+
+func (c *Committer) Apply(_ context.Context, plan *commitplan.Plan) error {
+	c.db.txMu.Lock()
+	defer c.db.txMu.Unlock()
+	return c.db.commit(plan)
+}
+
+// RunInTransaction holds the writer lock while fn runs, so transactions are
+// serial and never need re-running. fn must not start another transaction.
+func (c *Committer) RunInTransaction(ctx context.Context, fn commitplan.TxFunc) error {
+	c.db.txMu.Lock()
+	defer c.db.txMu.Unlock()
+
+	plan, err := fn(ctx)
+	if err != nil || plan == nil {
+		return err
+	}
+	return c.db.commit(plan)
+}
+
+// commit checks the preconditions, then applies the mutations to copies of
+// the tables they touch and swaps those in, so a failing mutation leaves
+// nothing behind.
+func (db *DB) commit(plan *commitplan.Plan) error {
+	if plan.IsEmpty() {
+		return nil
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for _, p := range plan.Preconditions() {
+		vc, ok := p.(*commitplan.VersionCheck)
+		if !ok {
+			return fmt.Errorf("commitplan/memory: unexpected precondition type %T", p)
+		}
+		row, found := db.tables[vc.Table][encodeKey(vc.Key.Values)]
+		if !found {
+			return vc.Failure()
+		}
+		if current, _ := row[vc.Column].(int64); current != vc.Expected {
+			return vc.Failure()
+		}
+	}
+
+	staged := map[string]map[string]Row{}
+	for _, m := range plan.Mutations() {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+		rows, ok := staged[m.Table]
+		if !ok {
+			rows = make(map[string]Row, len(db.tables[m.Table]))
+			for k, r := range db.tables[m.Table] {
+				rows[k] = r
+			}
+			staged[m.Table] = rows
+		}
+		if err := apply(rows, m); err != nil {
+			return err
+		}
+	}
+	for table, rows := range staged {
+		db.tables[table] = rows
+	}
+	return nil
+}
+
+// apply never edits a row in place: rows may be shared with readers and with
+// the committed tables.
+func apply(rows map[string]Row, m *commitplan.Mutation) error {
+	key, err := m.Key()
+	if err != nil {
+		return err
+	}
+	k := encodeKey(key.Values)
+	existing, found := rows[k]
+
+	switch m.Op {
+	case commitplan.OpDelete:
+		delete(rows, k)
+		return nil
+	case commitplan.OpInsert:
+		if found {
+			return fmt.Errorf("%w: %s %v", ErrAlreadyExists, m.Table, key.Values)
+		}
+		existing = Row{}
+	case commitplan.OpUpdate:
+		if !found {
+			return fmt.Errorf("%w: %s %v", ErrNotFound, m.Table, key.Values)
+		}
+	case commitplan.OpUpsert:
+		if !found {
+			existing = Row{}
+		}
+	}
+
+	next := existing.clone()
+	for i, c := range m.Columns {
+		next[c] = normalize(m.Values[i])
+	}
+	rows[k] = next
+	return nil
+}
+
+// normalize stores NUMERIC values as big.Rat, like Spanner returns them, and
+// copies them so the caller can't change a committed row.
+func normalize(v interface{}) interface{} {
+	switch r := v.(type) {
+	case *big.Rat:
+		if r == nil {
+			return nil
+		}
+		return *new(big.Rat).Set(r)
+	case big.Rat:
+		return *new(big.Rat).Set(&r)
+	}
+	return v
+}
+
+func encodeKey(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%T:%v", v, v)
+	}
+	return strings.Join(parts, "\x00")
+}
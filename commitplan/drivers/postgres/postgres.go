@@ -0,0 +1,53 @@
+// Package postgres is the PostgreSQL commitplan driver. Importing it
+// registers the driver as "postgres"; commitplan.Open expects a *sql.DB
+// opened with any PostgreSQL database/sql driver, e.g. pgx's stdlib.
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/tshubham2/commitplan"
+	"github.com/tshubham2/commitplan/drivers/sqldb"
+)
+
+// Name is the name the driver is registered under.
+const Name = "postgres"
+
+func init() {
+	commitplan.Register(Name, func(conn interface{}) (commitplan.Driver, error) {
+		db, ok := conn.(*sql.DB)
+		if !ok || db == nil {
+			return nil, fmt.Errorf("commitplan/postgres: want a *sql.DB, got %T", conn)
+		}
+		return NewCommitter(db), nil
+	})
+}
+
+// Dialect runs every transaction SERIALIZABLE, so a transaction whose reads
+// were overtaken by another commit fails with a serialization error and is
+// run again, the way Spanner aborts and retries.
+var Dialect = sqldb.Dialect{
+	Placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+	Isolation:   sql.LevelSerializable,
+	Retryable:   retryable,
+}
+
+func NewCommitter(db *sql.DB) *sqldb.Committer {
+	return sqldb.NewCommitter(db, Dialect)
+}
+
+// retryable matches serialization failures and deadlocks. Drivers expose the
+// SQLSTATE through an SQLState method (pgx's *pgconn.PgError does).
+func retryable(err error) bool {
+	var state interface{ SQLState() string }
+	if !errors.As(err, &state) {
+		return false
+	}
+	switch state.SQLState() {
+	case "40001", "40P01": // serialization_failure, deadlock_detected
+		return true
+	}
+	return false
+}
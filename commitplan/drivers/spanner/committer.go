@@ -1,14 +1,34 @@
+// Package spanner is the Cloud Spanner commitplan driver. Importing it
+// registers the driver as "spanner"; commitplan.Open expects a
+// *spanner.Client.
 package spanner
 
 import (
 	"context"
 	"fmt"
+	"math/big"
 
 	"cloud.google.com/go/spanner"
+	"google.golang.org/grpc/codes"
 
 	"github.com/tshubham2/commitplan"
 )
 
+// Name is the name the driver is registered under.
+const Name = "spanner"
+
+func init() {
+	commitplan.Register(Name, func(conn interface{}) (commitplan.Driver, error) {
+		client, ok := conn.(*spanner.Client)
+		if !ok || client == nil {
+			return nil, fmt.Errorf("commitplan/spanner: want a *spanner.Client, got %T", conn)
+		}
+		return NewCommitter(client), nil
+	})
+}
+
+var _ commitplan.Driver = (*Committer)(nil)
+
 type Committer struct {
 	client *spanner.Client
 }
@@ -18,20 +38,160 @@ func NewCommitter(client *spanner.Client) *Committer {
 }
 
 // Apply writes all mutations from the plan in a single Spanner transaction.
+// Plans without preconditions go through a blind write; otherwise the checks
+// and the writes share a read-write transaction.
 func (c *Committer) Apply(ctx context.Context, plan *commitplan.Plan) error {
 	if plan.IsEmpty() {
 		return nil
 	}
 
+	if len(plan.Preconditions()) == 0 {
+		ms, err := toMutations(plan)
+		if err != nil {
+			return err
+		}
+		_, err = c.client.Apply(ctx, ms)
+		return err
+	}
+
+	_, err := c.client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		return commit(ctx, txn, plan)
+	})
+	return err
+}
+
+type txKey struct{}
+
+// RunInTransaction runs fn in a read-write transaction and buffers the plan
+// it returns into the same transaction, so reads made by fn and the writes
+// commit atomically. fn's ctx carries the transaction; see Transaction. When
+// Spanner aborts the transaction (lock contention, wound-wait), the client
+// library retries fn from scratch until ctx expires; errors returned by fn
+// end the transaction without retry.
+func (c *Committer) RunInTransaction(ctx context.Context, fn commitplan.TxFunc) error {
+	_, err := c.client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		plan, err := fn(context.WithValue(ctx, txKey{}, txn))
+		if err != nil {
+			return err
+		}
+		if plan == nil || plan.IsEmpty() {
+			return nil
+		}
+		return commit(ctx, txn, plan)
+	})
+	return err
+}
+
+// Transaction returns the read-write transaction RunInTransaction bound to
+// ctx, if any.
+func Transaction(ctx context.Context) (*spanner.ReadWriteTransaction, bool) {
+	txn, ok := ctx.Value(txKey{}).(*spanner.ReadWriteTransaction)
+	return txn, ok
+}
+
+func commit(ctx context.Context, txn *spanner.ReadWriteTransaction, plan *commitplan.Plan) error {
+	ms, err := toMutations(plan)
+	if err != nil {
+		return err
+	}
+	checks, err := toChecks(plan)
+	if err != nil {
+		return err
+	}
+
+	for _, vc := range checks {
+		if err := verify(ctx, txn, vc); err != nil {
+			return err
+		}
+	}
+	return txn.BufferWrite(ms)
+}
+
+func verify(ctx context.Context, txn *spanner.ReadWriteTransaction, vc *commitplan.VersionCheck) error {
+	row, err := txn.ReadRow(ctx, vc.Table, toKey(vc.Key.Values), []string{vc.Column})
+	if err != nil {
+		if spanner.ErrCode(err) == codes.NotFound {
+			return vc.Failure()
+		}
+		return err
+	}
+
+	var current int64
+	if err := row.Column(0, &current); err != nil {
+		return err
+	}
+	if current != vc.Expected {
+		return vc.Failure()
+	}
+	return nil
+}
+
+func toMutations(plan *commitplan.Plan) ([]*spanner.Mutation, error) {
 	ms := make([]*spanner.Mutation, 0, len(plan.Mutations()))
 	for _, m := range plan.Mutations() {
-		sm, ok := m.(*spanner.Mutation)
-		if !ok {
-			return fmt.Errorf("commitplan/spanner: unexpected mutation type %T", m)
+		sm, err := toMutation(m)
+		if err != nil {
+			return nil, err
 		}
 		ms = append(ms, sm)
 	}
+	return ms, nil
+}
 
-	_, err := c.client.Apply(ctx, ms)
-	return err
+func toMutation(m *commitplan.Mutation) (*spanner.Mutation, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(m.Values))
+	for i, v := range m.Values {
+		values[i] = toValue(v)
+	}
+
+	switch m.Op {
+	case commitplan.OpInsert:
+		return spanner.Insert(m.Table, m.Columns, values), nil
+	case commitplan.OpUpdate:
+		return spanner.Update(m.Table, m.Columns, values), nil
+	case commitplan.OpUpsert:
+		return spanner.InsertOrUpdate(m.Table, m.Columns, values), nil
+	case commitplan.OpDelete:
+		key, err := m.Key()
+		if err != nil {
+			return nil, err
+		}
+		return spanner.Delete(m.Table, toKey(key.Values)), nil
+	}
+	return nil, fmt.Errorf("commitplan/spanner: unsupported op %s", m.Op)
+}
+
+// toValue maps neutral values onto what the client library encodes. NUMERIC
+// columns take a big.Rat by value.
+func toValue(v interface{}) interface{} {
+	if r, ok := v.(*big.Rat); ok {
+		if r == nil {
+			return nil
+		}
+		return *r
+	}
+	return v
+}
+
+func toKey(values []interface{}) spanner.Key {
+	key := make(spanner.Key, len(values))
+	for i, v := range values {
+		key[i] = toValue(v)
+	}
+	return key
+}
+
+func toChecks(plan *commitplan.Plan) ([]*commitplan.VersionCheck, error) {
+	checks := make([]*commitplan.VersionCheck, 0, len(plan.Preconditions()))
+	for _, p := range plan.Preconditions() {
+		vc, ok := p.(*commitplan.VersionCheck)
+		if !ok {
+			return nil, fmt.Errorf("commitplan/spanner: unexpected precondition type %T", p)
+		}
+		checks = append(checks, vc)
+	}
+	return checks, nil
 }
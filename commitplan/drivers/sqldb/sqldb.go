@@ -0,0 +1,310 @@
+// Package sqldb is the shared commitplan driver for database/sql backends.
+// It turns mutations into INSERT, UPDATE, DELETE and INSERT ... ON CONFLICT
+// statements; a Dialect covers what differs between databases. Backends
+// such as drivers/postgres wrap it and register themselves.
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/tshubham2/commitplan"
+)
+
+// ErrNotFound is returned when an update targets a missing row.
+var ErrNotFound = errors.New("commitplan/sqldb: row not found")
+
+// Dialect describes one SQL database.
+type Dialect struct {
+	// Placeholder renders the n-th (1-based) statement parameter.
+	Placeholder func(n int) string
+	// Isolation is used for every transaction. It must make the reads of a
+	// RunInTransaction function conflict with concurrent writers, the way
+	// Spanner's read-write transactions do.
+	Isolation sql.IsolationLevel
+	// Retryable reports whether a transaction failed only because it lost
+	// a conflict, so that running it again can succeed.
+	Retryable func(err error) bool
+	// Numeric encodes NUMERIC values. Nil sends decimal strings with
+	// Spanner's nine fractional digits.
+	Numeric func(r *big.Rat) interface{}
+	// Time encodes timestamps. Nil sends them as UTC time.Time values.
+	Time func(t time.Time) interface{}
+}
+
+var _ commitplan.Driver = (*Committer)(nil)
+
+type Committer struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+func NewCommitter(db *sql.DB, dialect Dialect) *Committer {
+	return &Committer{db: db, dialect: dialect}
+}
+
+// Apply writes all mutations from the plan in one transaction, after
+// checking its preconditions.
+func (c *Committer) Apply(ctx context.Context, plan *commitplan.Plan) error {
+	if plan.IsEmpty() {
+		return nil
+	}
+	return c.RunInTransaction(ctx, func(context.Context) (*commitplan.Plan, error) {
+		return plan, nil
+	})
+}
+
+type txKey struct{}
+
+// maxAttempts bounds how often RunInTransaction runs fn when the database
+// keeps aborting it, so steady contention fails instead of spinning until
+// a ctx without deadline ends.
+const maxAttempts = 10
+
+// RunInTransaction runs fn in a transaction and commits the plan it returns
+// in that same transaction. fn's ctx carries the transaction; see
+// Transaction. When the database aborts the transaction because it lost a
+// conflict, fn runs again from scratch, up to maxAttempts times or until
+// ctx expires; errors returned by fn end the transaction without retry.
+func (c *Committer) RunInTransaction(ctx context.Context, fn commitplan.TxFunc) error {
+	for attempt := 1; ; attempt++ {
+		err := c.runOnce(ctx, fn)
+		if err == nil || c.dialect.Retryable == nil || !c.dialect.Retryable(err) {
+			return err
+		}
+		if attempt == maxAttempts {
+			return fmt.Errorf("commitplan/sqldb: transaction aborted %d times: %w", attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff(attempt)):
+		}
+	}
+}
+
+// backoff grows linearly up to 100ms; the upper half is randomised so that
+// transactions that conflicted once do not collide again in lockstep.
+func backoff(attempt int) time.Duration {
+	d := time.Duration(attempt) * 5 * time.Millisecond
+	if d > 100*time.Millisecond {
+		d = 100 * time.Millisecond
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+func (c *Committer) runOnce(ctx context.Context, fn commitplan.TxFunc) error {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{Isolation: c.dialect.Isolation})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // no-op after Commit
+
+	plan, err := fn(context.WithValue(ctx, txKey{}, tx))
+	if err != nil {
+		return err
+	}
+	if plan != nil && !plan.IsEmpty() {
+		if err := c.commit(ctx, tx, plan); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Transaction returns the transaction RunInTransaction bound to ctx, if any.
+func Transaction(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok
+}
+
+func (c *Committer) commit(ctx context.Context, tx *sql.Tx, plan *commitplan.Plan) error {
+	for _, p := range plan.Preconditions() {
+		vc, ok := p.(*commitplan.VersionCheck)
+		if !ok {
+			return fmt.Errorf("commitplan/sqldb: unexpected precondition type %T", p)
+		}
+		if err := c.verify(ctx, tx, vc); err != nil {
+			return err
+		}
+	}
+	for _, m := range plan.Mutations() {
+		if err := c.write(ctx, tx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Committer) verify(ctx context.Context, tx *sql.Tx, vc *commitplan.VersionCheck) error {
+	where, args := c.whereKey(vc.Key.Columns, vc.Key.Values, 0)
+	query := "SELECT " + quote(vc.Column) + " FROM " + quote(vc.Table) + " WHERE " + where
+
+	var current int64
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return vc.Failure()
+	}
+	if err != nil {
+		return err
+	}
+	if current != vc.Expected {
+		return vc.Failure()
+	}
+	return nil
+}
+
+func (c *Committer) write(ctx context.Context, tx *sql.Tx, m *commitplan.Mutation) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	query, args, err := c.statement(m)
+	if err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", m.Op, m.Table, err)
+	}
+	if m.Op == commitplan.OpUpdate {
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			key, _ := m.Key()
+			return fmt.Errorf("%w: %s %v", ErrNotFound, m.Table, key.Values)
+		}
+	}
+	return nil
+}
+
+// statement renders a mutation as SQL.
+func (c *Committer) statement(m *commitplan.Mutation) (string, []interface{}, error) {
+	key, err := m.Key()
+	if err != nil {
+		return "", nil, err
+	}
+	table := quote(m.Table)
+
+	switch m.Op {
+	case commitplan.OpInsert, commitplan.OpUpsert:
+		cols := make([]string, len(m.Columns))
+		params := make([]string, len(m.Columns))
+		for i, col := range m.Columns {
+			cols[i] = quote(col)
+			params[i] = c.dialect.Placeholder(i + 1)
+		}
+		query := "INSERT INTO " + table + " (" + strings.Join(cols, ", ") + ") VALUES (" + strings.Join(params, ", ") + ")"
+		if m.Op == commitplan.OpUpsert {
+			query += " ON CONFLICT (" + quoteAll(m.KeyColumns) + ")"
+			var sets []string
+			for _, col := range m.Columns {
+				if !isKey(col, m.KeyColumns) {
+					sets = append(sets, quote(col)+" = excluded."+quote(col))
+				}
+			}
+			if len(sets) == 0 {
+				query += " DO NOTHING"
+			} else {
+				query += " DO UPDATE SET " + strings.Join(sets, ", ")
+			}
+		}
+		return query, c.values(m.Values), nil
+
+	case commitplan.OpUpdate:
+		var (
+			sets []string
+			args []interface{}
+		)
+		for i, col := range m.Columns {
+			if isKey(col, m.KeyColumns) {
+				continue
+			}
+			args = append(args, c.value(m.Values[i]))
+			sets = append(sets, quote(col)+" = "+c.dialect.Placeholder(len(args)))
+		}
+		if len(sets) == 0 {
+			return "", nil, fmt.Errorf("commitplan/sqldb: update of %s sets no columns", m.Table)
+		}
+		where, keyArgs := c.whereKey(key.Columns, key.Values, len(args))
+		return "UPDATE " + table + " SET " + strings.Join(sets, ", ") + " WHERE " + where, append(args, keyArgs...), nil
+
+	case commitplan.OpDelete:
+		where, args := c.whereKey(key.Columns, key.Values, 0)
+		return "DELETE FROM " + table + " WHERE " + where, args, nil
+	}
+	return "", nil, fmt.Errorf("commitplan/sqldb: unsupported op %s", m.Op)
+}
+
+// whereKey matches a row by key; its parameters are numbered after the
+// first offset ones.
+func (c *Committer) whereKey(columns []string, keyValues []interface{}, offset int) (string, []interface{}) {
+	conds := make([]string, len(columns))
+	for i, col := range columns {
+		conds[i] = quote(col) + " = " + c.dialect.Placeholder(offset+i+1)
+	}
+	return strings.Join(conds, " AND "), c.values(keyValues)
+}
+
+func (c *Committer) values(vs []interface{}) []interface{} {
+	out := make([]interface{}, len(vs))
+	for i, v := range vs {
+		out[i] = c.value(v)
+	}
+	return out
+}
+
+// value maps neutral values onto driver arguments, through the dialect's
+// encoders when it has them.
+func (c *Committer) value(v interface{}) interface{} {
+	switch r := v.(type) {
+	case *big.Rat:
+		if r == nil {
+			return nil
+		}
+		return c.numeric(r)
+	case big.Rat:
+		return c.numeric(&r)
+	case time.Time:
+		if c.dialect.Time != nil {
+			return c.dialect.Time(r)
+		}
+		return r.UTC()
+	}
+	return v
+}
+
+func (c *Committer) numeric(r *big.Rat) interface{} {
+	if c.dialect.Numeric != nil {
+		return c.dialect.Numeric(r)
+	}
+	return r.FloatString(9)
+}
+
+func isKey(column string, keyColumns []string) bool {
+	for _, k := range keyColumns {
+		if k == column {
+			return true
+		}
+	}
+	return false
+}
+
+func quote(ident string) string {
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+func quoteAll(idents []string) string {
+	out := make([]string, len(idents))
+	for i, id := range idents {
+		out[i] = quote(id)
+	}
+	return strings.Join(out, ", ")
+}
@@ -0,0 +1,94 @@
+// Package sqlite is the SQLite commitplan driver. Importing it registers the
+// driver as "sqlite"; commitplan.Open expects a *sql.DB opened with DSN, on
+// the pure-Go modernc.org/sqlite database/sql driver.
+//
+// SQLite has no NUMERIC or TIMESTAMP type that round-trips exactly, so the
+// driver stores NUMERIC values as exact rational strings ("25/2") and
+// timestamps as fixed-width UTC text in TimeFormat, which sorts and compares
+// like the instants it encodes.
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/tshubham2/commitplan"
+	"github.com/tshubham2/commitplan/drivers/sqldb"
+)
+
+// Name is the name the driver is registered under.
+const Name = "sqlite"
+
+// TimeFormat is how timestamps are stored. Readers parse it back with
+// time.Parse and format their own query parameters with it.
+const TimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+func init() {
+	commitplan.Register(Name, func(conn interface{}) (commitplan.Driver, error) {
+		db, ok := conn.(*sql.DB)
+		if !ok || db == nil {
+			return nil, fmt.Errorf("commitplan/sqlite: want a *sql.DB, got %T", conn)
+		}
+		return NewCommitter(db), nil
+	})
+}
+
+// DSN returns the modernc.org/sqlite data source name for the database file
+// at path, with the settings the driver relies on: every transaction takes
+// the write lock when it begins, so a RunInTransaction function's reads
+// can't be overtaken before it commits; waiters queue on busy_timeout
+// instead of failing; WAL lets readers run alongside the writer; and
+// foreign keys are enforced.
+func DSN(path string) string {
+	q := url.Values{}
+	q.Add("_pragma", "busy_timeout(5000)")
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Set("_txlock", "immediate")
+	return "file:" + path + "?" + q.Encode()
+}
+
+// Dialect leaves the isolation level at the default: SQLite transactions
+// are serializable, and DSN's immediate locking makes writers run one at a
+// time.
+var Dialect = sqldb.Dialect{
+	Placeholder: func(n int) string { return "?" + strconv.Itoa(n) },
+	Isolation:   sql.LevelDefault,
+	Retryable:   retryable,
+	Numeric:     func(r *big.Rat) interface{} { return r.RatString() },
+	Time:        func(t time.Time) interface{} { return FormatTime(t) },
+}
+
+func NewCommitter(db *sql.DB) *sqldb.Committer {
+	return sqldb.NewCommitter(db, Dialect)
+}
+
+// FormatTime renders t the way the driver stores it.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(TimeFormat)
+}
+
+// ParseTime reads back a timestamp stored by the driver.
+func ParseTime(s string) (time.Time, error) {
+	return time.Parse(TimeFormat, s)
+}
+
+// retryable matches SQLITE_BUSY and SQLITE_LOCKED, including their extended
+// codes, which outlast busy_timeout only under heavy contention. The driver
+// exposes the result code through a Code method.
+func retryable(err error) bool {
+	var coded interface{ Code() int }
+	if !errors.As(err, &coded) {
+		return false
+	}
+	switch coded.Code() & 0xff {
+	case 5, 6: // SQLITE_BUSY, SQLITE_LOCKED
+		return true
+	}
+	return false
+}
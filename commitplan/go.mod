@@ -2,7 +2,10 @@ module github.com/tshubham2/commitplan
 
 go 1.24.0
 
-require cloud.google.com/go/spanner v1.88.0
+require (
+	cloud.google.com/go/spanner v1.88.0
+	google.golang.org/grpc v1.78.0
+)
 
 require (
 	cel.dev/expr v0.24.0 // indirect
@@ -48,6 +51,5 @@ require (
 	google.golang.org/genproto v0.0.0-20260128011058-8636f8732409 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20260203192932-546029d2fa20 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20260203192932-546029d2fa20 // indirect
-	google.golang.org/grpc v1.78.0 // indirect
 	google.golang.org/protobuf v1.36.11 // indirect
 )
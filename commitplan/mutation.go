@@ -0,0 +1,170 @@
+package commitplan
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Op is the kind of write a Mutation performs.
+type Op int
+
+const (
+	// OpInsert adds a row; it fails if the key already exists.
+	OpInsert Op = iota + 1
+	// OpUpdate changes the listed columns of an existing row; it fails if
+	// the row does not exist. Columns not listed keep their values.
+	OpUpdate
+	// OpDelete removes the row with the given key, if there is one.
+	OpDelete
+	// OpUpsert inserts the row, or updates the listed columns when the key
+	// already exists.
+	OpUpsert
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpInsert:
+		return "insert"
+	case OpUpdate:
+		return "update"
+	case OpDelete:
+		return "delete"
+	case OpUpsert:
+		return "upsert"
+	default:
+		return fmt.Sprintf("Op(%d)", int(o))
+	}
+}
+
+// Mutation is a storage-neutral write of one row. Drivers translate it into
+// their own form (a *spanner.Mutation, an SQL statement, ...).
+//
+// Values are plain Go values: string, int64, bool, float64, time.Time,
+// big.Rat or *big.Rat, or nil for NULL. Drivers reject anything else.
+type Mutation struct {
+	Op    Op
+	Table string
+	// KeyColumns names the table's primary-key columns, in key order. Every
+	// key column must be among Columns.
+	KeyColumns []string
+	// Columns and Values are parallel. For OpDelete they hold only the key.
+	Columns []string
+	Values  []interface{}
+}
+
+// Insert builds an OpInsert from parallel columns and values.
+func Insert(table string, keyColumns, columns []string, values []interface{}) *Mutation {
+	return &Mutation{Op: OpInsert, Table: table, KeyColumns: keyColumns, Columns: columns, Values: values}
+}
+
+// InsertMap builds an OpInsert from a column-to-value map.
+func InsertMap(table string, keyColumns []string, values map[string]interface{}) *Mutation {
+	return fromMap(OpInsert, table, keyColumns, values)
+}
+
+// UpdateMap builds an OpUpdate. values must include the key columns.
+func UpdateMap(table string, keyColumns []string, values map[string]interface{}) *Mutation {
+	return fromMap(OpUpdate, table, keyColumns, values)
+}
+
+// UpsertMap builds an OpUpsert. values must include the key columns.
+func UpsertMap(table string, keyColumns []string, values map[string]interface{}) *Mutation {
+	return fromMap(OpUpsert, table, keyColumns, values)
+}
+
+// Delete builds an OpDelete of the row with the given key.
+func Delete(table string, key Key) *Mutation {
+	return &Mutation{Op: OpDelete, Table: table, KeyColumns: key.Columns, Columns: key.Columns, Values: key.Values}
+}
+
+// fromMap sorts the columns so equal maps give equal mutations.
+func fromMap(op Op, table string, keyColumns []string, values map[string]interface{}) *Mutation {
+	columns := make([]string, 0, len(values))
+	for c := range values {
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+
+	m := &Mutation{Op: op, Table: table, KeyColumns: keyColumns, Columns: columns, Values: make([]interface{}, len(columns))}
+	for i, c := range columns {
+		m.Values[i] = values[c]
+	}
+	return m
+}
+
+// Key returns the primary key of the row the mutation writes.
+func (m *Mutation) Key() (Key, error) {
+	key := Key{Columns: m.KeyColumns, Values: make([]interface{}, len(m.KeyColumns))}
+	for i, kc := range m.KeyColumns {
+		v, ok := m.Value(kc)
+		if !ok {
+			return Key{}, fmt.Errorf("commitplan: %s of %s is missing key column %q", m.Op, m.Table, kc)
+		}
+		key.Values[i] = v
+	}
+	return key, nil
+}
+
+// Value returns the value written to column, and whether the mutation
+// writes that column at all.
+func (m *Mutation) Value(column string) (interface{}, bool) {
+	for i, c := range m.Columns {
+		if c == column {
+			return m.Values[i], true
+		}
+	}
+	return nil, false
+}
+
+// Validate checks the mutation is well formed. Drivers call it before
+// translating.
+func (m *Mutation) Validate() error {
+	switch m.Op {
+	case OpInsert, OpUpdate, OpDelete, OpUpsert:
+	default:
+		return fmt.Errorf("commitplan: unknown mutation op %s", m.Op)
+	}
+	if m.Table == "" {
+		return fmt.Errorf("commitplan: %s without a table", m.Op)
+	}
+	if len(m.KeyColumns) == 0 {
+		return fmt.Errorf("commitplan: %s of %s without key columns", m.Op, m.Table)
+	}
+	if len(m.Columns) != len(m.Values) {
+		return fmt.Errorf("commitplan: %s of %s has %d columns but %d values", m.Op, m.Table, len(m.Columns), len(m.Values))
+	}
+	_, err := m.Key()
+	return err
+}
+
+// Key identifies a row by its primary-key columns and their values, in key
+// order.
+type Key struct {
+	Columns []string
+	Values  []interface{}
+}
+
+// KeyOf is the key of a table with a single-column primary key.
+func KeyOf(column string, value interface{}) Key {
+	return Key{Columns: []string{column}, Values: []interface{}{value}}
+}
+
+// VersionCheck is the precondition every driver understands: an INT64 column
+// of a row must still hold Expected when the plan commits. On mismatch, or if
+// the row is gone, the driver returns Err (or ErrPreconditionFailed when Err
+// is nil).
+type VersionCheck struct {
+	Table    string
+	Key      Key
+	Column   string
+	Expected int64
+	Err      error
+}
+
+// Failure is the error a driver returns when the check does not hold.
+func (vc *VersionCheck) Failure() error {
+	if vc.Err != nil {
+		return vc.Err
+	}
+	return ErrPreconditionFailed
+}
@@ -1,22 +1,40 @@
 package commitplan
 
-// Mutation is intentionally interface{} — concrete types (like *spanner.Mutation)
-// are supplied by callers and only interpreted by the driver.
-type Mutation interface{}
+import "errors"
+
+// ErrPreconditionFailed is returned by drivers when a precondition does not
+// hold at commit time and the precondition carries no error of its own.
+var ErrPreconditionFailed = errors.New("commitplan: precondition failed")
+
+// Precondition is a check the driver must verify inside the commit, before
+// any mutation is written. Every driver supports *VersionCheck; other kinds
+// are driver-specific.
+type Precondition interface{}
 
 // Plan collects mutations that should be applied atomically.
 type Plan struct {
-	mutations []Mutation
+	mutations     []*Mutation
+	preconditions []Precondition
 }
 
 func NewPlan() *Plan { return &Plan{} }
 
-func (p *Plan) Add(m Mutation) {
+func (p *Plan) Add(m *Mutation) {
 	if m == nil {
 		return
 	}
 	p.mutations = append(p.mutations, m)
 }
 
-func (p *Plan) Mutations() []Mutation { return p.mutations }
-func (p *Plan) IsEmpty() bool         { return len(p.mutations) == 0 }
+// Require adds a precondition. If any precondition fails, none of the
+// mutations are applied.
+func (p *Plan) Require(c Precondition) {
+	if c == nil {
+		return
+	}
+	p.preconditions = append(p.preconditions, c)
+}
+
+func (p *Plan) Mutations() []*Mutation        { return p.mutations }
+func (p *Plan) Preconditions() []Precondition { return p.preconditions }
+func (p *Plan) IsEmpty() bool                 { return len(p.mutations) == 0 }
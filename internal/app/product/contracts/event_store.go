@@ -0,0 +1,24 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+)
+
+// ErrHistoryUnreplayable means a product's outbox events exist but can't be
+// replayed into a product: one can't be decoded, or the sequence has a gap,
+// e.g. because retention purged the oldest events.
+var ErrHistoryUnreplayable = errors.New("product event history cannot be replayed")
+
+// ProductEventStore rebuilds products from the events in outbox_events
+// instead of the products table.
+type ProductEventStore interface {
+	// LoadFromEvents replays every event of the product in sequence order.
+	// It returns domain.ErrProductNotFound when the product has no events.
+	LoadFromEvents(ctx context.Context, productID string) (*domain.Product, error)
+}
+
+// EventDecoder turns a stored outbox event back into its domain event.
+type EventDecoder func(OutboxEvent) (domain.DomainEvent, error)
@@ -0,0 +1,29 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+// ProductRecordStore is the raw access integrity tooling needs: products as
+// stored, before the aggregate's invariants are checked, and repairs that
+// bypass the aggregate.
+type ProductRecordStore interface {
+	// ListIDs returns up to limit product IDs after the given one, in order.
+	ListIDs(ctx context.Context, after string, limit int) ([]string, error)
+	// FindRecord returns domain.ErrProductNotFound for a missing row, and a
+	// *domain.CorruptProductError for one that can't even be decoded.
+	FindRecord(ctx context.Context, id string) (*domain.ProductRecord, error)
+	RepairMut(r *domain.ProductRecord) *committer.Mutation
+	RecordVersionCheck(r *domain.ProductRecord) committer.Precondition
+}
+
+// QuarantineRepository keeps the products a scan found corrupt, one row
+// each, until a later scan finds them clean.
+type QuarantineRepository interface {
+	QuarantineMut(productID string, violations []domain.Violation, at time.Time) *committer.Mutation
+	ReleaseMut(productID string) *committer.Mutation
+}
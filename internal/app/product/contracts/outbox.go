@@ -0,0 +1,60 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+var ErrOutboxEventNotFound = errors.New("dead-lettered outbox event not found")
+
+// Values of OutboxEvent.Status.
+const (
+	OutboxStatusPending    = "pending"
+	OutboxStatusProcessed  = "processed"
+	OutboxStatusDeadLetter = "dead_letter"
+)
+
+// OutboxStore is the relay's side of outbox_events: it claims events that
+// still need delivering and builds the mutations that settle them. Several
+// relays can share one table; leases keep them from delivering the same
+// event at the same time.
+type OutboxStore interface {
+	// ListClaimable returns pending events that are due at now and not
+	// leased by anyone. Call it from committer.RunInTransaction and claim
+	// the result in the same plan, or another relay may grab them too.
+	ListClaimable(ctx context.Context, now time.Time, limit int) ([]OutboxEvent, error)
+	// ClaimOwner returns the relay currently holding the event, or "".
+	ClaimOwner(ctx context.Context, eventID string) (string, error)
+	// ClaimMut takes or renews a lease.
+	ClaimMut(eventID, owner string, leaseExpiresAt time.Time) *committer.Mutation
+
+	// The settle mutations below also release the lease.
+	MarkProcessedMut(eventID string, processedAt time.Time) *committer.Mutation
+	RetryLaterMut(eventID string, attempts int64, lastError string, nextAttemptAt time.Time) *committer.Mutation
+	DeadLetterMut(eventID string, attempts int64, lastError string, at time.Time) *committer.Mutation
+}
+
+// OutboxRetentionStore deletes settled events. processed_at is when an
+// event reached its terminal status, processed or dead-lettered.
+type OutboxRetentionStore interface {
+	// OldestSettled returns the earliest processed_at among events in
+	// status; ok is false when there are none.
+	OldestSettled(ctx context.Context, status string) (oldest time.Time, ok bool, err error)
+	// PurgeSettled deletes the events in status settled before the given
+	// time and returns how many it removed. It runs outside any
+	// transaction, so callers bound the range to keep each delete small.
+	PurgeSettled(ctx context.Context, status string, before time.Time) (int64, error)
+}
+
+// DeadLetterStore backs the outbox admin endpoints.
+type DeadLetterStore interface {
+	ListDeadLetters(ctx context.Context, pageSize int, pageToken string) ([]OutboxEvent, string, error)
+	// FindDeadLetter returns ErrOutboxEventNotFound unless the event exists
+	// and is dead-lettered.
+	FindDeadLetter(ctx context.Context, eventID string) (*OutboxEvent, error)
+	FindDeadLettersByAggregate(ctx context.Context, aggregateID string) ([]OutboxEvent, error)
+	RequeueMut(eventID string) *committer.Mutation
+}
@@ -0,0 +1,22 @@
+package contracts
+
+import (
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+type PriceHistoryRepository interface {
+	InsertMut(entry PriceChange) *committer.Mutation
+}
+
+// PriceChange is one entry in a product's price history. It is written in
+// the same plan as the price update itself.
+type PriceChange struct {
+	ID        string
+	ProductID string
+	OldPrice  *domain.Money
+	NewPrice  *domain.Money
+	ChangedAt time.Time
+}
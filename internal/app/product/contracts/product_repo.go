@@ -5,19 +5,20 @@ import (
 	"encoding/json"
 	"time"
 
-	"cloud.google.com/go/spanner"
-
 	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/catalog-proj/internal/pkg/eventmeta"
 )
 
 type ProductRepository interface {
 	FindByID(ctx context.Context, id string) (*domain.Product, error)
-	InsertMut(p *domain.Product) *spanner.Mutation
-	UpdateMut(p *domain.Product) *spanner.Mutation
+	InsertMut(p *domain.Product) *committer.Mutation
+	UpdateMut(p *domain.Product) *committer.Mutation
+	VersionCheck(p *domain.Product) committer.Precondition
 }
 
 type OutboxRepository interface {
-	InsertMut(event OutboxEvent) *spanner.Mutation
+	InsertMut(event OutboxEvent) *committer.Mutation
 }
 
 // OutboxEvent is the enriched form of a domain event, ready for persistence.
@@ -25,6 +26,19 @@ type OutboxEvent struct {
 	ID          string
 	EventType   string
 	AggregateID string
-	Payload     json.RawMessage
-	CreatedAt   time.Time
+	// Sequence orders events within an aggregate; it is the aggregate
+	// version the event produced.
+	Sequence  int64
+	Payload   json.RawMessage
+	CreatedAt time.Time
+	// Metadata records what caused the event: correlation and causation
+	// IDs, actor, client and trace context.
+	Metadata eventmeta.Metadata
+
+	// Delivery state, only populated when an event is read back by the relay
+	// or the admin endpoints.
+	Status      string
+	Attempts    int64
+	LastError   string
+	ProcessedAt *time.Time
 }
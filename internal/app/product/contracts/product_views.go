@@ -0,0 +1,21 @@
+package contracts
+
+import (
+	"context"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+// ProductViewStore is the projector's side of product_views, the
+// denormalised copy of products that a ProductReadModel can serve from
+// instead of the write table.
+type ProductViewStore interface {
+	// FindViewRecord reads a product's view through the caller's transaction
+	// when ctx carries one. It returns domain.ErrProductNotFound when the
+	// product has no view yet.
+	FindViewRecord(ctx context.Context, id string) (*domain.ProductRecord, error)
+	// UpsertViewMut replaces the product's view with p as it is now.
+	UpsertViewMut(p *domain.Product, projectedAt time.Time) *committer.Mutation
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"math/big"
 	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain/services"
 )
 
 // ProductReadModel provides read-optimised access, bypassing the aggregate.
@@ -13,18 +16,82 @@ type ProductReadModel interface {
 }
 
 // ProductView is a flat projection of a product row. The query layer
-// runs pricing calculations on top of this to derive effective prices.
+// derives effective prices from it with EffectivePrice.
+//
+// Read from product_views, two fields come precomputed by the projector:
+// DiscountedPrice and SearchText. Whether the discount is running, and so
+// which price is effective, depends on the clock, so it is still decided on
+// each read by checking now against the stored discount window; storing it
+// would need re-projecting every product as its window opens and closes.
 type ProductView struct {
-	ID                   string
-	Name                 string
-	Description          string
-	Category             string
-	BasePriceNumerator   int64
-	BasePriceDenominator int64
-	DiscountPercent      *big.Rat
-	DiscountStartDate    *time.Time
-	DiscountEndDate      *time.Time
-	Status               string
-	CreatedAt            time.Time
-	UpdatedAt            time.Time
+	ID                string
+	Name              string
+	Description       string
+	Category          string
+	BasePrice         *big.Rat
+	DiscountPercent   *big.Rat
+	DiscountStartDate *time.Time
+	DiscountEndDate   *time.Time
+	// DiscountedPrice is the price while the discount runs, when the read
+	// model stores it precomputed (product_views does); nil otherwise.
+	DiscountedPrice *big.Rat
+	// SearchText is the lower-cased name and category product_views is
+	// searched by; empty for a read model that doesn't store it.
+	SearchText string
+	Status     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Version    int64
+}
+
+// Pricing returns the view's price and discount as domain values, or a
+// *domain.CorruptProductError when the stored ones break the aggregate's
+// invariants.
+func (v *ProductView) Pricing() (*domain.Money, *domain.Discount, error) {
+	rec := domain.ProductRecord{
+		ID:              v.ID,
+		BasePrice:       v.BasePrice,
+		DiscountPercent: v.DiscountPercent,
+		DiscountStart:   v.DiscountStartDate,
+		DiscountEnd:     v.DiscountEndDate,
+	}
+	return rec.Pricing()
+}
+
+// EffectivePrice is what the product costs at now, given the base price
+// and discount Pricing returned. It only picks the price: while the
+// discount window is open, a stored DiscountedPrice is served as is, and
+// only without one is the price computed from the base price and discount.
+func (v *ProductView) EffectivePrice(basePrice *domain.Money, discount *domain.Discount, now time.Time) (*domain.Money, error) {
+	if discount == nil || v.DiscountedPrice == nil {
+		return services.CalculateEffectivePrice(basePrice, discount, now), nil
+	}
+	if !discount.IsValidAt(now) {
+		return basePrice, nil
+	}
+	price, err := domain.NewMoneyFromRat(v.DiscountedPrice)
+	if err != nil {
+		return nil, &domain.CorruptProductError{ProductID: v.ID, Violations: []domain.Violation{
+			{Field: "discounted_price", Err: err},
+		}}
+	}
+	return price, nil
+}
+
+// NewProductView flattens a stored record, for read models that read one.
+func NewProductView(r *domain.ProductRecord) *ProductView {
+	return &ProductView{
+		ID:                r.ID,
+		Name:              r.Name,
+		Description:       r.Description,
+		Category:          r.Category,
+		BasePrice:         r.BasePrice,
+		DiscountPercent:   r.DiscountPercent,
+		DiscountStartDate: r.DiscountStart,
+		DiscountEndDate:   r.DiscountEnd,
+		Status:            r.Status,
+		CreatedAt:         r.CreatedAt,
+		UpdatedAt:         r.UpdatedAt,
+		Version:           r.Version,
+	}
 }
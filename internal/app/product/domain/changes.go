@@ -11,6 +11,16 @@ const (
 	FieldStatus      = "status"
 )
 
+// FieldChange is the value of one field before and after an operation.
+// Values have the field's domain type: string for name, description and
+// category, ProductStatus for status, *Money for base_price and *Discount
+// for discount. A nil *Discount means no discount.
+type FieldChange struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
 // ChangeTracker keeps track of which aggregate fields have been modified
 // since the last load. The repo reads this to build partial updates.
 type ChangeTracker struct {
@@ -14,6 +14,8 @@ type Discount struct {
 	endDate    time.Time
 }
 
+// NewDiscount rejects a percentage that can't be stored exactly, like a
+// price, with ErrPriceNotRepresentable.
 func NewDiscount(percentage *big.Rat, startDate, endDate time.Time) (*Discount, error) {
 	zero := new(big.Rat)
 	hundred := new(big.Rat).SetInt64(100)
@@ -21,6 +23,9 @@ func NewDiscount(percentage *big.Rat, startDate, endDate time.Time) (*Discount,
 	if percentage.Cmp(zero) <= 0 || percentage.Cmp(hundred) > 0 {
 		return nil, ErrInvalidDiscountPercent
 	}
+	if !Representable(percentage) {
+		return nil, ErrPriceNotRepresentable
+	}
 	if !startDate.Before(endDate) {
 		return nil, ErrInvalidDiscountPeriod
 	}
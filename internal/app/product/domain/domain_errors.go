@@ -9,10 +9,14 @@ var (
 	ErrProductAlreadyInactive = errors.New("product is already inactive")
 	ErrProductArchived        = errors.New("product is archived")
 	ErrInvalidPrice           = errors.New("price must be positive")
+	ErrPriceNotRepresentable  = errors.New("price or discount must have at most 9 decimal places and fit in 64 bits")
 	ErrInvalidDiscountPercent = errors.New("discount percentage must be between 0 and 100 exclusive")
 	ErrInvalidDiscountPeriod  = errors.New("discount start date must be before end date")
 	ErrDiscountNotActive      = errors.New("discount is not active at the given time")
 	ErrNoActiveDiscount       = errors.New("product has no discount to remove")
 	ErrProductNameRequired    = errors.New("product name is required")
 	ErrCategoryRequired       = errors.New("product category is required")
+	ErrVersionConflict        = errors.New("product was modified concurrently")
+	ErrEventOutOfSequence     = errors.New("event does not follow the product's history")
+	ErrCorruptProduct         = errors.New("stored product breaks the aggregate's invariants")
 )
@@ -10,26 +10,43 @@ import (
 type DomainEvent interface {
 	EventType() string
 	OccurredAt() time.Time
+	// Sequence is the aggregate version the event produced. It increases
+	// strictly per aggregate, which gives consumers a defined order even
+	// when two events share a timestamp.
+	Sequence() int64
+	// Changes lists the fields the operation changed with their old and
+	// new values. Empty for ProductCreatedEvent, which has no "before".
+	Changes() []FieldChange
 }
 
 type baseEvent struct {
 	occurredAt time.Time
+	sequence   int64
+	changes    []FieldChange
 }
 
-func (e baseEvent) OccurredAt() time.Time { return e.occurredAt }
+func (e baseEvent) OccurredAt() time.Time  { return e.occurredAt }
+func (e baseEvent) Sequence() int64        { return e.sequence }
+func (e baseEvent) Changes() []FieldChange { return e.changes }
 
 type ProductCreatedEvent struct {
 	baseEvent
-	ProductID string
-	Name      string
-	Category  string
+	ProductID   string
+	Name        string
+	Category    string
+	Description string
+	BasePrice   *Money
 }
 
 func (e *ProductCreatedEvent) EventType() string { return "product.created" }
 
+// ProductUpdatedEvent carries the details as they are after the update.
 type ProductUpdatedEvent struct {
 	baseEvent
-	ProductID string
+	ProductID   string
+	Name        string
+	Description string
+	Category    string
 }
 
 func (e *ProductUpdatedEvent) EventType() string { return "product.updated" }
@@ -48,6 +65,14 @@ type ProductDeactivatedEvent struct {
 
 func (e *ProductDeactivatedEvent) EventType() string { return "product.deactivated" }
 
+type ProductArchivedEvent struct {
+	baseEvent
+	ProductID  string
+	ArchivedAt time.Time
+}
+
+func (e *ProductArchivedEvent) EventType() string { return "product.archived" }
+
 type DiscountAppliedEvent struct {
 	baseEvent
 	ProductID  string
@@ -64,3 +89,12 @@ type DiscountRemovedEvent struct {
 }
 
 func (e *DiscountRemovedEvent) EventType() string { return "discount.removed" }
+
+type PriceChangedEvent struct {
+	baseEvent
+	ProductID string
+	OldPrice  *Money
+	NewPrice  *Money
+}
+
+func (e *PriceChangedEvent) EventType() string { return "product.price_changed" }
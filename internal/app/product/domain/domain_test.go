@@ -1,8 +1,10 @@
 package domain_test
 
 import (
+	"errors"
 	"math/big"
 	"testing"
+	"testing/quick"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -18,8 +20,7 @@ func TestNewMoney(t *testing.T) {
 	m, err := domain.NewMoney(1999, 100)
 	require.NoError(t, err)
 	assert.Equal(t, "19.99", m.String())
-	assert.Equal(t, int64(1999), m.Numerator())
-	assert.Equal(t, int64(100), m.Denominator())
+	assert.Equal(t, 0, m.Amount().Cmp(big.NewRat(1999, 100)))
 }
 
 func TestNewMoney_NegativePrice(t *testing.T) {
@@ -32,6 +33,51 @@ func TestNewMoney_ZeroDenominator(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestNewMoney_RejectsUnrepresentable(t *testing.T) {
+	_, err := domain.NewMoney(1, 3)
+	assert.ErrorIs(t, err, domain.ErrPriceNotRepresentable)
+
+	_, err = domain.NewMoney(1, 10_000_000_000) // ten decimal places
+	assert.ErrorIs(t, err, domain.ErrPriceNotRepresentable)
+
+	huge := new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), 64))
+	_, err = domain.NewMoneyFromRat(huge)
+	assert.ErrorIs(t, err, domain.ErrPriceNotRepresentable)
+
+	m, err := domain.NewMoneyFromRat(big.NewRat(1, 10_000_000)) // 0.0000001
+	require.NoError(t, err)
+	assert.Equal(t, "0.000000100", m.Amount().FloatString(9))
+}
+
+// A price is accepted exactly when it survives the trip through a nine
+// decimal NUMERIC and an int64 event payload unchanged.
+func TestNewMoney_AcceptsExactlyWhatRoundTrips(t *testing.T) {
+	prop := func(num int64, twos, fives, shift uint8, third bool) bool {
+		if num < 0 {
+			num = -(num + 1)
+		}
+		// Denominators of 2s and 5s straddle the nine decimal limit; a
+		// factor of 3 never terminates; shifting crosses the int64 bound.
+		den := new(big.Int).Lsh(big.NewInt(1), uint(twos%12))
+		den.Mul(den, new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(fives%12)), nil))
+		if third {
+			den.Mul(den, big.NewInt(3))
+		}
+		r := new(big.Rat).SetFrac(new(big.Int).Lsh(big.NewInt(num), uint(shift%4)), den)
+
+		m, err := domain.NewMoneyFromRat(r)
+
+		stored, ok := new(big.Rat).SetString(r.FloatString(domain.PriceScale))
+		exact := ok && stored.Cmp(r) == 0 && r.Num().IsInt64() && r.Denom().IsInt64()
+		if !exact {
+			return errors.Is(err, domain.ErrPriceNotRepresentable)
+		}
+		return err == nil && m.Amount().Cmp(r) == 0 &&
+			big.NewRat(m.Amount().Num().Int64(), m.Amount().Denom().Int64()).Cmp(r) == 0
+	}
+	require.NoError(t, quick.Check(prop, &quick.Config{MaxCount: 5000}))
+}
+
 func TestMoney_Arithmetic(t *testing.T) {
 	a, _ := domain.NewMoney(2000, 100) // 20.00
 	b, _ := domain.NewMoney(500, 100)  // 5.00
@@ -43,6 +89,24 @@ func TestMoney_Arithmetic(t *testing.T) {
 	assert.Equal(t, "40.00", doubled.String())
 }
 
+func TestMoney_StringKeepsSubCentPrecision(t *testing.T) {
+	for _, tc := range []struct {
+		num, den int64
+		want     string
+	}{
+		{20, 1, "20.00"},
+		{1999, 100, "19.99"},
+		{199, 10, "19.90"},
+		{1, 1000, "0.001"},
+		{4, 1000, "0.004"},
+		{123456789, 1000000000, "0.123456789"},
+	} {
+		m, err := domain.NewMoney(tc.num, tc.den)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, m.String())
+	}
+}
+
 func TestMoney_Equal(t *testing.T) {
 	a, _ := domain.NewMoney(1000, 100)
 	b, _ := domain.NewMoney(10, 1) // same value, different fraction
@@ -73,6 +137,16 @@ func TestNewDiscount_InvalidPercentage(t *testing.T) {
 	assert.ErrorIs(t, err, domain.ErrInvalidDiscountPercent)
 }
 
+func TestNewDiscount_UnrepresentablePercentage(t *testing.T) {
+	start := time.Now()
+	end := start.Add(time.Hour)
+	_, err := domain.NewDiscount(big.NewRat(1, 3), start, end)
+	assert.ErrorIs(t, err, domain.ErrPriceNotRepresentable)
+
+	_, err = domain.NewDiscount(big.NewRat(125, 10), start, end)
+	assert.NoError(t, err)
+}
+
 func TestNewDiscount_InvalidPeriod(t *testing.T) {
 	now := time.Now()
 	_, err := domain.NewDiscount(big.NewRat(10, 1), now, now)
@@ -124,6 +198,47 @@ func TestProduct_UpdateDetails(t *testing.T) {
 	assert.Equal(t, "product.updated", p.DomainEvents()[0].EventType())
 }
 
+func TestProduct_UpdateDetails_RecordsFieldChanges(t *testing.T) {
+	p := activeProduct(t)
+	p.ClearEvents()
+
+	require.NoError(t, p.UpdateDetails("New Name", p.Description(), "toys", time.Now()))
+	require.Len(t, p.DomainEvents(), 1)
+	assert.Equal(t, []domain.FieldChange{
+		{Field: domain.FieldName, Old: "Test Product", New: "New Name"},
+		{Field: domain.FieldCategory, Old: "electronics", New: "toys"},
+	}, p.DomainEvents()[0].Changes())
+}
+
+func TestProduct_UpdateDetails_InvalidLeavesProductUntouched(t *testing.T) {
+	p := activeProduct(t)
+	p.ClearEvents()
+
+	err := p.UpdateDetails("New Name", p.Description(), "", time.Now())
+	assert.ErrorIs(t, err, domain.ErrCategoryRequired)
+	assert.Equal(t, "Test Product", p.Name())
+	assert.False(t, p.Changes().HasChanges())
+}
+
+func TestProduct_StatusAndDiscountChanges(t *testing.T) {
+	p := activeProduct(t)
+	p.ClearEvents()
+	now := time.Now().UTC()
+	discount := validDiscount(t, now)
+
+	require.NoError(t, p.Deactivate(now))
+	require.NoError(t, p.Activate(now))
+	require.NoError(t, p.ApplyDiscount(discount, now))
+	require.NoError(t, p.RemoveDiscount(now))
+
+	events := p.DomainEvents()
+	require.Len(t, events, 4)
+	assert.Equal(t, []domain.FieldChange{{Field: domain.FieldStatus, Old: domain.ProductStatusActive, New: domain.ProductStatusInactive}}, events[0].Changes())
+	assert.Equal(t, []domain.FieldChange{{Field: domain.FieldStatus, Old: domain.ProductStatusInactive, New: domain.ProductStatusActive}}, events[1].Changes())
+	assert.Equal(t, []domain.FieldChange{{Field: domain.FieldDiscount, Old: (*domain.Discount)(nil), New: discount}}, events[2].Changes())
+	assert.Equal(t, []domain.FieldChange{{Field: domain.FieldDiscount, Old: discount, New: (*domain.Discount)(nil)}}, events[3].Changes())
+}
+
 func TestProduct_UpdateDetails_NoChanges(t *testing.T) {
 	p := activeProduct(t)
 	p.ClearEvents()
@@ -162,6 +277,7 @@ func TestProduct_CannotActivateActiveProduct(t *testing.T) {
 
 func TestProduct_Archive(t *testing.T) {
 	p := activeProduct(t)
+	p.ClearEvents()
 	now := time.Now().UTC()
 
 	err := p.Archive(now)
@@ -169,6 +285,18 @@ func TestProduct_Archive(t *testing.T) {
 	assert.Equal(t, domain.ProductStatusArchived, p.Status())
 	assert.NotNil(t, p.ArchivedAt())
 
+	require.Len(t, p.DomainEvents(), 1)
+	event, ok := p.DomainEvents()[0].(*domain.ProductArchivedEvent)
+	require.True(t, ok)
+	assert.Equal(t, "product.archived", event.EventType())
+	assert.Equal(t, p.Version(), event.Sequence())
+	assert.True(t, now.Equal(event.ArchivedAt))
+	assert.Equal(t, []domain.FieldChange{{
+		Field: domain.FieldStatus,
+		Old:   domain.ProductStatusActive,
+		New:   domain.ProductStatusArchived,
+	}}, event.Changes())
+
 	// Cannot perform operations on archived products.
 	assert.ErrorIs(t, p.Activate(now), domain.ErrProductArchived)
 	assert.ErrorIs(t, p.Deactivate(now), domain.ErrProductArchived)
@@ -176,6 +304,94 @@ func TestProduct_Archive(t *testing.T) {
 	assert.ErrorIs(t, p.UpdateDetails("x", "y", "z", now), domain.ErrProductArchived)
 }
 
+// --- Versioning ---
+
+func TestProduct_VersionBumpsOnEveryChange(t *testing.T) {
+	p := activeProduct(t)
+	assert.Equal(t, int64(1), p.Version())
+
+	now := time.Now().UTC()
+	require.NoError(t, p.UpdateDetails("Renamed", p.Description(), p.Category(), now))
+	assert.Equal(t, int64(2), p.Version())
+
+	require.NoError(t, p.Deactivate(now))
+	assert.Equal(t, int64(3), p.Version())
+
+	// A no-op update leaves the version alone.
+	require.NoError(t, p.UpdateDetails(p.Name(), p.Description(), p.Category(), now))
+	assert.Equal(t, int64(3), p.Version())
+}
+
+func TestProduct_EventSequenceFollowsVersion(t *testing.T) {
+	p := activeProduct(t)
+	now := time.Now().UTC()
+
+	require.NoError(t, p.Deactivate(now))
+	require.NoError(t, p.Activate(now))
+	require.NoError(t, p.UpdateDetails("Renamed", p.Description(), p.Category(), now))
+
+	var seqs []int64
+	for _, e := range p.DomainEvents() {
+		seqs = append(seqs, e.Sequence())
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4}, seqs)
+	assert.Equal(t, p.Version(), seqs[len(seqs)-1])
+}
+
+func TestProduct_CheckVersion(t *testing.T) {
+	price, _ := domain.NewMoney(100, 1)
+	now := time.Now().UTC()
+	p := domain.Reconstitute("id", "name", "", "cat", price, nil, domain.ProductStatusActive, now, now, nil, 4)
+
+	assert.NoError(t, p.CheckVersion(0)) // caller opted out
+	assert.NoError(t, p.CheckVersion(4))
+	assert.ErrorIs(t, p.CheckVersion(3), domain.ErrVersionConflict)
+
+	require.NoError(t, p.Deactivate(now))
+	assert.Equal(t, int64(4), p.OriginalVersion())
+	assert.Equal(t, int64(5), p.Version())
+}
+
+// --- Pricing changes ---
+
+func TestProduct_ChangePrice(t *testing.T) {
+	p := activeProduct(t)
+	p.ClearEvents()
+	now := time.Now().UTC()
+	newPrice, _ := domain.NewMoney(2499, 100)
+
+	err := p.ChangePrice(newPrice, now)
+	require.NoError(t, err)
+	assert.Equal(t, "24.99", p.BasePrice().String())
+	assert.True(t, p.Changes().Dirty(domain.FieldBasePrice))
+	require.Len(t, p.DomainEvents(), 1)
+
+	ev, ok := p.DomainEvents()[0].(*domain.PriceChangedEvent)
+	require.True(t, ok)
+	assert.Equal(t, "product.price_changed", ev.EventType())
+	assert.Equal(t, "19.99", ev.OldPrice.String())
+	assert.Equal(t, "24.99", ev.NewPrice.String())
+}
+
+func TestProduct_ChangePrice_SamePriceIsNoop(t *testing.T) {
+	p := activeProduct(t)
+	p.ClearEvents()
+	same, _ := domain.NewMoney(19990, 1000)
+
+	require.NoError(t, p.ChangePrice(same, time.Now()))
+	assert.False(t, p.Changes().HasChanges())
+	assert.Empty(t, p.DomainEvents())
+	assert.Equal(t, int64(1), p.Version())
+}
+
+func TestProduct_ChangePrice_Archived(t *testing.T) {
+	p := activeProduct(t)
+	require.NoError(t, p.Archive(time.Now()))
+	newPrice, _ := domain.NewMoney(1, 1)
+
+	assert.ErrorIs(t, p.ChangePrice(newPrice, time.Now()), domain.ErrProductArchived)
+}
+
 // --- Discount application ---
 
 func TestProduct_ApplyDiscount(t *testing.T) {
@@ -246,6 +462,118 @@ func TestCalculateEffectivePrice_ExpiredDiscount(t *testing.T) {
 	assert.Equal(t, "100.00", result.String())
 }
 
+// --- Replay ---
+
+func TestReplay_RebuildsProductFromItsEvents(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	p := activeProduct(t)
+	require.NoError(t, p.UpdateDetails("Renamed", "Other", "tools", start.Add(time.Minute)))
+	require.NoError(t, p.ApplyDiscount(validDiscount(t, start.Add(2*time.Minute)), start.Add(2*time.Minute)))
+	price, _ := domain.NewMoney(2499, 100)
+	require.NoError(t, p.ChangePrice(price, start.Add(3*time.Minute)))
+	require.NoError(t, p.Deactivate(start.Add(4*time.Minute)))
+	require.NoError(t, p.Archive(start.Add(5*time.Minute)))
+
+	replayed, err := domain.Replay(p.DomainEvents())
+	require.NoError(t, err)
+
+	assert.Equal(t, p.ID(), replayed.ID())
+	assert.Equal(t, p.Name(), replayed.Name())
+	assert.Equal(t, p.Description(), replayed.Description())
+	assert.Equal(t, p.Category(), replayed.Category())
+	assert.True(t, p.BasePrice().Equal(replayed.BasePrice()))
+	require.NotNil(t, replayed.Discount())
+	assert.Zero(t, p.Discount().Percentage().Cmp(replayed.Discount().Percentage()))
+	assert.Equal(t, p.Status(), replayed.Status())
+	assert.Equal(t, p.CreatedAt(), replayed.CreatedAt())
+	assert.Equal(t, p.UpdatedAt(), replayed.UpdatedAt())
+	assert.Equal(t, p.ArchivedAt(), replayed.ArchivedAt())
+	assert.Equal(t, p.Version(), replayed.Version())
+
+	// A replayed product is clean, like one loaded from the table.
+	assert.Equal(t, replayed.Version(), replayed.OriginalVersion())
+	assert.Empty(t, replayed.DomainEvents())
+	assert.False(t, replayed.Changes().HasChanges())
+}
+
+func TestReplay_RejectsBrokenHistory(t *testing.T) {
+	p := activeProduct(t)
+	require.NoError(t, p.Deactivate(time.Now()))
+	require.NoError(t, p.Activate(time.Now()))
+	events := p.DomainEvents()
+
+	_, err := domain.Replay(nil)
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+
+	_, err = domain.Replay(events[1:])
+	assert.ErrorIs(t, err, domain.ErrEventOutOfSequence, "history must start with the created event")
+
+	_, err = domain.Replay([]domain.DomainEvent{events[0], events[2]})
+	assert.ErrorIs(t, err, domain.ErrEventOutOfSequence, "gap")
+
+	_, err = domain.Replay([]domain.DomainEvent{events[0], events[0]})
+	assert.ErrorIs(t, err, domain.ErrEventOutOfSequence, "created twice")
+}
+
+// --- ProductRecord ---
+
+func validRecord() *domain.ProductRecord {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &domain.ProductRecord{
+		ID: "p1", Name: "Widget", Category: "tools",
+		BasePrice: big.NewRat(1999, 100),
+		Status:    string(domain.ProductStatusActive),
+		CreatedAt: now, UpdatedAt: now,
+		Version: 1,
+	}
+}
+
+func fields(vs []domain.Violation) []string {
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = v.Field
+	}
+	return out
+}
+
+func TestProductRecord_RestoreRejectsCorruptRows(t *testing.T) {
+	p, err := validRecord().Restore()
+	require.NoError(t, err)
+	assert.Equal(t, "19.99", p.BasePrice().String())
+
+	rec := validRecord()
+	rec.BasePrice = nil
+	start := rec.CreatedAt
+	rec.DiscountStart = &start // no percentage or end
+	rec.Status = string(domain.ProductStatusArchived)
+	rec.Version = 0
+
+	_, err = rec.Restore()
+	var corrupt *domain.CorruptProductError
+	require.True(t, errors.As(err, &corrupt), "got %v", err)
+	assert.ErrorIs(t, err, domain.ErrCorruptProduct)
+	assert.False(t, errors.Is(err, domain.ErrInvalidPrice), "corruption isn't bad input")
+	assert.Equal(t, "p1", corrupt.ProductID)
+	assert.Equal(t, []string{domain.FieldBasePrice, domain.FieldDiscount, "archived_at", "version"}, fields(corrupt.Violations))
+}
+
+func TestProductRecord_RepairFixesOnlySafeViolations(t *testing.T) {
+	rec := validRecord()
+	rec.DiscountPercent = big.NewRat(150, 1)
+	start, end := rec.CreatedAt, rec.CreatedAt.Add(time.Hour)
+	rec.DiscountStart, rec.DiscountEnd = &start, &end
+	rec.Status = string(domain.ProductStatusArchived)
+	rec.UpdatedAt = rec.CreatedAt.Add(2 * time.Hour)
+	rec.Name = ""
+
+	fixed := rec.Repair()
+	assert.Equal(t, []string{domain.FieldDiscount, "archived_at"}, fields(fixed))
+	assert.Nil(t, rec.DiscountPercent)
+	require.NotNil(t, rec.ArchivedAt)
+	assert.Equal(t, rec.UpdatedAt, *rec.ArchivedAt)
+	assert.Equal(t, []string{domain.FieldName}, fields(rec.Check()), "a missing name needs a person")
+}
+
 // --- helpers ---
 
 func activeProduct(t *testing.T) *domain.Product {
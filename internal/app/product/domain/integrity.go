@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	errIDRequired          = errors.New("product ID is required")
+	errUnknownStatus       = errors.New("status is not active, inactive or archived")
+	errArchivedAtMismatch  = errors.New("must be set exactly when the product is archived")
+	errPartialDiscount     = errors.New("discount percentage, start and end must be set together")
+	errMissingTimestamp    = errors.New("is required")
+	errUpdatedBeforeCreate = errors.New("is before created_at")
+	errInvalidVersion      = errors.New("version must be at least 1")
+)
+
+// ProductRecord is a product as stored, before it is checked against the
+// aggregate's invariants. Repositories fill one from a row and Restore it;
+// integrity tooling reads and repairs them directly. The discount columns
+// are kept apart so a half-written discount reads as one.
+type ProductRecord struct {
+	ID          string
+	Name        string
+	Description string
+	Category    string
+	BasePrice   *big.Rat
+
+	DiscountPercent *big.Rat
+	DiscountStart   *time.Time
+	DiscountEnd     *time.Time
+
+	Status     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ArchivedAt *time.Time
+	Version    int64
+}
+
+// Violation is one invariant a record breaks. Field is a Field* constant
+// or a column name.
+type Violation struct {
+	Field string
+	Err   error
+}
+
+func (v Violation) String() string { return v.Field + ": " + v.Err.Error() }
+
+// CorruptProductError is returned when a stored product can't be turned
+// into an aggregate. It matches ErrCorruptProduct, and deliberately not the
+// validation errors behind it: those describe bad input, this bad data.
+type CorruptProductError struct {
+	ProductID  string
+	Violations []Violation
+}
+
+func (e *CorruptProductError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return fmt.Sprintf("product %s is corrupt: %s", e.ProductID, strings.Join(parts, "; "))
+}
+
+func (e *CorruptProductError) Is(target error) bool { return target == ErrCorruptProduct }
+
+// Check lists every invariant the record breaks, in column order; nil means
+// Restore will succeed.
+func (r *ProductRecord) Check() []Violation {
+	var out []Violation
+	add := func(field string, err error) {
+		out = append(out, Violation{Field: field, Err: err})
+	}
+
+	if r.ID == "" {
+		add("product_id", errIDRequired)
+	}
+	if r.Name == "" {
+		add(FieldName, ErrProductNameRequired)
+	}
+	if r.Category == "" {
+		add(FieldCategory, ErrCategoryRequired)
+	}
+	if _, err := NewMoneyFromRat(r.BasePrice); err != nil {
+		add(FieldBasePrice, err)
+	}
+	if err := r.checkDiscount(); err != nil {
+		add(FieldDiscount, err)
+	}
+
+	switch ProductStatus(r.Status) {
+	case ProductStatusActive, ProductStatusInactive:
+		if r.ArchivedAt != nil {
+			add("archived_at", errArchivedAtMismatch)
+		}
+	case ProductStatusArchived:
+		if r.ArchivedAt == nil {
+			add("archived_at", errArchivedAtMismatch)
+		}
+	default:
+		add(FieldStatus, errUnknownStatus)
+	}
+
+	if r.CreatedAt.IsZero() {
+		add("created_at", errMissingTimestamp)
+	}
+	if r.UpdatedAt.IsZero() {
+		add("updated_at", errMissingTimestamp)
+	} else if r.UpdatedAt.Before(r.CreatedAt) {
+		add("updated_at", errUpdatedBeforeCreate)
+	}
+	if r.Version < 1 {
+		add("version", errInvalidVersion)
+	}
+	return out
+}
+
+func (r *ProductRecord) hasDiscount() bool {
+	return r.DiscountPercent != nil || r.DiscountStart != nil || r.DiscountEnd != nil
+}
+
+func (r *ProductRecord) checkDiscount() error {
+	if !r.hasDiscount() {
+		return nil
+	}
+	if r.DiscountPercent == nil || r.DiscountStart == nil || r.DiscountEnd == nil {
+		return errPartialDiscount
+	}
+	_, err := NewDiscount(r.DiscountPercent, *r.DiscountStart, *r.DiscountEnd)
+	return err
+}
+
+// Repair fixes, in place, the violations that have only one sensible fix,
+// and returns those it fixed:
+//
+//   - A discount that is half-written or fails NewDiscount is cleared.
+//     Loading has always ignored such discounts, so no price changes.
+//   - An archived product without archived_at gets its updated_at, which is
+//     when Archive stamped it: nothing can update an archived product.
+//
+// Everything else needs a person to decide what the row should say.
+func (r *ProductRecord) Repair() []Violation {
+	var fixed []Violation
+	if err := r.checkDiscount(); err != nil {
+		r.DiscountPercent, r.DiscountStart, r.DiscountEnd = nil, nil, nil
+		fixed = append(fixed, Violation{Field: FieldDiscount, Err: err})
+	}
+	if ProductStatus(r.Status) == ProductStatusArchived && r.ArchivedAt == nil && !r.UpdatedAt.IsZero() {
+		at := r.UpdatedAt
+		r.ArchivedAt = &at
+		fixed = append(fixed, Violation{Field: "archived_at", Err: errArchivedAtMismatch})
+	}
+	return fixed
+}
+
+// Pricing checks and builds only the price and discount, for read models
+// that price a product without loading the aggregate. Either one breaking
+// an invariant is a *CorruptProductError, as it would be from Restore.
+func (r *ProductRecord) Pricing() (*Money, *Discount, error) {
+	basePrice, priceErr := NewMoneyFromRat(r.BasePrice)
+	discountErr := r.checkDiscount()
+
+	var violations []Violation
+	if priceErr != nil {
+		violations = append(violations, Violation{Field: FieldBasePrice, Err: priceErr})
+	}
+	if discountErr != nil {
+		violations = append(violations, Violation{Field: FieldDiscount, Err: discountErr})
+	}
+	if len(violations) > 0 {
+		return nil, nil, &CorruptProductError{ProductID: r.ID, Violations: violations}
+	}
+
+	var discount *Discount
+	if r.hasDiscount() {
+		discount, _ = NewDiscount(r.DiscountPercent, *r.DiscountStart, *r.DiscountEnd)
+	}
+	return basePrice, discount, nil
+}
+
+// Restore rebuilds the aggregate like Reconstitute, after checking the
+// record. A record that breaks an invariant is a *CorruptProductError.
+func (r *ProductRecord) Restore() (*Product, error) {
+	if violations := r.Check(); len(violations) > 0 {
+		return nil, &CorruptProductError{ProductID: r.ID, Violations: violations}
+	}
+
+	basePrice, _ := NewMoneyFromRat(r.BasePrice)
+	var discount *Discount
+	if r.hasDiscount() {
+		discount, _ = NewDiscount(r.DiscountPercent, *r.DiscountStart, *r.DiscountEnd)
+	}
+	return Reconstitute(
+		r.ID, r.Name, r.Description, r.Category,
+		basePrice, discount,
+		ProductStatus(r.Status),
+		r.CreatedAt, r.UpdatedAt,
+		r.ArchivedAt,
+		r.Version,
+	), nil
+}
@@ -3,40 +3,58 @@ package domain
 import (
 	"fmt"
 	"math/big"
+	"strings"
 )
 
 // Money wraps *big.Rat for precise monetary calculations.
-// Stored as numerator/denominator in the DB to avoid any floating-point path.
+// Stored as a NUMERIC in the DB to avoid any floating-point path.
 type Money struct {
 	amount *big.Rat
 }
 
+// PriceScale is the number of decimal places a stored price keeps, that of
+// Spanner's NUMERIC.
+const PriceScale = 9
+
+var priceScaleFactor = new(big.Int).Exp(big.NewInt(10), big.NewInt(PriceScale), nil)
+
+// NewMoney and NewMoneyFromRat accept only amounts that are stored exactly:
+// at most PriceScale decimal places, with a reduced numerator that fits an
+// int64 so event payloads carry it exactly too. Anything else is
+// ErrPriceNotRepresentable, rather than being rounded or truncated on the
+// way to the database.
 func NewMoney(numerator, denominator int64) (*Money, error) {
 	if denominator == 0 {
 		return nil, fmt.Errorf("money: denominator cannot be zero")
 	}
-	r := new(big.Rat).SetFrac64(numerator, denominator)
-	if r.Sign() < 0 {
-		return nil, ErrInvalidPrice
-	}
-	return &Money{amount: r}, nil
+	return NewMoneyFromRat(new(big.Rat).SetFrac64(numerator, denominator))
 }
 
 func NewMoneyFromRat(r *big.Rat) (*Money, error) {
 	if r == nil || r.Sign() < 0 {
 		return nil, ErrInvalidPrice
 	}
+	if !Representable(r) {
+		return nil, ErrPriceNotRepresentable
+	}
 	return &Money{amount: new(big.Rat).Set(r)}, nil
 }
 
+// Representable reports whether r survives storage and event encoding
+// unchanged; see NewMoneyFromRat.
+func Representable(r *big.Rat) bool {
+	if !r.Num().IsInt64() {
+		return false
+	}
+	// r has at most PriceScale decimals iff its denominator divides 10^9.
+	return new(big.Int).Mod(priceScaleFactor, r.Denom()).Sign() == 0
+}
+
 // Amount returns a defensive copy of the underlying rational.
 func (m *Money) Amount() *big.Rat {
 	return new(big.Rat).Set(m.amount)
 }
 
-func (m *Money) Numerator() int64  { return m.amount.Num().Int64() }
-func (m *Money) Denominator() int64 { return m.amount.Denom().Int64() }
-
 func (m *Money) Multiply(factor *big.Rat) *Money {
 	return &Money{amount: new(big.Rat).Mul(m.amount, factor)}
 }
@@ -56,7 +74,16 @@ func (m *Money) IsZero() bool {
 	return m.amount.Sign() == 0
 }
 
-// String formats as a two-decimal string, e.g. "19.99".
+// String formats the amount with FormatDecimal, e.g. "19.99" or "0.004".
 func (m *Money) String() string {
-	return m.amount.FloatString(2)
+	return FormatDecimal(m.amount)
+}
+
+// FormatDecimal renders r rounded to PriceScale places, with trailing
+// zeros trimmed but at least two decimals kept, so "20.00" and "0.001"
+// both read naturally. Representable amounts come out exact.
+func FormatDecimal(r *big.Rat) string {
+	s := r.FloatString(PriceScale)
+	minLen := len(s) - PriceScale + 2
+	return s[:minLen] + strings.TrimRight(s[minLen:], "0")
 }
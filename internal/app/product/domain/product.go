@@ -24,6 +24,11 @@ type Product struct {
 	createdAt   time.Time
 	updatedAt   time.Time
 	archivedAt  *time.Time
+	version     int64
+
+	// originalVersion is the version the aggregate had when it was loaded.
+	// The repository writes conditionally against it.
+	originalVersion int64
 
 	changes *ChangeTracker
 	events  []DomainEvent
@@ -47,14 +52,17 @@ func NewProduct(id, name, description, category string, basePrice *Money, now ti
 		status:      ProductStatusActive,
 		createdAt:   now,
 		updatedAt:   now,
+		version:     1,
 		changes:     NewChangeTracker(),
 	}
 
 	p.events = append(p.events, &ProductCreatedEvent{
-		baseEvent: baseEvent{occurredAt: now},
-		ProductID: id,
-		Name:      name,
-		Category:  category,
+		baseEvent:   p.newEvent(now),
+		ProductID:   id,
+		Name:        name,
+		Category:    category,
+		Description: description,
+		BasePrice:   basePrice,
 	})
 
 	return p, nil
@@ -69,6 +77,7 @@ func Reconstitute(
 	status ProductStatus,
 	createdAt, updatedAt time.Time,
 	archivedAt *time.Time,
+	version int64,
 ) *Product {
 	return &Product{
 		id:          id,
@@ -81,7 +90,10 @@ func Reconstitute(
 		createdAt:   createdAt,
 		updatedAt:   updatedAt,
 		archivedAt:  archivedAt,
+		version:     version,
 		changes:     NewChangeTracker(),
+
+		originalVersion: version,
 	}
 }
 
@@ -95,45 +107,69 @@ func (p *Product) Status() ProductStatus   { return p.status }
 func (p *Product) CreatedAt() time.Time    { return p.createdAt }
 func (p *Product) UpdatedAt() time.Time    { return p.updatedAt }
 func (p *Product) ArchivedAt() *time.Time  { return p.archivedAt }
+func (p *Product) Version() int64          { return p.version }
+func (p *Product) OriginalVersion() int64  { return p.originalVersion }
 func (p *Product) Changes() *ChangeTracker { return p.changes }
 
 func (p *Product) DomainEvents() []DomainEvent { return p.events }
 func (p *Product) ClearEvents()                { p.events = nil }
 
+// CheckVersion rejects the operation when the caller edited a stale copy of
+// the product. An expected version of zero skips the check.
+func (p *Product) CheckVersion(expected int64) error {
+	if expected != 0 && expected != p.version {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// newEvent stamps an event with the version the change just produced, so
+// call it after bumping p.version.
+func (p *Product) newEvent(now time.Time, changes ...FieldChange) baseEvent {
+	return baseEvent{occurredAt: now, sequence: p.version, changes: changes}
+}
+
+// record marks field dirty and returns the change for the event.
+func (p *Product) record(field string, old, new interface{}) FieldChange {
+	p.changes.MarkDirty(field)
+	return FieldChange{Field: field, Old: old, New: new}
+}
+
 func (p *Product) UpdateDetails(name, description, category string, now time.Time) error {
 	if p.status == ProductStatusArchived {
 		return ErrProductArchived
 	}
 
-	changed := false
+	if name != p.name && name == "" {
+		return ErrProductNameRequired
+	}
+	if category != p.category && category == "" {
+		return ErrCategoryRequired
+	}
 
+	var changes []FieldChange
 	if name != p.name {
-		if name == "" {
-			return ErrProductNameRequired
-		}
+		changes = append(changes, p.record(FieldName, p.name, name))
 		p.name = name
-		p.changes.MarkDirty(FieldName)
-		changed = true
 	}
 	if description != p.description {
+		changes = append(changes, p.record(FieldDescription, p.description, description))
 		p.description = description
-		p.changes.MarkDirty(FieldDescription)
-		changed = true
 	}
 	if category != p.category {
-		if category == "" {
-			return ErrCategoryRequired
-		}
+		changes = append(changes, p.record(FieldCategory, p.category, category))
 		p.category = category
-		p.changes.MarkDirty(FieldCategory)
-		changed = true
 	}
 
-	if changed {
+	if len(changes) > 0 {
 		p.updatedAt = now
+		p.version++
 		p.events = append(p.events, &ProductUpdatedEvent{
-			baseEvent: baseEvent{occurredAt: now},
-			ProductID: p.id,
+			baseEvent:   p.newEvent(now, changes...),
+			ProductID:   p.id,
+			Name:        p.name,
+			Description: p.description,
+			Category:    p.category,
 		})
 	}
 	return nil
@@ -147,12 +183,13 @@ func (p *Product) Activate(now time.Time) error {
 		return ErrProductAlreadyActive
 	}
 
+	change := p.record(FieldStatus, p.status, ProductStatusActive)
 	p.status = ProductStatusActive
 	p.updatedAt = now
-	p.changes.MarkDirty(FieldStatus)
+	p.version++
 
 	p.events = append(p.events, &ProductActivatedEvent{
-		baseEvent: baseEvent{occurredAt: now},
+		baseEvent: p.newEvent(now, change),
 		ProductID: p.id,
 	})
 	return nil
@@ -166,12 +203,13 @@ func (p *Product) Deactivate(now time.Time) error {
 		return ErrProductAlreadyInactive
 	}
 
+	change := p.record(FieldStatus, p.status, ProductStatusInactive)
 	p.status = ProductStatusInactive
 	p.updatedAt = now
-	p.changes.MarkDirty(FieldStatus)
+	p.version++
 
 	p.events = append(p.events, &ProductDeactivatedEvent{
-		baseEvent: baseEvent{occurredAt: now},
+		baseEvent: p.newEvent(now, change),
 		ProductID: p.id,
 	})
 	return nil
@@ -182,11 +220,46 @@ func (p *Product) Archive(now time.Time) error {
 		return ErrProductArchived
 	}
 
+	change := p.record(FieldStatus, p.status, ProductStatusArchived)
 	p.status = ProductStatusArchived
 	p.updatedAt = now
+	p.version++
 	archived := now
 	p.archivedAt = &archived
-	p.changes.MarkDirty(FieldStatus)
+
+	p.events = append(p.events, &ProductArchivedEvent{
+		baseEvent:  p.newEvent(now, change),
+		ProductID:  p.id,
+		ArchivedAt: archived,
+	})
+	return nil
+}
+
+// ChangePrice reprices the product. Setting the current price again is a
+// no-op, same as an UpdateDetails call that changes nothing.
+func (p *Product) ChangePrice(newPrice *Money, now time.Time) error {
+	if p.status == ProductStatusArchived {
+		return ErrProductArchived
+	}
+	if newPrice == nil {
+		return ErrInvalidPrice
+	}
+	if newPrice.Equal(p.basePrice) {
+		return nil
+	}
+
+	oldPrice := p.basePrice
+	change := p.record(FieldBasePrice, oldPrice, newPrice)
+	p.basePrice = newPrice
+	p.updatedAt = now
+	p.version++
+
+	p.events = append(p.events, &PriceChangedEvent{
+		baseEvent: p.newEvent(now, change),
+		ProductID: p.id,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+	})
 	return nil
 }
 
@@ -198,12 +271,13 @@ func (p *Product) ApplyDiscount(discount *Discount, now time.Time) error {
 		return ErrDiscountNotActive
 	}
 
+	change := p.record(FieldDiscount, p.discount, discount)
 	p.discount = discount
 	p.updatedAt = now
-	p.changes.MarkDirty(FieldDiscount)
+	p.version++
 
 	p.events = append(p.events, &DiscountAppliedEvent{
-		baseEvent:  baseEvent{occurredAt: now},
+		baseEvent:  p.newEvent(now, change),
 		ProductID:  p.id,
 		Percentage: discount.Percentage(),
 		StartDate:  discount.StartDate(),
@@ -217,12 +291,13 @@ func (p *Product) RemoveDiscount(now time.Time) error {
 		return ErrNoActiveDiscount
 	}
 
+	change := p.record(FieldDiscount, p.discount, (*Discount)(nil))
 	p.discount = nil
 	p.updatedAt = now
-	p.changes.MarkDirty(FieldDiscount)
+	p.version++
 
 	p.events = append(p.events, &DiscountRemovedEvent{
-		baseEvent: baseEvent{occurredAt: now},
+		baseEvent: p.newEvent(now, change),
 		ProductID: p.id,
 	})
 	return nil
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Replay rebuilds a product from its event history alone. The events must
+// start with ProductCreatedEvent and have consecutive sequences; the result
+// is clean, as if just loaded, with no pending events.
+func Replay(events []DomainEvent) (*Product, error) {
+	if len(events) == 0 {
+		return nil, ErrProductNotFound
+	}
+	p := &Product{changes: NewChangeTracker()}
+	for _, event := range events {
+		if err := p.Apply(event); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Apply moves the product to the state an already-recorded event describes.
+// Unlike the command methods it doesn't re-check business rules, since the
+// event is a fact, and it neither records events nor marks fields dirty. It
+// does insist that the event is the next one in the aggregate's sequence.
+func (p *Product) Apply(event DomainEvent) error {
+	if event.Sequence() != p.version+1 {
+		return fmt.Errorf("%w: %s has sequence %d, product is at version %d",
+			ErrEventOutOfSequence, event.EventType(), event.Sequence(), p.version)
+	}
+	if _, created := event.(*ProductCreatedEvent); created != (p.version == 0) {
+		return fmt.Errorf("%w: %s at version %d", ErrEventOutOfSequence, event.EventType(), p.version)
+	}
+
+	switch e := event.(type) {
+	case *ProductCreatedEvent:
+		p.id = e.ProductID
+		p.name = e.Name
+		p.description = e.Description
+		p.category = e.Category
+		p.basePrice = e.BasePrice
+		p.status = ProductStatusActive
+		p.createdAt = e.OccurredAt()
+	case *ProductUpdatedEvent:
+		p.name = e.Name
+		p.description = e.Description
+		p.category = e.Category
+	case *ProductActivatedEvent:
+		p.status = ProductStatusActive
+	case *ProductDeactivatedEvent:
+		p.status = ProductStatusInactive
+	case *ProductArchivedEvent:
+		p.status = ProductStatusArchived
+		archived := e.ArchivedAt
+		p.archivedAt = &archived
+	case *DiscountAppliedEvent:
+		discount, err := NewDiscount(e.Percentage, e.StartDate, e.EndDate)
+		if err != nil {
+			return fmt.Errorf("apply %s: %w", e.EventType(), err)
+		}
+		p.discount = discount
+	case *DiscountRemovedEvent:
+		p.discount = nil
+	case *PriceChangedEvent:
+		p.basePrice = e.NewPrice
+	default:
+		return fmt.Errorf("cannot apply domain event %T (%s)", event, event.EventType())
+	}
+
+	p.updatedAt = event.OccurredAt()
+	p.version = event.Sequence()
+	p.originalVersion = p.version
+	return nil
+}
+
+// eventRestorer is implemented by every event through baseEvent.
+type eventRestorer interface {
+	restore(occurredAt time.Time, sequence int64, changes []FieldChange)
+}
+
+func (e *baseEvent) restore(occurredAt time.Time, sequence int64, changes []FieldChange) {
+	e.occurredAt = occurredAt
+	e.sequence = sequence
+	e.changes = changes
+}
+
+// RestoreEvent sets the metadata an event was originally recorded with on
+// one decoded from storage. Used only when replaying persisted events.
+func RestoreEvent(event DomainEvent, occurredAt time.Time, sequence int64, changes []FieldChange) DomainEvent {
+	if r, ok := event.(eventRestorer); ok {
+		r.restore(occurredAt, sequence, changes)
+	}
+	return event
+}
@@ -14,4 +14,5 @@ type ProductDTO struct {
 	Status         string
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	Version        int64
 }
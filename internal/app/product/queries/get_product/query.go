@@ -4,8 +4,6 @@ import (
 	"context"
 
 	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
-	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
-	"github.com/tshubham2/catalog-proj/internal/app/product/domain/services"
 	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
 )
 
@@ -23,20 +21,20 @@ func (h *Handler) Execute(ctx context.Context, productID string) (*ProductDTO, e
 	if err != nil {
 		return nil, err
 	}
-	return h.toDTO(view), nil
+	return h.toDTO(view)
 }
 
-func (h *Handler) toDTO(v *contracts.ProductView) *ProductDTO {
+func (h *Handler) toDTO(v *contracts.ProductView) (*ProductDTO, error) {
 	now := h.clock.Now()
 
-	basePrice, _ := domain.NewMoney(v.BasePriceNumerator, v.BasePriceDenominator)
-
-	var discount *domain.Discount
-	if v.DiscountPercent != nil && v.DiscountStartDate != nil && v.DiscountEndDate != nil {
-		discount, _ = domain.NewDiscount(v.DiscountPercent, *v.DiscountStartDate, *v.DiscountEndDate)
+	basePrice, discount, err := v.Pricing()
+	if err != nil {
+		return nil, err
+	}
+	effectivePrice, err := v.EffectivePrice(basePrice, discount, now)
+	if err != nil {
+		return nil, err
 	}
-
-	effectivePrice := services.CalculateEffectivePrice(basePrice, discount, now)
 
 	dto := &ProductDTO{
 		ID:             v.ID,
@@ -48,6 +46,7 @@ func (h *Handler) toDTO(v *contracts.ProductView) *ProductDTO {
 		Status:         v.Status,
 		CreatedAt:      v.CreatedAt,
 		UpdatedAt:      v.UpdatedAt,
+		Version:        v.Version,
 	}
 
 	if v.DiscountPercent != nil {
@@ -55,5 +54,5 @@ func (h *Handler) toDTO(v *contracts.ProductView) *ProductDTO {
 		dto.DiscountPercent = &pct
 	}
 
-	return dto
+	return dto, nil
 }
@@ -4,8 +4,6 @@ import (
 	"context"
 
 	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
-	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
-	"github.com/tshubham2/catalog-proj/internal/app/product/domain/services"
 	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
 )
 
@@ -44,14 +42,14 @@ func (h *Handler) Execute(ctx context.Context, params Params) (*ListResult, erro
 	}
 
 	for _, v := range views {
-		basePrice, _ := domain.NewMoney(v.BasePriceNumerator, v.BasePriceDenominator)
-
-		var discount *domain.Discount
-		if v.DiscountPercent != nil && v.DiscountStartDate != nil && v.DiscountEndDate != nil {
-			discount, _ = domain.NewDiscount(v.DiscountPercent, *v.DiscountStartDate, *v.DiscountEndDate)
+		basePrice, discount, err := v.Pricing()
+		if err != nil {
+			return nil, err
+		}
+		effectivePrice, err := v.EffectivePrice(basePrice, discount, now)
+		if err != nil {
+			return nil, err
 		}
-
-		effectivePrice := services.CalculateEffectivePrice(basePrice, discount, now)
 
 		result.Products = append(result.Products, ProductSummary{
 			ID:             v.ID,
@@ -0,0 +1,64 @@
+package cached
+
+import (
+	"context"
+
+	"github.com/tshubham2/catalog-proj/internal/models/m_product"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+var _ committer.Committer = (*Committer)(nil)
+
+// Committer drops every product a plan writes from the cache once the plan
+// has committed, so the replica that took a write reads it back straight
+// away. Other replicas still learn about it from the relay. It only helps
+// when the cache reads the tables the plan writes: in front of product_views
+// it would re-cache the view the relay hasn't updated yet.
+type Committer struct {
+	next  committer.Committer
+	cache *ReadModel
+}
+
+// NewCommitter wraps the committer the write usecases use.
+func NewCommitter(next committer.Committer, cache *ReadModel) *Committer {
+	return &Committer{next: next, cache: cache}
+}
+
+func (c *Committer) Apply(ctx context.Context, plan *committer.Plan) error {
+	if err := c.next.Apply(ctx, plan); err != nil {
+		return err
+	}
+	c.invalidate(plan)
+	return nil
+}
+
+func (c *Committer) RunInTransaction(ctx context.Context, fn func(ctx context.Context) (*committer.Plan, error)) error {
+	// fn may run several times; only the plan of the last run commits.
+	var committed *committer.Plan
+	err := c.next.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		plan, err := fn(ctx)
+		committed = plan
+		return plan, err
+	})
+	if err != nil {
+		return err
+	}
+	c.invalidate(committed)
+	return nil
+}
+
+func (c *Committer) invalidate(plan *committer.Plan) {
+	if plan == nil {
+		return
+	}
+	for _, m := range plan.Mutations() {
+		if m.Table != m_product.Table {
+			continue
+		}
+		if id, ok := m.Value(m_product.ProductID); ok {
+			if id, ok := id.(string); ok {
+				c.cache.InvalidateProduct(id)
+			}
+		}
+	}
+}
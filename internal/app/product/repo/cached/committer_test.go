@@ -0,0 +1,84 @@
+package cached_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/cached"
+	"github.com/tshubham2/catalog-proj/internal/models/m_product"
+	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+// fakeCommitter commits nothing and fails with err; RunInTransaction runs
+// fn runs times, as a backend retrying aborted transactions would.
+type fakeCommitter struct {
+	err  error
+	runs int
+}
+
+func (f *fakeCommitter) Apply(context.Context, *committer.Plan) error { return f.err }
+
+func (f *fakeCommitter) RunInTransaction(ctx context.Context, fn func(ctx context.Context) (*committer.Plan, error)) error {
+	for i := 0; i < f.runs; i++ {
+		if _, err := fn(ctx); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+func renamePlan(id string) *committer.Plan {
+	plan := committer.NewPlan()
+	plan.Add(m_product.New().UpdateMap(id, map[string]interface{}{m_product.Name: "Gadget"}))
+	return plan
+}
+
+func TestCommitter_InvalidatesWrittenProducts(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake("p1", "p2")
+	c := cached.New(fake, &clock.FixedClock{T: t0}, cached.Config{})
+	for _, id := range []string{"p1", "p2"} {
+		_, err := c.GetByID(ctx, id)
+		require.NoError(t, err)
+	}
+	fake.set(&contracts.ProductView{ID: "p1", Name: "Gadget", BasePrice: big.NewRat(1, 1), Version: 2})
+
+	cm := cached.NewCommitter(&fakeCommitter{runs: 2}, c)
+	require.NoError(t, cm.Apply(ctx, renamePlan("p1")))
+	v, err := c.GetByID(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, "Gadget", v.Name)
+
+	require.NoError(t, cm.RunInTransaction(ctx, func(context.Context) (*committer.Plan, error) {
+		return renamePlan("p2"), nil
+	}))
+	_, err = c.GetByID(ctx, "p2")
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, fake.calls.Load(), "both writes reloaded their product")
+}
+
+func TestCommitter_KeepsProductWhenCommitFails(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake("p1")
+	c := cached.New(fake, &clock.FixedClock{T: t0}, cached.Config{})
+	_, err := c.GetByID(ctx, "p1")
+	require.NoError(t, err)
+
+	aborted := errors.New("aborted")
+	cm := cached.NewCommitter(&fakeCommitter{err: aborted, runs: 1}, c)
+	assert.ErrorIs(t, cm.Apply(ctx, renamePlan("p1")), aborted)
+	assert.ErrorIs(t, cm.RunInTransaction(ctx, func(context.Context) (*committer.Plan, error) {
+		return renamePlan("p1"), nil
+	}), aborted)
+
+	_, err = c.GetByID(ctx, "p1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, fake.calls.Load())
+}
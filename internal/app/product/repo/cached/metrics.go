@@ -0,0 +1,18 @@
+package cached
+
+import "expvar"
+
+// Cache counters, published under /debug/vars as "product_cache": hits,
+// misses, evictions (pushed out by Size), expirations (older than TTL),
+// invalidations (dropped for an outbox event) and size.
+var (
+	cacheMetrics = expvar.NewMap("product_cache")
+	cacheSize    = new(expvar.Int)
+)
+
+func init() {
+	for _, name := range []string{"hits", "misses", "evictions", "expirations", "invalidations"} {
+		cacheMetrics.Add(name, 0)
+	}
+	cacheMetrics.Set("size", cacheSize)
+}
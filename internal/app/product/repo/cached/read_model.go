@@ -0,0 +1,249 @@
+// Package cached keeps recently read products in process memory in front
+// of another ProductReadModel. It caches the stored view rather than the
+// query result, so effective prices are still computed on every read and a
+// discount starts and ends on time however long its product stays cached.
+package cached
+
+import (
+	"container/list"
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+)
+
+type Config struct {
+	// Size is how many products are kept; the least recently used one is
+	// evicted to make room.
+	Size int
+	// TTL bounds how long a product is served from the cache. Invalidation
+	// only reaches the replica that took the write and the one whose relay
+	// delivered its event, so on the others TTL is how stale a read can be.
+	TTL time.Duration
+	// LoadTimeout bounds one read from the wrapped read model. A load is
+	// shared by every caller waiting on the product, so it doesn't run on
+	// any one caller's context.
+	LoadTimeout time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{Size: 10000, TTL: 30 * time.Second, LoadTimeout: 5 * time.Second}
+}
+
+// withDefaults fills zero fields from DefaultConfig.
+func (c Config) withDefaults() Config {
+	def := DefaultConfig()
+	if c.Size <= 0 {
+		c.Size = def.Size
+	}
+	if c.TTL <= 0 {
+		c.TTL = def.TTL
+	}
+	if c.LoadTimeout <= 0 {
+		c.LoadTimeout = def.LoadTimeout
+	}
+	return c
+}
+
+var _ contracts.ProductReadModel = (*ReadModel)(nil)
+
+// ReadModel caches GetByID of the read model it wraps. Concurrent misses
+// for one product share a single load. Lists are always read through:
+// which products a page holds changes with any write, not just one
+// product's.
+//
+// The relay runs Invalidate on each event before publishing it. In front of
+// products, Committer also drops what a commit wrote on the replica that
+// made it, so that replica reads its own writes.
+type ReadModel struct {
+	next  contracts.ProductReadModel
+	clock clock.Clock
+	cfg   Config
+	loads singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // front is most recently used
+	// loading counts the loads under way per product, and invalidated the
+	// invalidations of each while any is, so a load that raced one isn't
+	// stored over it. Both only hold products being loaded.
+	loading     map[string]int
+	invalidated map[string]uint64
+}
+
+type entry struct {
+	id      string
+	view    *contracts.ProductView
+	expires time.Time
+}
+
+// New wraps next; zero fields in cfg take their DefaultConfig value.
+func New(next contracts.ProductReadModel, clk clock.Clock, cfg Config) *ReadModel {
+	return &ReadModel{
+		next:        next,
+		clock:       clk,
+		cfg:         cfg.withDefaults(),
+		entries:     map[string]*list.Element{},
+		lru:         list.New(),
+		loading:     map[string]int{},
+		invalidated: map[string]uint64{},
+	}
+}
+
+func (c *ReadModel) GetByID(ctx context.Context, id string) (*contracts.ProductView, error) {
+	if view, ok := c.get(id); ok {
+		cacheMetrics.Add("hits", 1)
+		return view, nil
+	}
+	cacheMetrics.Add("misses", 1)
+
+	// The load outlives a caller that gives up on it, since others may be
+	// waiting on it too; each caller stops waiting when its own ctx ends.
+	loaded := c.loads.DoChan(id, func() (interface{}, error) {
+		generation := c.startLoad(id)
+		defer c.endLoad(id)
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.cfg.LoadTimeout)
+		defer cancel()
+		view, err := c.next.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		c.put(id, view, generation)
+		return view, nil
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-loaded:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return clone(res.Val.(*contracts.ProductView)), nil
+	}
+}
+
+func (c *ReadModel) ListActive(ctx context.Context, pageSize int, pageToken string, category string) ([]*contracts.ProductView, string, error) {
+	return c.next.ListActive(ctx, pageSize, pageToken, category)
+}
+
+// Invalidate drops the event's product. Its signature matches
+// outbox.Handler; it never fails.
+func (c *ReadModel) Invalidate(_ context.Context, event contracts.OutboxEvent) error {
+	c.InvalidateProduct(event.AggregateID)
+	return nil
+}
+
+// InvalidateProduct drops one product, and keeps loads already under way
+// from storing what they read.
+func (c *ReadModel) InvalidateProduct(id string) {
+	// A load already under way may have read the old row; make the next
+	// reader start a fresh one instead of joining it.
+	c.loads.Forget(id)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.loading[id] > 0 {
+		c.invalidated[id]++
+	}
+	if el, ok := c.entries[id]; ok {
+		c.remove(el)
+		cacheMetrics.Add("invalidations", 1)
+	}
+}
+
+func (c *ReadModel) get(id string) (*contracts.ProductView, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[id]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !c.clock.Now().Before(e.expires) {
+		c.remove(el)
+		cacheMetrics.Add("expirations", 1)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return clone(e.view), true
+}
+
+// startLoad registers a load of id and returns the product's invalidation
+// count to hand to put.
+func (c *ReadModel) startLoad(id string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loading[id]++
+	return c.invalidated[id]
+}
+
+// endLoad forgets id's invalidations once no load of it is left to race
+// them.
+func (c *ReadModel) endLoad(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.loading[id]--; c.loading[id] == 0 {
+		delete(c.loading, id)
+		delete(c.invalidated, id)
+	}
+}
+
+// put stores a view of id loaded at generation, unless id has been
+// invalidated since: the view may predate the write behind that.
+func (c *ReadModel) put(id string, view *contracts.ProductView, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.invalidated[id] != generation {
+		return
+	}
+	e := &entry{id: id, view: clone(view), expires: c.clock.Now().Add(c.cfg.TTL)}
+	if el, ok := c.entries[id]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[id] = c.lru.PushFront(e)
+	for c.lru.Len() > c.cfg.Size {
+		c.remove(c.lru.Back())
+		cacheMetrics.Add("evictions", 1)
+	}
+	cacheSize.Set(int64(c.lru.Len()))
+}
+
+func (c *ReadModel) remove(el *list.Element) {
+	delete(c.entries, el.Value.(*entry).id)
+	c.lru.Remove(el)
+	cacheSize.Set(int64(c.lru.Len()))
+}
+
+// clone copies a view deeply, so callers can't change a cached one.
+func clone(v *contracts.ProductView) *contracts.ProductView {
+	out := *v
+	out.BasePrice = cloneRat(v.BasePrice)
+	out.DiscountPercent = cloneRat(v.DiscountPercent)
+	out.DiscountedPrice = cloneRat(v.DiscountedPrice)
+	out.DiscountStartDate = cloneTime(v.DiscountStartDate)
+	out.DiscountEndDate = cloneTime(v.DiscountEndDate)
+	return &out
+}
+
+func cloneRat(r *big.Rat) *big.Rat {
+	if r == nil {
+		return nil
+	}
+	return new(big.Rat).Set(r)
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := *t
+	return &u
+}
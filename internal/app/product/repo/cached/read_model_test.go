@@ -0,0 +1,310 @@
+package cached_test
+
+import (
+	"context"
+	"expvar"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/app/product/queries/get_product"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/cached"
+	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+)
+
+var t0 = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeReadModel serves views from a map and counts GetByID calls. When
+// block is set, each call waits for it after signalling entered.
+type fakeReadModel struct {
+	mu      sync.Mutex
+	views   map[string]*contracts.ProductView
+	calls   atomic.Int32
+	entered chan struct{}
+	block   chan struct{}
+}
+
+func newFake(ids ...string) *fakeReadModel {
+	f := &fakeReadModel{views: map[string]*contracts.ProductView{}}
+	for _, id := range ids {
+		f.set(&contracts.ProductView{ID: id, Name: "Widget", BasePrice: big.NewRat(1999, 100), Version: 1})
+	}
+	return f
+}
+
+func (f *fakeReadModel) set(v *contracts.ProductView) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.views[v.ID] = v
+}
+
+func (f *fakeReadModel) GetByID(_ context.Context, id string) (*contracts.ProductView, error) {
+	f.calls.Add(1)
+	f.mu.Lock()
+	v, ok := f.views[id]
+	f.mu.Unlock()
+	if f.block != nil {
+		f.entered <- struct{}{}
+		<-f.block
+	}
+	if !ok {
+		return nil, domain.ErrProductNotFound
+	}
+	out := *v
+	return &out, nil
+}
+
+func (f *fakeReadModel) ListActive(context.Context, int, string, string) ([]*contracts.ProductView, string, error) {
+	return nil, "", nil
+}
+
+func counter(name string) int64 {
+	return expvar.Get("product_cache").(*expvar.Map).Get(name).(*expvar.Int).Value()
+}
+
+func TestReadModel_HitsAfterFirstRead(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake("p1")
+	c := cached.New(fake, &clock.FixedClock{T: t0}, cached.Config{})
+	hits, misses := counter("hits"), counter("misses")
+
+	for i := 0; i < 3; i++ {
+		v, err := c.GetByID(ctx, "p1")
+		require.NoError(t, err)
+		assert.Equal(t, "Widget", v.Name)
+	}
+	assert.EqualValues(t, 1, fake.calls.Load())
+	assert.Equal(t, hits+2, counter("hits"))
+	assert.Equal(t, misses+1, counter("misses"))
+}
+
+func TestReadModel_DoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake()
+	c := cached.New(fake, &clock.FixedClock{T: t0}, cached.Config{})
+
+	_, err := c.GetByID(ctx, "p1")
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	fake.set(&contracts.ProductView{ID: "p1", BasePrice: big.NewRat(1, 1)})
+	_, err = c.GetByID(ctx, "p1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, fake.calls.Load())
+}
+
+func TestReadModel_ReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	c := cached.New(newFake("p1"), &clock.FixedClock{T: t0}, cached.Config{})
+
+	v, err := c.GetByID(ctx, "p1")
+	require.NoError(t, err)
+	v.Name = "changed"
+	v.BasePrice.SetInt64(0)
+
+	v, err = c.GetByID(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, "Widget", v.Name)
+	assert.Equal(t, "19.99", v.BasePrice.FloatString(2))
+}
+
+func TestReadModel_ExpiresAfterTTL(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake("p1")
+	clk := &clock.FixedClock{T: t0}
+	c := cached.New(fake, clk, cached.Config{TTL: time.Minute})
+	expirations := counter("expirations")
+
+	_, err := c.GetByID(ctx, "p1")
+	require.NoError(t, err)
+	clk.T = t0.Add(59 * time.Second)
+	_, err = c.GetByID(ctx, "p1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, fake.calls.Load())
+
+	clk.T = t0.Add(time.Minute)
+	_, err = c.GetByID(ctx, "p1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, fake.calls.Load())
+	assert.Equal(t, expirations+1, counter("expirations"))
+}
+
+func TestReadModel_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake("p1", "p2", "p3")
+	c := cached.New(fake, &clock.FixedClock{T: t0}, cached.Config{Size: 2})
+	evictions := counter("evictions")
+
+	for _, id := range []string{"p1", "p2", "p1", "p3"} {
+		_, err := c.GetByID(ctx, id)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 3, fake.calls.Load())
+	assert.Equal(t, evictions+1, counter("evictions"))
+
+	_, err := c.GetByID(ctx, "p1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, fake.calls.Load(), "p1 was used more recently than p2")
+	_, err = c.GetByID(ctx, "p2")
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, fake.calls.Load())
+}
+
+func TestReadModel_InvalidateDropsProduct(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake("p1", "p2")
+	c := cached.New(fake, &clock.FixedClock{T: t0}, cached.Config{})
+	for _, id := range []string{"p1", "p2"} {
+		_, err := c.GetByID(ctx, id)
+		require.NoError(t, err)
+	}
+
+	fake.set(&contracts.ProductView{ID: "p1", Name: "Gadget", BasePrice: big.NewRat(1, 1), Version: 2})
+	require.NoError(t, c.Invalidate(ctx, contracts.OutboxEvent{AggregateID: "p1"}))
+
+	v, err := c.GetByID(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, "Gadget", v.Name)
+	_, err = c.GetByID(ctx, "p2")
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, fake.calls.Load(), "only p1 is reloaded")
+}
+
+func TestReadModel_CoalescesConcurrentMisses(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake("p1")
+	fake.entered, fake.block = make(chan struct{}, 1), make(chan struct{})
+	c := cached.New(fake, &clock.FixedClock{T: t0}, cached.Config{})
+	misses := counter("misses")
+
+	const readers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.GetByID(ctx, "p1")
+			assert.NoError(t, err)
+			assert.Equal(t, "Widget", v.Name)
+		}()
+	}
+	<-fake.entered
+	require.Eventually(t, func() bool { return counter("misses") == misses+readers }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond) // let the last reader join the load
+	close(fake.block)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, fake.calls.Load())
+}
+
+func TestReadModel_DoesNotStoreLoadThatRacedInvalidation(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake("p1")
+	fake.entered, fake.block = make(chan struct{}, 1), make(chan struct{})
+	c := cached.New(fake, &clock.FixedClock{T: t0}, cached.Config{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		v, err := c.GetByID(ctx, "p1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Widget", v.Name)
+	}()
+	<-fake.entered
+	fake.set(&contracts.ProductView{ID: "p1", Name: "Gadget", BasePrice: big.NewRat(1, 1), Version: 2})
+	require.NoError(t, c.Invalidate(ctx, contracts.OutboxEvent{AggregateID: "p1"}))
+	close(fake.block)
+	<-done
+
+	fake.block = nil
+	v, err := c.GetByID(ctx, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, "Gadget", v.Name)
+	assert.EqualValues(t, 2, fake.calls.Load())
+}
+
+func TestReadModel_InvalidatingAnotherProductKeepsLoad(t *testing.T) {
+	ctx := context.Background()
+	fake := newFake("p1")
+	fake.entered, fake.block = make(chan struct{}, 1), make(chan struct{})
+	c := cached.New(fake, &clock.FixedClock{T: t0}, cached.Config{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := c.GetByID(ctx, "p1")
+		assert.NoError(t, err)
+	}()
+	<-fake.entered
+	c.InvalidateProduct("p2")
+	close(fake.block)
+	<-done
+
+	fake.block = nil
+	_, err := c.GetByID(ctx, "p1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, fake.calls.Load(), "p1 was stored")
+}
+
+// The cache holds the stored product, so the discount is applied by when
+// it is read, not by when it was cached.
+func TestReadModel_EffectivePriceFollowsDiscountWindow(t *testing.T) {
+	ctx := context.Background()
+	start, end := t0.Add(time.Hour), t0.Add(2*time.Hour)
+	fake := newFake()
+	fake.set(&contracts.ProductView{
+		ID: "p1", BasePrice: big.NewRat(20, 1), DiscountPercent: big.NewRat(25, 1),
+		DiscountStartDate: &start, DiscountEndDate: &end, Version: 2,
+	})
+	clk := &clock.FixedClock{T: t0}
+	q := get_product.NewHandler(cached.New(fake, clk, cached.Config{TTL: 24 * time.Hour}), clk)
+
+	for _, tc := range []struct {
+		at   time.Time
+		want string
+	}{
+		{t0, "20.00"},
+		{start.Add(time.Minute), "15.00"},
+		{end.Add(time.Minute), "20.00"},
+	} {
+		clk.T = tc.at
+		dto, err := q.Execute(ctx, "p1")
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, dto.EffectivePrice, "at %s", tc.at)
+	}
+	assert.EqualValues(t, 1, fake.calls.Load())
+}
+
+func TestReadModel_CancelledCallerDoesNotFailSharedLoad(t *testing.T) {
+	fake := newFake("p1")
+	fake.entered, fake.block = make(chan struct{}, 1), make(chan struct{})
+	c := cached.New(fake, &clock.FixedClock{T: t0}, cached.Config{})
+
+	first, cancel := context.WithCancel(context.Background())
+	failed := make(chan error)
+	go func() {
+		_, err := c.GetByID(first, "p1")
+		failed <- err
+	}()
+	<-fake.entered
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		v, err := c.GetByID(context.Background(), "p1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Widget", v.Name)
+	}()
+	time.Sleep(10 * time.Millisecond) // let the second reader join the load
+	cancel()
+	assert.ErrorIs(t, <-failed, context.Canceled)
+	close(fake.block)
+	<-done
+
+	assert.EqualValues(t, 1, fake.calls.Load())
+}
@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/spanner"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+)
+
+var _ contracts.ProductEventStore = (*EventStore)(nil)
+
+// EventStore replays products from outbox_events. Every status counts: an
+// event is part of the history from the moment it's committed, whether or
+// not it has been delivered.
+type EventStore struct {
+	outbox AggregateEvents
+	decode contracts.EventDecoder
+}
+
+// AggregateEvents is the part of an outbox repository EventStore reads
+// from. Every backend's outbox repository has it.
+type AggregateEvents interface {
+	FindByAggregate(ctx context.Context, aggregateID string) ([]contracts.OutboxEvent, error)
+}
+
+func NewEventStore(client *spanner.Client, decode contracts.EventDecoder) *EventStore {
+	return NewEventStoreOn(NewOutboxRepo(client), decode)
+}
+
+// NewEventStoreOn replays from another backend's outbox repository.
+func NewEventStoreOn(outbox AggregateEvents, decode contracts.EventDecoder) *EventStore {
+	return &EventStore{outbox: outbox, decode: decode}
+}
+
+func (s *EventStore) LoadFromEvents(ctx context.Context, productID string) (*domain.Product, error) {
+	rows, err := s.outbox.FindByAggregate(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, domain.ErrProductNotFound
+	}
+
+	events := make([]domain.DomainEvent, 0, len(rows))
+	for _, row := range rows {
+		event, err := s.decode(row)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", contracts.ErrHistoryUnreplayable, err)
+		}
+		events = append(events, event)
+	}
+	p, err := domain.Replay(events)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", contracts.ErrHistoryUnreplayable, err)
+	}
+	return p, nil
+}
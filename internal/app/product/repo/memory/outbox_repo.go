@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/writes"
+	"github.com/tshubham2/catalog-proj/internal/models/m_outbox"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/commitplan"
+	memdriver "github.com/tshubham2/commitplan/drivers/memory"
+)
+
+var (
+	_ contracts.OutboxRepository = (*OutboxRepo)(nil)
+	_ contracts.OutboxStore      = (*OutboxRepo)(nil)
+	_ contracts.DeadLetterStore  = (*OutboxRepo)(nil)
+
+	_ contracts.OutboxRetentionStore = (*OutboxRepo)(nil)
+)
+
+// OutboxRepo mirrors the Spanner outbox repository's semantics: per-aggregate
+// ordering, leases and dead letters behave the same.
+type OutboxRepo struct {
+	writes.Outbox
+	store     *Store
+	committer committer.Committer
+}
+
+func NewOutboxRepo(store *Store) *OutboxRepo {
+	return &OutboxRepo{Outbox: writes.NewOutbox(), store: store, committer: NewCommitter(store)}
+}
+
+// outboxRow is an OutboxEvent plus the relay's bookkeeping columns.
+type outboxRow struct {
+	event          contracts.OutboxEvent
+	nextAttemptAt  *time.Time
+	claimedBy      string
+	leaseExpiresAt *time.Time
+}
+
+func toOutboxRow(row memdriver.Row) outboxRow {
+	e := contracts.OutboxEvent{
+		ID:          str(row, m_outbox.EventID),
+		EventType:   str(row, m_outbox.EventType),
+		AggregateID: str(row, m_outbox.AggregateID),
+		Sequence:    i64(row, m_outbox.Sequence),
+		Payload:     json.RawMessage(str(row, m_outbox.Payload)),
+		CreatedAt:   timestamp(row, m_outbox.CreatedAt),
+		Status:      str(row, m_outbox.Status),
+		ProcessedAt: nullTime(row, m_outbox.ProcessedAt),
+		Attempts:    i64(row, m_outbox.Attempts),
+		LastError:   str(row, m_outbox.LastError),
+	}
+	if metadata := str(row, m_outbox.Metadata); metadata != "" {
+		// Best effort, as in the Spanner repository.
+		_ = json.Unmarshal([]byte(metadata), &e.Metadata)
+	}
+	return outboxRow{
+		event:          e,
+		nextAttemptAt:  nullTime(row, m_outbox.NextAttemptAt),
+		claimedBy:      str(row, m_outbox.ClaimedBy),
+		leaseExpiresAt: nullTime(row, m_outbox.LeaseExpiresAt),
+	}
+}
+
+// ListClaimable applies the same rules as the Spanner query: due, unleased
+// and pending, with no unsettled earlier event of the same aggregate.
+func (r *OutboxRepo) ListClaimable(_ context.Context, now time.Time, limit int) ([]contracts.OutboxEvent, error) {
+	rows := r.rows()
+	var out []contracts.OutboxEvent
+	for _, row := range rows {
+		e := row.event
+		if e.Status != contracts.OutboxStatusPending ||
+			(row.nextAttemptAt != nil && row.nextAttemptAt.After(now)) ||
+			(row.leaseExpiresAt != nil && row.leaseExpiresAt.After(now)) {
+			continue
+		}
+		if !blocked(rows, e) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// blocked reports whether an earlier event of e's aggregate is still
+// pending or dead-lettered.
+func blocked(rows []outboxRow, e contracts.OutboxEvent) bool {
+	for _, prior := range rows {
+		p := prior.event
+		if p.AggregateID != e.AggregateID ||
+			(p.Status != contracts.OutboxStatusPending && p.Status != contracts.OutboxStatusDeadLetter) {
+			continue
+		}
+		if p.Sequence < e.Sequence || (p.Sequence == e.Sequence && p.CreatedAt.Before(e.CreatedAt)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *OutboxRepo) ClaimOwner(_ context.Context, eventID string) (string, error) {
+	row, found := r.store.db.Get(m_outbox.Table, commitplan.KeyOf(m_outbox.EventID, eventID))
+	if !found {
+		return "", fmt.Errorf("memory: outbox event %q not found", eventID)
+	}
+	return str(row, m_outbox.ClaimedBy), nil
+}
+
+func (r *OutboxRepo) ListDeadLetters(_ context.Context, pageSize int, pageToken string) ([]contracts.OutboxEvent, string, error) {
+	events := r.filter(func(e contracts.OutboxEvent) bool {
+		return e.Status == contracts.OutboxStatusDeadLetter && e.ID > pageToken
+	})
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+
+	var nextToken string
+	if len(events) > pageSize {
+		nextToken = events[pageSize-1].ID
+		events = events[:pageSize]
+	}
+	return events, nextToken, nil
+}
+
+func (r *OutboxRepo) FindDeadLetter(_ context.Context, eventID string) (*contracts.OutboxEvent, error) {
+	events := r.filter(func(e contracts.OutboxEvent) bool {
+		return e.ID == eventID && e.Status == contracts.OutboxStatusDeadLetter
+	})
+	if len(events) == 0 {
+		return nil, contracts.ErrOutboxEventNotFound
+	}
+	return &events[0], nil
+}
+
+func (r *OutboxRepo) FindDeadLettersByAggregate(_ context.Context, aggregateID string) ([]contracts.OutboxEvent, error) {
+	events := r.filter(func(e contracts.OutboxEvent) bool {
+		return e.AggregateID == aggregateID && e.Status == contracts.OutboxStatusDeadLetter
+	})
+	sortBySequence(events)
+	return events, nil
+}
+
+// FindByAggregate returns every event of an aggregate in sequence order,
+// whatever its delivery status.
+func (r *OutboxRepo) FindByAggregate(_ context.Context, aggregateID string) ([]contracts.OutboxEvent, error) {
+	events := r.filter(func(e contracts.OutboxEvent) bool { return e.AggregateID == aggregateID })
+	sortBySequence(events)
+	return events, nil
+}
+
+func (r *OutboxRepo) OldestSettled(_ context.Context, status string) (time.Time, bool, error) {
+	var (
+		oldest time.Time
+		ok     bool
+	)
+	for _, e := range r.filter(func(e contracts.OutboxEvent) bool { return e.Status == status && e.ProcessedAt != nil }) {
+		if !ok || e.ProcessedAt.Before(oldest) {
+			oldest, ok = *e.ProcessedAt, true
+		}
+	}
+	return oldest, ok, nil
+}
+
+// PurgeSettled finds and deletes the expired events in one transaction.
+func (r *OutboxRepo) PurgeSettled(ctx context.Context, status string, before time.Time) (int64, error) {
+	var n int64
+	err := r.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		plan := committer.NewPlan()
+		n = 0
+		for _, e := range r.filter(func(e contracts.OutboxEvent) bool {
+			return e.Status == status && e.ProcessedAt != nil && e.ProcessedAt.Before(before)
+		}) {
+			plan.Add(r.DeleteMut(e.ID))
+			n++
+		}
+		return plan, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *OutboxRepo) rows() []outboxRow {
+	var rows []outboxRow
+	r.store.db.Scan(m_outbox.Table, func(row memdriver.Row) bool {
+		rows = append(rows, toOutboxRow(row))
+		return true
+	})
+	return rows
+}
+
+func (r *OutboxRepo) filter(keep func(contracts.OutboxEvent) bool) []contracts.OutboxEvent {
+	var out []contracts.OutboxEvent
+	for _, row := range r.rows() {
+		if keep(row.event) {
+			out = append(out, row.event)
+		}
+	}
+	return out
+}
+
+func sortBySequence(events []contracts.OutboxEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Sequence != events[j].Sequence {
+			return events[i].Sequence < events[j].Sequence
+		}
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+}
@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/writes"
+	"github.com/tshubham2/catalog-proj/internal/models/m_product"
+	"github.com/tshubham2/catalog-proj/internal/models/m_product_view"
+	"github.com/tshubham2/commitplan"
+	memdriver "github.com/tshubham2/commitplan/drivers/memory"
+)
+
+var (
+	_ contracts.ProductRepository  = (*ProductRepo)(nil)
+	_ contracts.ProductRecordStore = (*ProductRepo)(nil)
+)
+
+// ProductRepo writes the same mutations as the Spanner repository and reads
+// the rows they leave in the store.
+type ProductRepo struct {
+	writes.Products
+	store *Store
+}
+
+func NewProductRepo(store *Store) *ProductRepo {
+	return &ProductRepo{Products: writes.NewProducts(), store: store}
+}
+
+func (r *ProductRepo) FindByID(_ context.Context, id string) (*domain.Product, error) {
+	row, found := r.store.db.Get(m_product.Table, commitplan.KeyOf(m_product.ProductID, id))
+	if !found {
+		return nil, domain.ErrProductNotFound
+	}
+	return toRecord(row).Restore()
+}
+
+// FindRecord reads a product as stored, without checking it.
+func (r *ProductRepo) FindRecord(_ context.Context, id string) (*domain.ProductRecord, error) {
+	row, found := r.store.db.Get(m_product.Table, commitplan.KeyOf(m_product.ProductID, id))
+	if !found {
+		return nil, domain.ErrProductNotFound
+	}
+	return toRecord(row), nil
+}
+
+func (r *ProductRepo) ListIDs(_ context.Context, after string, limit int) ([]string, error) {
+	var ids []string
+	r.store.db.Scan(m_product.Table, func(row memdriver.Row) bool {
+		if id := str(row, m_product.ProductID); id > after {
+			ids = append(ids, id)
+		}
+		return true
+	})
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+func toRecord(row memdriver.Row) *domain.ProductRecord {
+	return &domain.ProductRecord{
+		ID:              str(row, m_product.ProductID),
+		Name:            str(row, m_product.Name),
+		Description:     str(row, m_product.Description),
+		Category:        str(row, m_product.Category),
+		BasePrice:       numeric(row, m_product.BasePrice),
+		DiscountPercent: numeric(row, m_product.DiscountPercent),
+		DiscountStart:   nullTime(row, m_product.DiscountStartDate),
+		DiscountEnd:     nullTime(row, m_product.DiscountEndDate),
+		Status:          str(row, m_product.Status),
+		CreatedAt:       timestamp(row, m_product.CreatedAt),
+		UpdatedAt:       timestamp(row, m_product.UpdatedAt),
+		ArchivedAt:      nullTime(row, m_product.ArchivedAt),
+		Version:         i64(row, m_product.Version),
+	}
+}
+
+// ProductReadModel serves the query side from the same Store.
+
+var _ contracts.ProductReadModel = (*ProductReadModel)(nil)
+
+type ProductReadModel struct {
+	store *Store
+	// table is products, or product_views for a ProductViewRepo, whose rows
+	// toView decodes.
+	table  string
+	toView func(row memdriver.Row) *contracts.ProductView
+}
+
+func NewProductReadModel(store *Store) *ProductReadModel {
+	return &ProductReadModel{store: store, table: m_product.Table, toView: toView}
+}
+
+func (rm *ProductReadModel) GetByID(_ context.Context, id string) (*contracts.ProductView, error) {
+	row, found := rm.store.db.Get(rm.table, commitplan.KeyOf(m_product.ProductID, id))
+	if !found {
+		return nil, domain.ErrProductNotFound
+	}
+	return rm.toView(row), nil
+}
+
+func (rm *ProductReadModel) ListActive(_ context.Context, pageSize int, pageToken string, category string) ([]*contracts.ProductView, string, error) {
+	var views []*contracts.ProductView
+	rm.store.db.Scan(rm.table, func(row memdriver.Row) bool {
+		if str(row, m_product.Status) == string(domain.ProductStatusActive) &&
+			str(row, m_product.ProductID) > pageToken &&
+			(category == "" || str(row, m_product.Category) == category) {
+			views = append(views, rm.toView(row))
+		}
+		return true
+	})
+	sort.Slice(views, func(i, j int) bool { return views[i].ID < views[j].ID })
+
+	var nextToken string
+	if len(views) > pageSize {
+		views = views[:pageSize]
+		nextToken = views[pageSize-1].ID
+	}
+	return views, nextToken, nil
+}
+
+func toView(row memdriver.Row) *contracts.ProductView {
+	return &contracts.ProductView{
+		ID:                str(row, m_product.ProductID),
+		Name:              str(row, m_product.Name),
+		Description:       str(row, m_product.Description),
+		Category:          str(row, m_product.Category),
+		BasePrice:         numeric(row, m_product.BasePrice),
+		DiscountPercent:   numeric(row, m_product.DiscountPercent),
+		DiscountStartDate: nullTime(row, m_product.DiscountStartDate),
+		DiscountEndDate:   nullTime(row, m_product.DiscountEndDate),
+		Status:            str(row, m_product.Status),
+		CreatedAt:         timestamp(row, m_product.CreatedAt),
+		UpdatedAt:         timestamp(row, m_product.UpdatedAt),
+		Version:           i64(row, m_product.Version),
+	}
+}
+
+var (
+	_ contracts.ProductReadModel = (*ProductViewRepo)(nil)
+	_ contracts.ProductViewStore = (*ProductViewRepo)(nil)
+)
+
+// ProductViewRepo serves the read side from product_views. A view has every
+// products column, so its rows read just like products, plus the
+// precomputed discounted_price.
+type ProductViewRepo struct {
+	writes.ProductViews
+	*ProductReadModel
+}
+
+func NewProductViewRepo(store *Store) *ProductViewRepo {
+	return &ProductViewRepo{
+		ProductViews:     writes.NewProductViews(),
+		ProductReadModel: &ProductReadModel{store: store, table: m_product_view.Table, toView: viewToView},
+	}
+}
+
+func viewToView(row memdriver.Row) *contracts.ProductView {
+	v := toView(row)
+	v.DiscountedPrice = numeric(row, m_product_view.DiscountedPrice)
+	v.SearchText = str(row, m_product_view.SearchText)
+	return v
+}
+
+func (r *ProductViewRepo) FindViewRecord(_ context.Context, id string) (*domain.ProductRecord, error) {
+	row, found := r.store.db.Get(m_product_view.Table, commitplan.KeyOf(m_product_view.ProductID, id))
+	if !found {
+		return nil, domain.ErrProductNotFound
+	}
+	return toRecord(row), nil
+}
+
+var _ contracts.PriceHistoryRepository = (*PriceHistoryRepo)(nil)
+
+type PriceHistoryRepo struct {
+	writes.PriceHistory
+}
+
+func NewPriceHistoryRepo() *PriceHistoryRepo {
+	return &PriceHistoryRepo{PriceHistory: writes.NewPriceHistory()}
+}
+
+var _ contracts.QuarantineRepository = (*QuarantineRepo)(nil)
+
+type QuarantineRepo struct {
+	writes.Quarantine
+}
+
+func NewQuarantineRepo() *QuarantineRepo {
+	return &QuarantineRepo{Quarantine: writes.NewQuarantine()}
+}
@@ -0,0 +1,69 @@
+// Package memory is a storage backend that keeps everything in process
+// memory: repositories and read model over the commitplan memory driver,
+// which applies plans to them atomically. It backs demos and tests that
+// shouldn't need Spanner. Nothing survives a restart.
+package memory
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	memdriver "github.com/tshubham2/commitplan/drivers/memory"
+)
+
+// Store holds the tables. Repositories created from the same Store see the
+// same data, and its Committer is the only thing that writes to it.
+type Store struct {
+	db *memdriver.DB
+}
+
+func NewStore() *Store {
+	return &Store{db: memdriver.NewDB()}
+}
+
+// DB is the raw tables, for tests that check what the repositories wrote.
+// Writes must still go through NewCommitter.
+func (s *Store) DB() *memdriver.DB {
+	return s.db
+}
+
+// NewCommitter applies plans to the store. Transactions are serial: the
+// driver holds a writer lock while the transaction's function runs.
+func NewCommitter(store *Store) committer.Committer {
+	return committer.New(memdriver.NewCommitter(store.db))
+}
+
+// Rows hold the values mutations wrote: strings, int64s, time.Times,
+// big.Rats, and nothing at all for NULL columns.
+
+func str(row memdriver.Row, column string) string {
+	s, _ := row[column].(string)
+	return s
+}
+
+func i64(row memdriver.Row, column string) int64 {
+	n, _ := row[column].(int64)
+	return n
+}
+
+func timestamp(row memdriver.Row, column string) time.Time {
+	t, _ := row[column].(time.Time)
+	return t
+}
+
+func nullTime(row memdriver.Row, column string) *time.Time {
+	t, ok := row[column].(time.Time)
+	if !ok {
+		return nil
+	}
+	return &t
+}
+
+func numeric(row memdriver.Row, column string) *big.Rat {
+	r, ok := row[column].(big.Rat)
+	if !ok {
+		return nil
+	}
+	return new(big.Rat).Set(&r)
+}
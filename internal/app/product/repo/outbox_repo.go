@@ -1,30 +1,229 @@
 package repo
 
 import (
+	"context"
+	"encoding/json"
+	"time"
+
 	"cloud.google.com/go/spanner"
+	"google.golang.org/api/iterator"
 
 	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/writes"
 	"github.com/tshubham2/catalog-proj/internal/models/m_outbox"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
 )
 
-var _ contracts.OutboxRepository = (*OutboxRepo)(nil)
+var (
+	_ contracts.OutboxRepository = (*OutboxRepo)(nil)
+	_ contracts.OutboxStore      = (*OutboxRepo)(nil)
+	_ contracts.DeadLetterStore  = (*OutboxRepo)(nil)
+
+	_ contracts.OutboxRetentionStore = (*OutboxRepo)(nil)
+)
 
 type OutboxRepo struct {
-	model *m_outbox.Model
+	writes.Outbox
+	client *spanner.Client
+	model  *m_outbox.Model
+}
+
+func NewOutboxRepo(client *spanner.Client) *OutboxRepo {
+	return &OutboxRepo{Outbox: writes.NewOutbox(), client: client, model: m_outbox.New()}
+}
+
+// ListClaimable returns up to limit due, unleased pending events, oldest
+// first, at most one per aggregate: an event is only claimable once every
+// earlier event of its aggregate has been processed. Dead-lettered events
+// hold their successors back until they are requeued. It reads through the
+// caller's transaction so the claim that follows conflicts with any other
+// relay claiming the same rows.
+func (r *OutboxRepo) ListClaimable(ctx context.Context, now time.Time, limit int) ([]contracts.OutboxEvent, error) {
+	stmt := spanner.Statement{
+		SQL: `SELECT ` + m_outbox.SelectList() + ` FROM outbox_events@{FORCE_INDEX=idx_outbox_status} e
+			WHERE status = @status
+			AND (next_attempt_at IS NULL OR next_attempt_at <= @now)
+			AND (lease_expires_at IS NULL OR lease_expires_at <= @now)
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_events@{FORCE_INDEX=idx_outbox_aggregate_sequence} prior
+				WHERE prior.aggregate_id = e.aggregate_id
+				AND prior.status IN UNNEST(@unsettled)
+				AND (prior.sequence < e.sequence
+					OR (prior.sequence = e.sequence AND prior.created_at < e.created_at))
+			)
+			ORDER BY created_at ASC LIMIT @limit`,
+		Params: map[string]interface{}{
+			"status":    contracts.OutboxStatusPending,
+			"unsettled": []string{contracts.OutboxStatusPending, contracts.OutboxStatusDeadLetter},
+			"now":       now,
+			"limit":     int64(limit),
+		},
+	}
+	return r.query(ctx, committer.Reader(ctx, r.client), stmt)
+}
+
+func (r *OutboxRepo) ClaimOwner(ctx context.Context, eventID string) (string, error) {
+	row, err := committer.Reader(ctx, r.client).ReadRow(
+		ctx, m_outbox.Table, spanner.Key{eventID}, []string{m_outbox.ClaimedBy},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	var owner spanner.NullString
+	if err := row.Column(0, &owner); err != nil {
+		return "", err
+	}
+	return owner.StringVal, nil
+}
+
+func (r *OutboxRepo) ListDeadLetters(ctx context.Context, pageSize int, pageToken string) ([]contracts.OutboxEvent, string, error) {
+	stmt := spanner.Statement{
+		SQL: `SELECT ` + m_outbox.SelectList() + ` FROM outbox_events
+			WHERE status = @status AND event_id > @after
+			ORDER BY event_id ASC LIMIT @limit`,
+		Params: map[string]interface{}{
+			"status": contracts.OutboxStatusDeadLetter,
+			"after":  pageToken,
+			"limit":  int64(pageSize + 1),
+		},
+	}
+
+	events, err := r.query(ctx, r.client.Single(), stmt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(events) > pageSize {
+		nextToken = events[pageSize-1].ID
+		events = events[:pageSize]
+	}
+	return events, nextToken, nil
+}
+
+func (r *OutboxRepo) FindDeadLetter(ctx context.Context, eventID string) (*contracts.OutboxEvent, error) {
+	stmt := spanner.Statement{
+		SQL: `SELECT ` + m_outbox.SelectList() + ` FROM outbox_events
+			WHERE event_id = @id AND status = @status`,
+		Params: map[string]interface{}{
+			"id":     eventID,
+			"status": contracts.OutboxStatusDeadLetter,
+		},
+	}
+
+	events, err := r.query(ctx, committer.Reader(ctx, r.client), stmt)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, contracts.ErrOutboxEventNotFound
+	}
+	return &events[0], nil
+}
+
+func (r *OutboxRepo) FindDeadLettersByAggregate(ctx context.Context, aggregateID string) ([]contracts.OutboxEvent, error) {
+	stmt := spanner.Statement{
+		SQL: `SELECT ` + m_outbox.SelectList() + ` FROM outbox_events
+			WHERE aggregate_id = @aggregate AND status = @status
+			ORDER BY sequence ASC, created_at ASC`,
+		Params: map[string]interface{}{
+			"aggregate": aggregateID,
+			"status":    contracts.OutboxStatusDeadLetter,
+		},
+	}
+	return r.query(ctx, committer.Reader(ctx, r.client), stmt)
 }
 
-func NewOutboxRepo() *OutboxRepo {
-	return &OutboxRepo{model: m_outbox.New()}
+// FindByAggregate returns every event of an aggregate in sequence order,
+// whatever its delivery status.
+func (r *OutboxRepo) FindByAggregate(ctx context.Context, aggregateID string) ([]contracts.OutboxEvent, error) {
+	stmt := spanner.Statement{
+		SQL: `SELECT ` + m_outbox.SelectList() + ` FROM outbox_events@{FORCE_INDEX=idx_outbox_aggregate_sequence}
+			WHERE aggregate_id = @aggregate
+			ORDER BY sequence ASC, created_at ASC`,
+		Params: map[string]interface{}{"aggregate": aggregateID},
+	}
+	return r.query(ctx, committer.Reader(ctx, r.client), stmt)
 }
 
-func (r *OutboxRepo) InsertMut(event contracts.OutboxEvent) *spanner.Mutation {
-	data := &m_outbox.Data{
-		EventID:     event.ID,
-		EventType:   event.EventType,
-		AggregateID: event.AggregateID,
-		Payload:     event.Payload,
-		Status:      "pending",
-		CreatedAt:   event.CreatedAt,
+func (r *OutboxRepo) OldestSettled(ctx context.Context, status string) (time.Time, bool, error) {
+	stmt := spanner.Statement{
+		SQL: `SELECT MIN(processed_at) FROM outbox_events@{FORCE_INDEX=idx_outbox_settled}
+			WHERE status = @status`,
+		Params: map[string]interface{}{"status": status},
+	}
+
+	iter := r.client.Single().Query(ctx, stmt)
+	defer iter.Stop()
+
+	row, err := iter.Next()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	var oldest spanner.NullTime
+	if err := row.Column(0, &oldest); err != nil {
+		return time.Time{}, false, err
+	}
+	return oldest.Time, oldest.Valid, nil
+}
+
+// PurgeSettled uses partitioned DML, so the delete is split across splits
+// and committed piecewise rather than in one large transaction.
+func (r *OutboxRepo) PurgeSettled(ctx context.Context, status string, before time.Time) (int64, error) {
+	stmt := spanner.Statement{
+		SQL: `DELETE FROM outbox_events
+			WHERE status = @status AND processed_at < @before`,
+		Params: map[string]interface{}{
+			"status": status,
+			"before": before,
+		},
+	}
+	return r.client.PartitionedUpdate(ctx, stmt)
+}
+
+func (r *OutboxRepo) query(ctx context.Context, txn committer.ReadTxn, stmt spanner.Statement) ([]contracts.OutboxEvent, error) {
+	iter := txn.Query(ctx, stmt)
+	defer iter.Stop()
+
+	var events []contracts.OutboxEvent
+	for {
+		row, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := r.model.FromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, toOutboxEvent(data))
+	}
+	return events, nil
+}
+
+func toOutboxEvent(d *m_outbox.Data) contracts.OutboxEvent {
+	e := contracts.OutboxEvent{
+		ID:          d.EventID,
+		EventType:   d.EventType,
+		AggregateID: d.AggregateID,
+		Sequence:    d.Sequence,
+		Payload:     d.Payload,
+		CreatedAt:   d.CreatedAt,
+		Status:      d.Status,
+		Attempts:    d.Attempts,
+		LastError:   d.LastError.StringVal,
+	}
+	if d.ProcessedAt.Valid {
+		t := d.ProcessedAt.Time
+		e.ProcessedAt = &t
+	}
+	if len(d.Metadata) > 0 {
+		// Metadata is informational; a malformed value mustn't stop the
+		// event from being delivered.
+		_ = json.Unmarshal(d.Metadata, &e.Metadata)
 	}
-	return r.model.InsertMap(r.model.ToRow(data))
+	return e
 }
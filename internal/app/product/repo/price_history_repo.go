@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/writes"
+)
+
+var _ contracts.PriceHistoryRepository = (*PriceHistoryRepo)(nil)
+
+type PriceHistoryRepo struct {
+	writes.PriceHistory
+}
+
+func NewPriceHistoryRepo() *PriceHistoryRepo {
+	return &PriceHistoryRepo{PriceHistory: writes.NewPriceHistory()}
+}
@@ -3,36 +3,59 @@ package repo
 import (
 	"context"
 	"math/big"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/spanner"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
 
 	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
 	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/writes"
 	"github.com/tshubham2/catalog-proj/internal/models/m_product"
+	"github.com/tshubham2/catalog-proj/internal/models/m_product_view"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
 )
 
-var _ contracts.ProductRepository = (*ProductRepo)(nil) // compile-time check
+var (
+	_ contracts.ProductRepository  = (*ProductRepo)(nil) // compile-time check
+	_ contracts.ProductRecordStore = (*ProductRepo)(nil)
+)
 
 type ProductRepo struct {
+	writes.Products
 	client *spanner.Client
 	model  *m_product.Model
 }
 
 func NewProductRepo(client *spanner.Client) *ProductRepo {
 	return &ProductRepo{
-		client: client,
-		model:  m_product.New(),
+		Products: writes.NewProducts(),
+		client:   client,
+		model:    m_product.New(),
 	}
 }
 
+// FindByID reads through the caller's transaction when ctx carries one (see
+// committer.RunInTransaction). A row that breaks the aggregate's invariants
+// is a *domain.CorruptProductError.
 func (r *ProductRepo) FindByID(ctx context.Context, id string) (*domain.Product, error) {
-	row, err := r.client.Single().ReadRow(
+	rec, err := r.FindRecord(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return rec.Restore()
+}
+
+// FindRecord reads a product as stored, without checking it, for integrity
+// tooling. It reads through the caller's transaction like FindByID.
+func (r *ProductRepo) FindRecord(ctx context.Context, id string) (*domain.ProductRecord, error) {
+	row, err := committer.Reader(ctx, r.client).ReadRow(
 		ctx, m_product.Table, spanner.Key{id}, m_product.AllColumns,
 	)
 	if err != nil {
-		if spanner.ErrCode(err) == 5 {
+		if spanner.ErrCode(err) == codes.NotFound {
 			return nil, domain.ErrProductNotFound
 		}
 		return nil, err
@@ -42,74 +65,37 @@ func (r *ProductRepo) FindByID(ctx context.Context, id string) (*domain.Product,
 	if err != nil {
 		return nil, err
 	}
-	return toDomain(data), nil
-}
-
-func (r *ProductRepo) InsertMut(p *domain.Product) *spanner.Mutation {
-	values := map[string]interface{}{
-		m_product.ProductID:            p.ID(),
-		m_product.Name:                 p.Name(),
-		m_product.Description:          p.Description(),
-		m_product.Category:             p.Category(),
-		m_product.BasePriceNumerator:   p.BasePrice().Numerator(),
-		m_product.BasePriceDenominator: p.BasePrice().Denominator(),
-		m_product.Status:               string(p.Status()),
-		m_product.CreatedAt:            p.CreatedAt(),
-		m_product.UpdatedAt:            p.UpdatedAt(),
-	}
-
-	if d := p.Discount(); d != nil {
-		pct := d.Percentage()
-		values[m_product.DiscountPercent] = *pct
-		values[m_product.DiscountStartDate] = d.StartDate()
-		values[m_product.DiscountEndDate] = d.EndDate()
-	}
-	if p.ArchivedAt() != nil {
-		values[m_product.ArchivedAt] = *p.ArchivedAt()
-	}
-
-	return r.model.InsertMap(values)
+	return toRecord(data), nil
 }
 
-func (r *ProductRepo) UpdateMut(p *domain.Product) *spanner.Mutation {
-	ch := p.Changes()
-	if !ch.HasChanges() {
-		return nil
-	}
-
-	updates := map[string]interface{}{
-		m_product.UpdatedAt: p.UpdatedAt(),
+// ListIDs returns up to limit product IDs after the given one, in order, for
+// tools that walk the whole table a page at a time.
+func (r *ProductRepo) ListIDs(ctx context.Context, after string, limit int) ([]string, error) {
+	stmt := spanner.Statement{
+		SQL: `SELECT product_id FROM products
+			WHERE product_id > @after
+			ORDER BY product_id ASC LIMIT @limit`,
+		Params: map[string]interface{}{"after": after, "limit": int64(limit)},
 	}
+	iter := r.client.Single().Query(ctx, stmt)
+	defer iter.Stop()
 
-	if ch.Dirty(domain.FieldName) {
-		updates[m_product.Name] = p.Name()
-	}
-	if ch.Dirty(domain.FieldDescription) {
-		updates[m_product.Description] = p.Description()
-	}
-	if ch.Dirty(domain.FieldCategory) {
-		updates[m_product.Category] = p.Category()
-	}
-	if ch.Dirty(domain.FieldStatus) {
-		updates[m_product.Status] = string(p.Status())
-		if p.ArchivedAt() != nil {
-			updates[m_product.ArchivedAt] = *p.ArchivedAt()
+	var ids []string
+	for {
+		row, err := iter.Next()
+		if err == iterator.Done {
+			break
 		}
-	}
-	if ch.Dirty(domain.FieldDiscount) {
-		if d := p.Discount(); d != nil {
-			pct := d.Percentage()
-			updates[m_product.DiscountPercent] = *pct
-			updates[m_product.DiscountStartDate] = d.StartDate()
-			updates[m_product.DiscountEndDate] = d.EndDate()
-		} else {
-			updates[m_product.DiscountPercent] = nil
-			updates[m_product.DiscountStartDate] = nil
-			updates[m_product.DiscountEndDate] = nil
+		if err != nil {
+			return nil, err
 		}
+		var id string
+		if err := row.Columns(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
-
-	return r.model.UpdateMap(p.ID(), updates)
+	return ids, nil
 }
 
 // ProductReadModel reads directly from Spanner, bypassing the aggregate.
@@ -118,35 +104,41 @@ var _ contracts.ProductReadModel = (*ProductReadModel)(nil)
 
 type ProductReadModel struct {
 	client *spanner.Client
+	// table is products, or product_views for a ProductViewRepo; columns
+	// are read from it and decoded by toView.
+	table   string
+	columns []string
+	toView  func(row *spanner.Row) (*contracts.ProductView, error)
 }
 
 func NewProductReadModel(client *spanner.Client) *ProductReadModel {
-	return &ProductReadModel{client: client}
+	return &ProductReadModel{
+		client:  client,
+		table:   m_product.Table,
+		columns: m_product.AllColumns,
+		toView:  productToView,
+	}
 }
 
 func (rm *ProductReadModel) GetByID(ctx context.Context, id string) (*contracts.ProductView, error) {
 	row, err := rm.client.Single().ReadRow(
-		ctx, m_product.Table, spanner.Key{id}, m_product.AllColumns,
+		ctx, rm.table, spanner.Key{id}, rm.columns,
 	)
 	if err != nil {
-		if spanner.ErrCode(err) == 5 {
+		if spanner.ErrCode(err) == codes.NotFound {
 			return nil, domain.ErrProductNotFound
 		}
 		return nil, err
 	}
-
-	data, err := m_product.New().FromRow(row)
-	if err != nil {
-		return nil, err
-	}
-	return toView(data), nil
+	return rm.toView(row)
 }
 
 func (rm *ProductReadModel) ListActive(ctx context.Context, pageSize int, pageToken string, category string) ([]*contracts.ProductView, string, error) {
 	stmt := spanner.Statement{}
+	columns := strings.Join(rm.columns, ", ")
 
 	if category != "" {
-		stmt.SQL = `SELECT ` + columnsCSV() + ` FROM products
+		stmt.SQL = `SELECT ` + columns + ` FROM ` + rm.table + `
 			WHERE status = @status AND category = @category AND product_id > @after
 			ORDER BY product_id ASC LIMIT @limit`
 		stmt.Params = map[string]interface{}{
@@ -156,7 +148,7 @@ func (rm *ProductReadModel) ListActive(ctx context.Context, pageSize int, pageTo
 			"limit":    int64(pageSize + 1),
 		}
 	} else {
-		stmt.SQL = `SELECT ` + columnsCSV() + ` FROM products
+		stmt.SQL = `SELECT ` + columns + ` FROM ` + rm.table + `
 			WHERE status = @status AND product_id > @after
 			ORDER BY product_id ASC LIMIT @limit`
 		stmt.Params = map[string]interface{}{
@@ -169,7 +161,6 @@ func (rm *ProductReadModel) ListActive(ctx context.Context, pageSize int, pageTo
 	iter := rm.client.Single().Query(ctx, stmt)
 	defer iter.Stop()
 
-	model := m_product.New()
 	var views []*contracts.ProductView
 
 	for {
@@ -180,75 +171,127 @@ func (rm *ProductReadModel) ListActive(ctx context.Context, pageSize int, pageTo
 		if err != nil {
 			return nil, "", err
 		}
-		data, err := model.FromRow(row)
+		view, err := rm.toView(row)
 		if err != nil {
 			return nil, "", err
 		}
-		views = append(views, toView(data))
+		views = append(views, view)
 	}
 
 	var nextToken string
 	if len(views) > pageSize {
-		nextToken = views[pageSize].ID
+		nextToken = views[pageSize-1].ID
 		views = views[:pageSize]
 	}
 
 	return views, nextToken, nil
 }
 
-func toDomain(d *m_product.Data) *domain.Product {
-	basePrice, _ := domain.NewMoney(d.BasePriceNumerator, d.BasePriceDenominator)
+var (
+	_ contracts.ProductReadModel = (*ProductViewRepo)(nil)
+	_ contracts.ProductViewStore = (*ProductViewRepo)(nil)
+)
 
-	var discount *domain.Discount
-	if pct := d.DiscountPercentRat(); pct != nil && d.DiscountStartDate.Valid && d.DiscountEndDate.Valid {
-		discount, _ = domain.NewDiscount(pct, d.DiscountStartDate.Time, d.DiscountEndDate.Time)
-	}
+// ProductViewRepo serves the read side from product_views, and gives the
+// projector that maintains it its reads and writes. Reads serve the view's
+// precomputed discounted_price.
+type ProductViewRepo struct {
+	writes.ProductViews
+	*ProductReadModel
+}
 
-	var archivedAt *time.Time
-	if d.ArchivedAt.Valid {
-		t := d.ArchivedAt.Time
-		archivedAt = &t
+func NewProductViewRepo(client *spanner.Client) *ProductViewRepo {
+	return &ProductViewRepo{
+		ProductViews: writes.NewProductViews(),
+		ProductReadModel: &ProductReadModel{
+			client:  client,
+			table:   m_product_view.Table,
+			columns: m_product_view.AllColumns,
+			toView:  viewToView,
+		},
 	}
+}
 
-	return domain.Reconstitute(
-		d.ProductID, d.Name, d.Description, d.Category,
-		basePrice, discount,
-		domain.ProductStatus(d.Status),
-		d.CreatedAt, d.UpdatedAt,
-		archivedAt,
+// FindViewRecord reads through the caller's transaction when ctx carries one.
+func (r *ProductViewRepo) FindViewRecord(ctx context.Context, id string) (*domain.ProductRecord, error) {
+	row, err := committer.Reader(ctx, r.client).ReadRow(
+		ctx, m_product_view.Table, spanner.Key{id}, m_product_view.AllColumns,
 	)
+	if err != nil {
+		if spanner.ErrCode(err) == codes.NotFound {
+			return nil, domain.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	data, err := m_product_view.New().FromRow(row)
+	if err != nil {
+		return nil, err
+	}
+	return viewRecord(data), nil
 }
 
-func toView(d *m_product.Data) *contracts.ProductView {
-	v := &contracts.ProductView{
-		ID:                   d.ProductID,
-		Name:                 d.Name,
-		Description:          d.Description,
-		Category:             d.Category,
-		BasePriceNumerator:   d.BasePriceNumerator,
-		BasePriceDenominator: d.BasePriceDenominator,
-		Status:               d.Status,
-		CreatedAt:            d.CreatedAt,
-		UpdatedAt:            d.UpdatedAt,
+func toRecord(d *m_product.Data) *domain.ProductRecord {
+	return &domain.ProductRecord{
+		ID:              d.ProductID,
+		Name:            d.Name,
+		Description:     d.Description.StringVal,
+		Category:        d.Category,
+		BasePrice:       new(big.Rat).Set(&d.BasePrice),
+		DiscountPercent: d.DiscountPercentRat(),
+		DiscountStart:   nullTime(d.DiscountStartDate),
+		DiscountEnd:     nullTime(d.DiscountEndDate),
+		Status:          d.Status,
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+		ArchivedAt:      nullTime(d.ArchivedAt),
+		Version:         d.Version,
 	}
-	if pct := d.DiscountPercentRat(); pct != nil {
-		v.DiscountPercent = new(big.Rat).Set(pct)
+}
+
+func nullTime(t spanner.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
 	}
-	if d.DiscountStartDate.Valid {
-		t := d.DiscountStartDate.Time
-		v.DiscountStartDate = &t
+	v := t.Time
+	return &v
+}
+
+func productToView(row *spanner.Row) (*contracts.ProductView, error) {
+	data, err := m_product.New().FromRow(row)
+	if err != nil {
+		return nil, err
+	}
+	return contracts.NewProductView(toRecord(data)), nil
+}
+
+func viewToView(row *spanner.Row) (*contracts.ProductView, error) {
+	data, err := m_product_view.New().FromRow(row)
+	if err != nil {
+		return nil, err
 	}
-	if d.DiscountEndDate.Valid {
-		t := d.DiscountEndDate.Time
-		v.DiscountEndDate = &t
+	v := contracts.NewProductView(viewRecord(data))
+	if price := data.DiscountedPriceRat(); price != nil {
+		v.DiscountedPrice = new(big.Rat).Set(price)
 	}
-	return v
+	v.SearchText = data.SearchText
+	return v, nil
 }
 
-func columnsCSV() string {
-	out := m_product.AllColumns[0]
-	for _, c := range m_product.AllColumns[1:] {
-		out += ", " + c
+func viewRecord(d *m_product_view.Data) *domain.ProductRecord {
+	return &domain.ProductRecord{
+		ID:              d.ProductID,
+		Name:            d.Name,
+		Description:     d.Description.StringVal,
+		Category:        d.Category,
+		BasePrice:       new(big.Rat).Set(&d.BasePrice),
+		DiscountPercent: d.DiscountPercentRat(),
+		DiscountStart:   nullTime(d.DiscountStartDate),
+		DiscountEnd:     nullTime(d.DiscountEndDate),
+		Status:          d.Status,
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+		ArchivedAt:      nullTime(d.ArchivedAt),
+		Version:         d.Version,
 	}
-	return out
 }
@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/writes"
+)
+
+var _ contracts.QuarantineRepository = (*QuarantineRepo)(nil)
+
+type QuarantineRepo struct {
+	writes.Quarantine
+}
+
+func NewQuarantineRepo() *QuarantineRepo {
+	return &QuarantineRepo{Quarantine: writes.NewQuarantine()}
+}
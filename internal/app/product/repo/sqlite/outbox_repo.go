@@ -0,0 +1,202 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/writes"
+	"github.com/tshubham2/catalog-proj/internal/models/m_outbox"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	sqlitedriver "github.com/tshubham2/commitplan/drivers/sqlite"
+)
+
+var (
+	_ contracts.OutboxRepository = (*OutboxRepo)(nil)
+	_ contracts.OutboxStore      = (*OutboxRepo)(nil)
+	_ contracts.DeadLetterStore  = (*OutboxRepo)(nil)
+
+	_ contracts.OutboxRetentionStore = (*OutboxRepo)(nil)
+)
+
+type OutboxRepo struct {
+	writes.Outbox
+	db *sql.DB
+}
+
+func NewOutboxRepo(db *sql.DB) *OutboxRepo {
+	return &OutboxRepo{Outbox: writes.NewOutbox(), db: db}
+}
+
+var outboxColumns = strings.Join(m_outbox.AllColumns, ", ")
+
+// ListClaimable applies the same rules as the Spanner query: due, unleased
+// and pending, with no unsettled earlier event of the same aggregate. The
+// relay's transaction holds SQLite's write lock from its first read, so two
+// relays can't claim the same rows.
+func (r *OutboxRepo) ListClaimable(ctx context.Context, now time.Time, limit int) ([]contracts.OutboxEvent, error) {
+	return r.query(ctx, committer.SQLReader(ctx, r.db), `SELECT `+outboxColumns+` FROM outbox_events e
+		WHERE status = ?1
+		AND (next_attempt_at IS NULL OR next_attempt_at <= ?2)
+		AND (lease_expires_at IS NULL OR lease_expires_at <= ?2)
+		AND NOT EXISTS (
+			SELECT 1 FROM outbox_events prior
+			WHERE prior.aggregate_id = e.aggregate_id
+			AND prior.status IN (?3, ?4)
+			AND (prior.sequence < e.sequence
+				OR (prior.sequence = e.sequence AND prior.created_at < e.created_at))
+		)
+		ORDER BY created_at ASC LIMIT ?5`,
+		contracts.OutboxStatusPending, sqlitedriver.FormatTime(now),
+		contracts.OutboxStatusPending, contracts.OutboxStatusDeadLetter,
+		limit,
+	)
+}
+
+func (r *OutboxRepo) ClaimOwner(ctx context.Context, eventID string) (string, error) {
+	var owner sql.NullString
+	err := committer.SQLReader(ctx, r.db).QueryRowContext(ctx,
+		`SELECT claimed_by FROM outbox_events WHERE event_id = ?1`, eventID).Scan(&owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("outbox event %q not found", eventID)
+	}
+	return owner.String, err
+}
+
+func (r *OutboxRepo) ListDeadLetters(ctx context.Context, pageSize int, pageToken string) ([]contracts.OutboxEvent, string, error) {
+	events, err := r.query(ctx, r.db, `SELECT `+outboxColumns+` FROM outbox_events
+		WHERE status = ?1 AND event_id > ?2
+		ORDER BY event_id ASC LIMIT ?3`,
+		contracts.OutboxStatusDeadLetter, pageToken, pageSize+1,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(events) > pageSize {
+		nextToken = events[pageSize-1].ID
+		events = events[:pageSize]
+	}
+	return events, nextToken, nil
+}
+
+func (r *OutboxRepo) FindDeadLetter(ctx context.Context, eventID string) (*contracts.OutboxEvent, error) {
+	events, err := r.query(ctx, committer.SQLReader(ctx, r.db), `SELECT `+outboxColumns+` FROM outbox_events
+		WHERE event_id = ?1 AND status = ?2`,
+		eventID, contracts.OutboxStatusDeadLetter,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, contracts.ErrOutboxEventNotFound
+	}
+	return &events[0], nil
+}
+
+func (r *OutboxRepo) FindDeadLettersByAggregate(ctx context.Context, aggregateID string) ([]contracts.OutboxEvent, error) {
+	return r.query(ctx, committer.SQLReader(ctx, r.db), `SELECT `+outboxColumns+` FROM outbox_events
+		WHERE aggregate_id = ?1 AND status = ?2
+		ORDER BY sequence ASC, created_at ASC`,
+		aggregateID, contracts.OutboxStatusDeadLetter,
+	)
+}
+
+// FindByAggregate returns every event of an aggregate in sequence order,
+// whatever its delivery status.
+func (r *OutboxRepo) FindByAggregate(ctx context.Context, aggregateID string) ([]contracts.OutboxEvent, error) {
+	return r.query(ctx, committer.SQLReader(ctx, r.db), `SELECT `+outboxColumns+` FROM outbox_events
+		WHERE aggregate_id = ?1
+		ORDER BY sequence ASC, created_at ASC`,
+		aggregateID,
+	)
+}
+
+// OldestSettled relies on TimeFormat sorting like the instants it encodes.
+func (r *OutboxRepo) OldestSettled(ctx context.Context, status string) (time.Time, bool, error) {
+	var oldest timestamp
+	err := r.db.QueryRowContext(ctx,
+		`SELECT MIN(processed_at) FROM outbox_events WHERE status = ?1`, status).Scan(&oldest)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return oldest.Time, oldest.Valid, nil
+}
+
+// PurgeSettled deletes outside any transaction, like the Spanner repository's
+// partitioned DML; callers keep the range small.
+func (r *OutboxRepo) PurgeSettled(ctx context.Context, status string, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM outbox_events WHERE status = ?1 AND processed_at < ?2`, status, sqlitedriver.FormatTime(before))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *OutboxRepo) query(ctx context.Context, txn committer.SQLReadTxn, query string, args ...interface{}) ([]contracts.OutboxEvent, error) {
+	rows, err := txn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []contracts.OutboxEvent
+	for rows.Next() {
+		e, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// scanOutboxEvent reads a row selected with m_outbox.AllColumns.
+func scanOutboxEvent(s scanner) (contracts.OutboxEvent, error) {
+	var (
+		e                                      contracts.OutboxEvent
+		payload                                string
+		metadata, lastError, claimedBy         sql.NullString
+		createdAt                              timestamp
+		processedAt, nextAttemptAt, leaseUntil timestamp
+	)
+	dest, err := m_outbox.ScanDest(map[string]interface{}{
+		m_outbox.EventID:        &e.ID,
+		m_outbox.EventType:      &e.EventType,
+		m_outbox.AggregateID:    &e.AggregateID,
+		m_outbox.Payload:        &payload,
+		m_outbox.Status:         &e.Status,
+		m_outbox.CreatedAt:      &createdAt,
+		m_outbox.ProcessedAt:    &processedAt,
+		m_outbox.Attempts:       &e.Attempts,
+		m_outbox.LastError:      &lastError,
+		m_outbox.NextAttemptAt:  &nextAttemptAt,
+		m_outbox.ClaimedBy:      &claimedBy,
+		m_outbox.LeaseExpiresAt: &leaseUntil,
+		m_outbox.Sequence:       &e.Sequence,
+		m_outbox.Metadata:       &metadata,
+	})
+	if err != nil {
+		return contracts.OutboxEvent{}, err
+	}
+	if err := s.Scan(dest...); err != nil {
+		return contracts.OutboxEvent{}, err
+	}
+	e.Payload = json.RawMessage(payload)
+	e.CreatedAt = createdAt.Time
+	e.ProcessedAt = processedAt.ptr()
+	e.LastError = lastError.String
+	if metadata.Valid {
+		// Metadata is informational; a malformed value mustn't stop the
+		// event from being delivered.
+		_ = json.Unmarshal([]byte(metadata.String), &e.Metadata)
+	}
+	return e, nil
+}
@@ -0,0 +1,408 @@
+// Package sqlite implements the product repositories on SQLite, over the
+// schema in migrations/sqlite, for single-node deployments such as kiosks.
+// Writes are the shared mutations from repo/writes; reads go through the
+// transaction the committer bound to ctx. Discounts are stored as exact
+// rationals and timestamps as text (see the commitplan sqlite driver).
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/writes"
+	"github.com/tshubham2/catalog-proj/internal/models/m_product"
+	"github.com/tshubham2/catalog-proj/internal/models/m_product_view"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	sqlitedriver "github.com/tshubham2/commitplan/drivers/sqlite"
+)
+
+var (
+	_ contracts.ProductRepository  = (*ProductRepo)(nil)
+	_ contracts.ProductRecordStore = (*ProductRepo)(nil)
+)
+
+type ProductRepo struct {
+	writes.Products
+	db *sql.DB
+}
+
+func NewProductRepo(db *sql.DB) *ProductRepo {
+	return &ProductRepo{Products: writes.NewProducts(), db: db}
+}
+
+// FindByID reads through the caller's transaction when ctx carries one (see
+// committer.RunInTransaction). A row that breaks the aggregate's invariants
+// is a *domain.CorruptProductError.
+func (r *ProductRepo) FindByID(ctx context.Context, id string) (*domain.Product, error) {
+	rec, err := r.FindRecord(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return rec.Restore()
+}
+
+// FindRecord reads a product as stored, without checking it, for integrity
+// tooling. It reads through the caller's transaction like FindByID.
+func (r *ProductRepo) FindRecord(ctx context.Context, id string) (*domain.ProductRecord, error) {
+	row := committer.SQLReader(ctx, r.db).QueryRowContext(ctx,
+		`SELECT `+productColumns+` FROM products WHERE product_id = ?1`, id)
+	d, err := scanProduct(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toRecord(d), nil
+}
+
+// ListIDs returns up to limit product IDs after the given one, in order.
+func (r *ProductRepo) ListIDs(ctx context.Context, after string, limit int) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT product_id FROM products WHERE product_id > ?1 ORDER BY product_id ASC LIMIT ?2`,
+		after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ProductReadModel reads directly from the products table, bypassing the
+// aggregate.
+
+var _ contracts.ProductReadModel = (*ProductReadModel)(nil)
+
+type ProductReadModel struct {
+	db *sql.DB
+	// table is products, or product_views for a ProductViewRepo; columns
+	// are selected from it and decoded by scan.
+	table   string
+	columns string
+	scan    func(s scanner) (*contracts.ProductView, error)
+}
+
+func NewProductReadModel(db *sql.DB) *ProductReadModel {
+	return &ProductReadModel{db: db, table: m_product.Table, columns: productColumns, scan: scanProductAsView}
+}
+
+func (rm *ProductReadModel) GetByID(ctx context.Context, id string) (*contracts.ProductView, error) {
+	row := rm.db.QueryRowContext(ctx, `SELECT `+rm.columns+` FROM `+rm.table+` WHERE product_id = ?1`, id)
+	view, err := rm.scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (rm *ProductReadModel) ListActive(ctx context.Context, pageSize int, pageToken string, category string) ([]*contracts.ProductView, string, error) {
+	query := `SELECT ` + rm.columns + ` FROM ` + rm.table + `
+		WHERE status = ?1 AND product_id > ?2`
+	args := []interface{}{string(domain.ProductStatusActive), pageToken}
+	if category != "" {
+		query += ` AND category = ?3`
+		args = append(args, category)
+	}
+	query += fmt.Sprintf(` ORDER BY product_id ASC LIMIT ?%d`, len(args)+1)
+	args = append(args, pageSize+1)
+
+	rows, err := rm.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var views []*contracts.ProductView
+	for rows.Next() {
+		view, err := rm.scan(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	var nextToken string
+	if len(views) > pageSize {
+		nextToken = views[pageSize-1].ID
+		views = views[:pageSize]
+	}
+	return views, nextToken, nil
+}
+
+var (
+	_ contracts.ProductReadModel = (*ProductViewRepo)(nil)
+	_ contracts.ProductViewStore = (*ProductViewRepo)(nil)
+)
+
+// ProductViewRepo serves the read side from product_views, and gives the
+// projector that maintains it its reads and writes. Reads serve the view's
+// precomputed discounted_price.
+type ProductViewRepo struct {
+	writes.ProductViews
+	*ProductReadModel
+}
+
+func NewProductViewRepo(db *sql.DB) *ProductViewRepo {
+	return &ProductViewRepo{
+		ProductViews:     writes.NewProductViews(),
+		ProductReadModel: &ProductReadModel{db: db, table: m_product_view.Table, columns: viewColumns, scan: scanViewAsView},
+	}
+}
+
+// FindViewRecord reads through the caller's transaction when ctx carries one.
+func (r *ProductViewRepo) FindViewRecord(ctx context.Context, id string) (*domain.ProductRecord, error) {
+	row := committer.SQLReader(ctx, r.db).QueryRowContext(ctx,
+		`SELECT `+viewColumns+` FROM product_views WHERE product_id = ?1`, id)
+	v, err := scanView(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toRecord(&v.productRow), nil
+}
+
+var _ contracts.PriceHistoryRepository = (*PriceHistoryRepo)(nil)
+
+type PriceHistoryRepo struct {
+	writes.PriceHistory
+}
+
+func NewPriceHistoryRepo() *PriceHistoryRepo {
+	return &PriceHistoryRepo{PriceHistory: writes.NewPriceHistory()}
+}
+
+var _ contracts.QuarantineRepository = (*QuarantineRepo)(nil)
+
+type QuarantineRepo struct {
+	writes.Quarantine
+}
+
+func NewQuarantineRepo() *QuarantineRepo {
+	return &QuarantineRepo{Quarantine: writes.NewQuarantine()}
+}
+
+var productColumns = strings.Join(m_product.AllColumns, ", ")
+
+// productRow is a products row as scanned, before NULLs are resolved.
+type productRow struct {
+	m_product.Data
+	basePrice   string
+	description sql.NullString
+	discount    sql.NullString
+	start, end  timestamp
+	created     timestamp
+	updated     timestamp
+	archivedAt  timestamp
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanProduct(s scanner) (*productRow, error) {
+	d := &productRow{}
+	dest, err := m_product.ScanDest(d.dest())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if err := d.parse(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// dest maps every products column to its scan destination. product_views
+// shares the column names.
+func (d *productRow) dest() map[string]interface{} {
+	return map[string]interface{}{
+		m_product.ProductID:         &d.ProductID,
+		m_product.Name:              &d.Name,
+		m_product.Description:       &d.description,
+		m_product.Category:          &d.Category,
+		m_product.DiscountPercent:   &d.discount,
+		m_product.DiscountStartDate: &d.start,
+		m_product.DiscountEndDate:   &d.end,
+		m_product.Status:            &d.Status,
+		m_product.CreatedAt:         &d.created,
+		m_product.UpdatedAt:         &d.updated,
+		m_product.ArchivedAt:        &d.archivedAt,
+		m_product.Version:           &d.Version,
+		m_product.BasePrice:         &d.basePrice,
+	}
+}
+
+// parse fills the fields Scan can't fill directly.
+func (d *productRow) parse() error {
+	if _, ok := d.BasePrice.SetString(d.basePrice); !ok {
+		return &domain.CorruptProductError{ProductID: d.ProductID, Violations: []domain.Violation{
+			{Field: domain.FieldBasePrice, Err: fmt.Errorf("malformed value %q", d.basePrice)},
+		}}
+	}
+	d.CreatedAt = d.created.Time
+	d.UpdatedAt = d.updated.Time
+	return nil
+}
+
+func scanProductAsView(s scanner) (*contracts.ProductView, error) {
+	d, err := scanProduct(s)
+	if err != nil {
+		return nil, err
+	}
+	return toView(d), nil
+}
+
+var viewColumns = strings.Join(m_product_view.AllColumns, ", ")
+
+// viewRow is a product_views row as scanned: a product plus what the
+// projector precomputed.
+type viewRow struct {
+	productRow
+	discounted      sql.NullString
+	discountedPrice *big.Rat
+	searchText      string
+	projectedAt     timestamp
+}
+
+func scanView(s scanner) (*viewRow, error) {
+	v := &viewRow{}
+	byColumn := v.dest()
+	byColumn[m_product_view.DiscountedPrice] = &v.discounted
+	byColumn[m_product_view.SearchText] = &v.searchText
+	byColumn[m_product_view.ProjectedAt] = &v.projectedAt
+	dest, err := m_product_view.ScanDest(byColumn)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if err := v.parse(); err != nil {
+		return nil, err
+	}
+	if v.discounted.Valid {
+		price, ok := new(big.Rat).SetString(v.discounted.String)
+		if !ok {
+			return nil, &domain.CorruptProductError{ProductID: v.ProductID, Violations: []domain.Violation{
+				{Field: m_product_view.DiscountedPrice, Err: fmt.Errorf("malformed value %q", v.discounted.String)},
+			}}
+		}
+		v.discountedPrice = price
+	}
+	return v, nil
+}
+
+func scanViewAsView(s scanner) (*contracts.ProductView, error) {
+	v, err := scanView(s)
+	if err != nil {
+		return nil, err
+	}
+	view := toView(&v.productRow)
+	view.DiscountedPrice = v.discountedPrice
+	view.SearchText = v.searchText
+	return view, nil
+}
+
+// discountPercent parses the stored rational, e.g. "25/2"; a plain decimal
+// reads back too.
+func (d *productRow) discountPercent() *big.Rat {
+	if !d.discount.Valid {
+		return nil
+	}
+	r, ok := new(big.Rat).SetString(d.discount.String)
+	if !ok {
+		return nil
+	}
+	return r
+}
+
+func toRecord(d *productRow) *domain.ProductRecord {
+	return &domain.ProductRecord{
+		ID:              d.ProductID,
+		Name:            d.Name,
+		Description:     d.description.String,
+		Category:        d.Category,
+		BasePrice:       new(big.Rat).Set(&d.BasePrice),
+		DiscountPercent: d.discountPercent(),
+		DiscountStart:   d.start.ptr(),
+		DiscountEnd:     d.end.ptr(),
+		Status:          d.Status,
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+		ArchivedAt:      d.archivedAt.ptr(),
+		Version:         d.Version,
+	}
+}
+
+func toView(d *productRow) *contracts.ProductView {
+	return &contracts.ProductView{
+		ID:                d.ProductID,
+		Name:              d.Name,
+		Description:       d.description.String,
+		Category:          d.Category,
+		BasePrice:         new(big.Rat).Set(&d.BasePrice),
+		DiscountPercent:   d.discountPercent(),
+		DiscountStartDate: d.start.ptr(),
+		DiscountEndDate:   d.end.ptr(),
+		Status:            d.Status,
+		CreatedAt:         d.CreatedAt,
+		UpdatedAt:         d.UpdatedAt,
+		Version:           d.Version,
+	}
+}
+
+// timestamp scans a nullable column written in the driver's TimeFormat.
+type timestamp struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (t *timestamp) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = timestamp{}
+		return nil
+	case string:
+		parsed, err := sqlitedriver.ParseTime(v)
+		if err != nil {
+			return err
+		}
+		*t = timestamp{Time: parsed, Valid: true}
+		return nil
+	}
+	return fmt.Errorf("sqlite: cannot scan %T into a timestamp", src)
+}
+
+func (t timestamp) ptr() *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	u := t.Time
+	return &u
+}
@@ -0,0 +1,96 @@
+package writes
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/models/m_outbox"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/commitplan"
+)
+
+// Outbox is the write side of the outbox contracts: inserts from usecases,
+// and the relay's and admin's status changes.
+type Outbox struct {
+	model *m_outbox.Model
+}
+
+func NewOutbox() Outbox {
+	return Outbox{model: m_outbox.New()}
+}
+
+func (w Outbox) InsertMut(event contracts.OutboxEvent) *committer.Mutation {
+	data := &m_outbox.Data{
+		EventID:     event.ID,
+		EventType:   event.EventType,
+		AggregateID: event.AggregateID,
+		Sequence:    event.Sequence,
+		Payload:     event.Payload,
+		Status:      contracts.OutboxStatusPending,
+		CreatedAt:   event.CreatedAt,
+	}
+	if !event.Metadata.IsZero() {
+		data.Metadata, _ = json.Marshal(event.Metadata) // plain strings, can't fail
+	}
+	return w.model.InsertMap(w.model.ToRow(data))
+}
+
+func (w Outbox) ClaimMut(eventID, owner string, leaseExpiresAt time.Time) *committer.Mutation {
+	return w.model.UpdateMap(eventID, map[string]interface{}{
+		m_outbox.ClaimedBy:      owner,
+		m_outbox.LeaseExpiresAt: leaseExpiresAt,
+	})
+}
+
+func (w Outbox) MarkProcessedMut(eventID string, processedAt time.Time) *committer.Mutation {
+	return w.model.UpdateMap(eventID, map[string]interface{}{
+		m_outbox.Status:         contracts.OutboxStatusProcessed,
+		m_outbox.ProcessedAt:    processedAt,
+		m_outbox.ClaimedBy:      nil,
+		m_outbox.LeaseExpiresAt: nil,
+	})
+}
+
+func (w Outbox) RetryLaterMut(eventID string, attempts int64, lastError string, nextAttemptAt time.Time) *committer.Mutation {
+	return w.model.UpdateMap(eventID, map[string]interface{}{
+		m_outbox.Attempts:       attempts,
+		m_outbox.LastError:      lastError,
+		m_outbox.NextAttemptAt:  nextAttemptAt,
+		m_outbox.ClaimedBy:      nil,
+		m_outbox.LeaseExpiresAt: nil,
+	})
+}
+
+// DeadLetterMut parks an event for good. processed_at records when the
+// event reached a terminal status, whichever one it was.
+func (w Outbox) DeadLetterMut(eventID string, attempts int64, lastError string, at time.Time) *committer.Mutation {
+	return w.model.UpdateMap(eventID, map[string]interface{}{
+		m_outbox.Status:         contracts.OutboxStatusDeadLetter,
+		m_outbox.Attempts:       attempts,
+		m_outbox.LastError:      lastError,
+		m_outbox.NextAttemptAt:  nil,
+		m_outbox.ProcessedAt:    at,
+		m_outbox.ClaimedBy:      nil,
+		m_outbox.LeaseExpiresAt: nil,
+	})
+}
+
+// RequeueMut puts an event back in the pending queue with a fresh attempt
+// budget.
+func (w Outbox) RequeueMut(eventID string) *committer.Mutation {
+	return w.model.UpdateMap(eventID, map[string]interface{}{
+		m_outbox.Status:         contracts.OutboxStatusPending,
+		m_outbox.Attempts:       int64(0),
+		m_outbox.LastError:      nil,
+		m_outbox.NextAttemptAt:  nil,
+		m_outbox.ProcessedAt:    nil,
+		m_outbox.ClaimedBy:      nil,
+		m_outbox.LeaseExpiresAt: nil,
+	})
+}
+
+// DeleteMut removes an event row.
+func (w Outbox) DeleteMut(eventID string) *committer.Mutation {
+	return commitplan.Delete(m_outbox.Table, commitplan.KeyOf(m_outbox.EventID, eventID))
+}
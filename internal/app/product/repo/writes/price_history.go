@@ -0,0 +1,27 @@
+package writes
+
+import (
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/models/m_price_history"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+// PriceHistory is the write side of contracts.PriceHistoryRepository.
+type PriceHistory struct {
+	model *m_price_history.Model
+}
+
+func NewPriceHistory() PriceHistory {
+	return PriceHistory{model: m_price_history.New()}
+}
+
+func (w PriceHistory) InsertMut(entry contracts.PriceChange) *committer.Mutation {
+	data := &m_price_history.Data{
+		ProductID: entry.ProductID,
+		ChangeID:  entry.ID,
+		OldPrice:  *entry.OldPrice.Amount(),
+		NewPrice:  *entry.NewPrice.Amount(),
+		ChangedAt: entry.ChangedAt,
+	}
+	return w.model.InsertMap(w.model.ToRow(data))
+}
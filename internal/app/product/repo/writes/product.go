@@ -0,0 +1,139 @@
+// Package writes builds the storage-neutral mutations behind the
+// repositories' *Mut methods. Every backend embeds these, so a plan means
+// the same thing whichever driver commits it.
+package writes
+
+import (
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/models/m_product"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/commitplan"
+)
+
+// Products is the write side of contracts.ProductRepository.
+type Products struct {
+	model *m_product.Model
+}
+
+func NewProducts() Products {
+	return Products{model: m_product.New()}
+}
+
+func (w Products) InsertMut(p *domain.Product) *committer.Mutation {
+	values := map[string]interface{}{
+		m_product.ProductID:   p.ID(),
+		m_product.Name:        p.Name(),
+		m_product.Description: p.Description(),
+		m_product.Category:    p.Category(),
+		m_product.BasePrice:   *p.BasePrice().Amount(),
+		m_product.Status:      string(p.Status()),
+		m_product.CreatedAt:   p.CreatedAt(),
+		m_product.UpdatedAt:   p.UpdatedAt(),
+		m_product.Version:     p.Version(),
+	}
+
+	if d := p.Discount(); d != nil {
+		pct := d.Percentage()
+		values[m_product.DiscountPercent] = *pct
+		values[m_product.DiscountStartDate] = d.StartDate()
+		values[m_product.DiscountEndDate] = d.EndDate()
+	}
+	if p.ArchivedAt() != nil {
+		values[m_product.ArchivedAt] = *p.ArchivedAt()
+	}
+
+	return w.model.InsertMap(values)
+}
+
+// UpdateMut writes only the dirty fields, plus updated_at and version. It
+// returns nil when nothing changed.
+func (w Products) UpdateMut(p *domain.Product) *committer.Mutation {
+	ch := p.Changes()
+	if !ch.HasChanges() {
+		return nil
+	}
+
+	updates := map[string]interface{}{
+		m_product.UpdatedAt: p.UpdatedAt(),
+		m_product.Version:   p.Version(),
+	}
+
+	if ch.Dirty(domain.FieldName) {
+		updates[m_product.Name] = p.Name()
+	}
+	if ch.Dirty(domain.FieldDescription) {
+		updates[m_product.Description] = p.Description()
+	}
+	if ch.Dirty(domain.FieldCategory) {
+		updates[m_product.Category] = p.Category()
+	}
+	if ch.Dirty(domain.FieldBasePrice) {
+		updates[m_product.BasePrice] = *p.BasePrice().Amount()
+	}
+	if ch.Dirty(domain.FieldStatus) {
+		updates[m_product.Status] = string(p.Status())
+		if p.ArchivedAt() != nil {
+			updates[m_product.ArchivedAt] = *p.ArchivedAt()
+		}
+	}
+	if ch.Dirty(domain.FieldDiscount) {
+		if d := p.Discount(); d != nil {
+			pct := d.Percentage()
+			updates[m_product.DiscountPercent] = *pct
+			updates[m_product.DiscountStartDate] = d.StartDate()
+			updates[m_product.DiscountEndDate] = d.EndDate()
+		} else {
+			updates[m_product.DiscountPercent] = nil
+			updates[m_product.DiscountStartDate] = nil
+			updates[m_product.DiscountEndDate] = nil
+		}
+	}
+
+	return w.model.UpdateMap(p.ID(), updates)
+}
+
+// RepairMut writes the columns domain.ProductRecord.Repair can change: the
+// discount and archived_at. It leaves updated_at and version alone, since
+// a repair isn't an event and replaying the outbox must still match.
+func (w Products) RepairMut(r *domain.ProductRecord) *committer.Mutation {
+	updates := map[string]interface{}{
+		m_product.DiscountPercent:   nil,
+		m_product.DiscountStartDate: nil,
+		m_product.DiscountEndDate:   nil,
+		m_product.ArchivedAt:        nil,
+	}
+	if r.DiscountPercent != nil {
+		updates[m_product.DiscountPercent] = *r.DiscountPercent
+	}
+	if r.DiscountStart != nil {
+		updates[m_product.DiscountStartDate] = *r.DiscountStart
+	}
+	if r.DiscountEnd != nil {
+		updates[m_product.DiscountEndDate] = *r.DiscountEnd
+	}
+	if r.ArchivedAt != nil {
+		updates[m_product.ArchivedAt] = *r.ArchivedAt
+	}
+	return w.model.UpdateMap(r.ID, updates)
+}
+
+// VersionCheck guards an update so it only lands if nobody else has written
+// the row since the aggregate was loaded.
+func (w Products) VersionCheck(p *domain.Product) committer.Precondition {
+	return w.versionCheck(p.ID(), p.OriginalVersion())
+}
+
+// RecordVersionCheck guards a RepairMut the same way.
+func (w Products) RecordVersionCheck(r *domain.ProductRecord) committer.Precondition {
+	return w.versionCheck(r.ID, r.Version)
+}
+
+func (w Products) versionCheck(id string, expected int64) committer.Precondition {
+	return &committer.VersionCheck{
+		Table:    m_product.Table,
+		Key:      commitplan.KeyOf(m_product.ProductID, id),
+		Column:   m_product.Version,
+		Expected: expected,
+		Err:      domain.ErrVersionConflict,
+	}
+}
@@ -0,0 +1,77 @@
+package writes
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/models/m_product_view"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+// ProductViews is the write side of contracts.ProductViewStore.
+type ProductViews struct {
+	model *m_product_view.Model
+}
+
+func NewProductViews() ProductViews {
+	return ProductViews{model: m_product_view.New()}
+}
+
+// UpsertViewMut writes every column, NULLs included, so an upsert over an
+// older view clears a discount the product no longer has. It precomputes
+// discounted_price and search_text; see contracts.ProductView.
+func (w ProductViews) UpsertViewMut(p *domain.Product, projectedAt time.Time) *committer.Mutation {
+	values := map[string]interface{}{
+		m_product_view.ProductID:         p.ID(),
+		m_product_view.Name:              p.Name(),
+		m_product_view.Description:       p.Description(),
+		m_product_view.Category:          p.Category(),
+		m_product_view.BasePrice:         *p.BasePrice().Amount(),
+		m_product_view.DiscountPercent:   nil,
+		m_product_view.DiscountStartDate: nil,
+		m_product_view.DiscountEndDate:   nil,
+		m_product_view.DiscountedPrice:   nil,
+		m_product_view.Status:            string(p.Status()),
+		m_product_view.SearchText:        SearchText(p.Name(), p.Category()),
+		m_product_view.CreatedAt:         p.CreatedAt(),
+		m_product_view.UpdatedAt:         p.UpdatedAt(),
+		m_product_view.ArchivedAt:        nil,
+		m_product_view.Version:           p.Version(),
+		m_product_view.ProjectedAt:       projectedAt,
+	}
+
+	if d := p.Discount(); d != nil {
+		pct := d.Percentage()
+		values[m_product_view.DiscountPercent] = *pct
+		values[m_product_view.DiscountStartDate] = d.StartDate()
+		values[m_product_view.DiscountEndDate] = d.EndDate()
+		values[m_product_view.DiscountedPrice] = discountedPrice(p.BasePrice(), pct)
+	}
+	if p.ArchivedAt() != nil {
+		values[m_product_view.ArchivedAt] = *p.ArchivedAt()
+	}
+
+	return w.model.UpsertMap(values)
+}
+
+// discountedPrice is what the product costs while its discount is running,
+// rounded to domain.PriceScale so every backend's NUMERIC can hold it. The
+// read side serves it while the stored discount window is open, instead of
+// pricing every read from the base price and discount.
+func discountedPrice(base *domain.Money, pct *big.Rat) big.Rat {
+	hundred := big.NewRat(100, 1)
+	factor := new(big.Rat).Sub(hundred, pct)
+	factor.Quo(factor, hundred)
+	price := new(big.Rat).Mul(base.Amount(), factor)
+	rounded, _ := new(big.Rat).SetString(price.FloatString(domain.PriceScale))
+	return *rounded
+}
+
+// SearchText is what product_views stores to search by: the name and
+// category, lower-cased, so a case-insensitive prefix search is a range scan
+// of idx_product_views_search.
+func SearchText(name, category string) string {
+	return strings.ToLower(name + " " + category)
+}
@@ -0,0 +1,39 @@
+package writes
+
+import (
+	"strings"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/models/m_product_quarantine"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+// Quarantine is the write side of contracts.QuarantineRepository.
+type Quarantine struct {
+	model *m_product_quarantine.Model
+}
+
+func NewQuarantine() Quarantine {
+	return Quarantine{model: m_product_quarantine.New()}
+}
+
+// QuarantineMut records a product's current violations, replacing whatever
+// an earlier scan found.
+func (w Quarantine) QuarantineMut(productID string, violations []domain.Violation, at time.Time) *committer.Mutation {
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = v.String()
+	}
+	return w.model.UpsertMap(w.model.ToRow(&m_product_quarantine.Data{
+		ProductID:  productID,
+		Violations: strings.Join(lines, "\n"),
+		FoundAt:    at,
+	}))
+}
+
+// ReleaseMut removes a product from quarantine; it is a no-op for products
+// that aren't in it.
+func (w Quarantine) ReleaseMut(productID string) *committer.Mutation {
+	return w.model.Delete(productID)
+}
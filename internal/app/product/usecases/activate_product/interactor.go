@@ -7,10 +7,12 @@ import (
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases"
 	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
 	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/catalog-proj/internal/pkg/eventmeta"
 )
 
 type Request struct {
-	ProductID string
+	ProductID       string
+	ExpectedVersion int64 // zero skips the optimistic-concurrency check
 }
 
 // --- Activate ---
@@ -18,14 +20,14 @@ type Request struct {
 type ActivateInteractor struct {
 	repo      contracts.ProductRepository
 	outbox    contracts.OutboxRepository
-	committer *committer.Committer
+	committer committer.Committer
 	clock     clock.Clock
 }
 
 func NewActivateInteractor(
 	repo contracts.ProductRepository,
 	outbox contracts.OutboxRepository,
-	cm *committer.Committer,
+	cm committer.Committer,
 	clk clock.Clock,
 ) *ActivateInteractor {
 	return &ActivateInteractor{
@@ -37,23 +39,33 @@ func NewActivateInteractor(
 }
 
 func (it *ActivateInteractor) Execute(ctx context.Context, req Request) error {
-	product, err := it.repo.FindByID(ctx, req.ProductID)
-	if err != nil {
-		return err
-	}
-
-	if err := product.Activate(it.clock.Now()); err != nil {
-		return err
-	}
-
-	plan := committer.NewPlan()
-	plan.Add(it.repo.UpdateMut(product))
-
-	for _, event := range product.DomainEvents() {
-		plan.Add(it.outbox.InsertMut(usecases.EnrichEvent(product.ID(), event)))
-	}
-
-	return it.committer.Apply(ctx, plan)
+	return it.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		product, err := it.repo.FindByID(ctx, req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if err := product.CheckVersion(req.ExpectedVersion); err != nil {
+			return nil, err
+		}
+
+		if err := product.Activate(it.clock.Now()); err != nil {
+			return nil, err
+		}
+
+		plan := committer.NewPlan()
+		plan.Require(it.repo.VersionCheck(product))
+		plan.Add(it.repo.UpdateMut(product))
+
+		for _, event := range product.DomainEvents() {
+			outboxEvent, err := usecases.EnrichEvent(product.ID(), event, eventmeta.FromContext(ctx))
+			if err != nil {
+				return nil, err
+			}
+			plan.Add(it.outbox.InsertMut(outboxEvent))
+		}
+
+		return plan, nil
+	})
 }
 
 // --- Deactivate ---
@@ -61,14 +73,14 @@ func (it *ActivateInteractor) Execute(ctx context.Context, req Request) error {
 type DeactivateInteractor struct {
 	repo      contracts.ProductRepository
 	outbox    contracts.OutboxRepository
-	committer *committer.Committer
+	committer committer.Committer
 	clock     clock.Clock
 }
 
 func NewDeactivateInteractor(
 	repo contracts.ProductRepository,
 	outbox contracts.OutboxRepository,
-	cm *committer.Committer,
+	cm committer.Committer,
 	clk clock.Clock,
 ) *DeactivateInteractor {
 	return &DeactivateInteractor{
@@ -80,23 +92,33 @@ func NewDeactivateInteractor(
 }
 
 func (it *DeactivateInteractor) Execute(ctx context.Context, req Request) error {
-	product, err := it.repo.FindByID(ctx, req.ProductID)
-	if err != nil {
-		return err
-	}
-
-	if err := product.Deactivate(it.clock.Now()); err != nil {
-		return err
-	}
-
-	plan := committer.NewPlan()
-	plan.Add(it.repo.UpdateMut(product))
-
-	for _, event := range product.DomainEvents() {
-		plan.Add(it.outbox.InsertMut(usecases.EnrichEvent(product.ID(), event)))
-	}
-
-	return it.committer.Apply(ctx, plan)
+	return it.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		product, err := it.repo.FindByID(ctx, req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if err := product.CheckVersion(req.ExpectedVersion); err != nil {
+			return nil, err
+		}
+
+		if err := product.Deactivate(it.clock.Now()); err != nil {
+			return nil, err
+		}
+
+		plan := committer.NewPlan()
+		plan.Require(it.repo.VersionCheck(product))
+		plan.Add(it.repo.UpdateMut(product))
+
+		for _, event := range product.DomainEvents() {
+			outboxEvent, err := usecases.EnrichEvent(product.ID(), event, eventmeta.FromContext(ctx))
+			if err != nil {
+				return nil, err
+			}
+			plan.Add(it.outbox.InsertMut(outboxEvent))
+		}
+
+		return plan, nil
+	})
 }
 
 // --- Archive ---
@@ -104,14 +126,14 @@ func (it *DeactivateInteractor) Execute(ctx context.Context, req Request) error
 type ArchiveInteractor struct {
 	repo      contracts.ProductRepository
 	outbox    contracts.OutboxRepository
-	committer *committer.Committer
+	committer committer.Committer
 	clock     clock.Clock
 }
 
 func NewArchiveInteractor(
 	repo contracts.ProductRepository,
 	outbox contracts.OutboxRepository,
-	cm *committer.Committer,
+	cm committer.Committer,
 	clk clock.Clock,
 ) *ArchiveInteractor {
 	return &ArchiveInteractor{
@@ -123,21 +145,31 @@ func NewArchiveInteractor(
 }
 
 func (it *ArchiveInteractor) Execute(ctx context.Context, req Request) error {
-	product, err := it.repo.FindByID(ctx, req.ProductID)
-	if err != nil {
-		return err
-	}
-
-	if err := product.Archive(it.clock.Now()); err != nil {
-		return err
-	}
-
-	plan := committer.NewPlan()
-	plan.Add(it.repo.UpdateMut(product))
-
-	for _, event := range product.DomainEvents() {
-		plan.Add(it.outbox.InsertMut(usecases.EnrichEvent(product.ID(), event)))
-	}
-
-	return it.committer.Apply(ctx, plan)
+	return it.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		product, err := it.repo.FindByID(ctx, req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if err := product.CheckVersion(req.ExpectedVersion); err != nil {
+			return nil, err
+		}
+
+		if err := product.Archive(it.clock.Now()); err != nil {
+			return nil, err
+		}
+
+		plan := committer.NewPlan()
+		plan.Require(it.repo.VersionCheck(product))
+		plan.Add(it.repo.UpdateMut(product))
+
+		for _, event := range product.DomainEvents() {
+			outboxEvent, err := usecases.EnrichEvent(product.ID(), event, eventmeta.FromContext(ctx))
+			if err != nil {
+				return nil, err
+			}
+			plan.Add(it.outbox.InsertMut(outboxEvent))
+		}
+
+		return plan, nil
+	})
 }
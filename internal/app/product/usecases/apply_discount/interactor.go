@@ -10,28 +10,30 @@ import (
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases"
 	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
 	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/catalog-proj/internal/pkg/eventmeta"
 )
 
 // --- Apply ---
 
 type ApplyRequest struct {
-	ProductID  string
-	Percentage *big.Rat
-	StartDate  time.Time
-	EndDate    time.Time
+	ProductID       string
+	Percentage      *big.Rat
+	StartDate       time.Time
+	EndDate         time.Time
+	ExpectedVersion int64 // zero skips the optimistic-concurrency check
 }
 
 type ApplyInteractor struct {
 	repo      contracts.ProductRepository
 	outbox    contracts.OutboxRepository
-	committer *committer.Committer
+	committer committer.Committer
 	clock     clock.Clock
 }
 
 func NewApplyInteractor(
 	repo contracts.ProductRepository,
 	outbox contracts.OutboxRepository,
-	cm *committer.Committer,
+	cm committer.Committer,
 	clk clock.Clock,
 ) *ApplyInteractor {
 	return &ApplyInteractor{
@@ -43,48 +45,59 @@ func NewApplyInteractor(
 }
 
 func (it *ApplyInteractor) Execute(ctx context.Context, req ApplyRequest) error {
-	product, err := it.repo.FindByID(ctx, req.ProductID)
-	if err != nil {
-		return err
-	}
-
-	discount, err := domain.NewDiscount(req.Percentage, req.StartDate, req.EndDate)
-	if err != nil {
-		return err
-	}
-
-	now := it.clock.Now()
-	if err := product.ApplyDiscount(discount, now); err != nil {
-		return err
-	}
-
-	plan := committer.NewPlan()
-	plan.Add(it.repo.UpdateMut(product))
-
-	for _, event := range product.DomainEvents() {
-		plan.Add(it.outbox.InsertMut(usecases.EnrichEvent(product.ID(), event)))
-	}
-
-	return it.committer.Apply(ctx, plan)
+	return it.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		product, err := it.repo.FindByID(ctx, req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if err := product.CheckVersion(req.ExpectedVersion); err != nil {
+			return nil, err
+		}
+
+		discount, err := domain.NewDiscount(req.Percentage, req.StartDate, req.EndDate)
+		if err != nil {
+			return nil, err
+		}
+
+		now := it.clock.Now()
+		if err := product.ApplyDiscount(discount, now); err != nil {
+			return nil, err
+		}
+
+		plan := committer.NewPlan()
+		plan.Require(it.repo.VersionCheck(product))
+		plan.Add(it.repo.UpdateMut(product))
+
+		for _, event := range product.DomainEvents() {
+			outboxEvent, err := usecases.EnrichEvent(product.ID(), event, eventmeta.FromContext(ctx))
+			if err != nil {
+				return nil, err
+			}
+			plan.Add(it.outbox.InsertMut(outboxEvent))
+		}
+
+		return plan, nil
+	})
 }
 
 // --- Remove ---
 
 type RemoveRequest struct {
-	ProductID string
+	ProductID       string
+	ExpectedVersion int64 // zero skips the optimistic-concurrency check
 }
 
 type RemoveInteractor struct {
 	repo      contracts.ProductRepository
 	outbox    contracts.OutboxRepository
-	committer *committer.Committer
+	committer committer.Committer
 	clock     clock.Clock
 }
 
 func NewRemoveInteractor(
 	repo contracts.ProductRepository,
 	outbox contracts.OutboxRepository,
-	cm *committer.Committer,
+	cm committer.Committer,
 	clk clock.Clock,
 ) *RemoveInteractor {
 	return &RemoveInteractor{
@@ -96,21 +109,31 @@ func NewRemoveInteractor(
 }
 
 func (it *RemoveInteractor) Execute(ctx context.Context, req RemoveRequest) error {
-	product, err := it.repo.FindByID(ctx, req.ProductID)
-	if err != nil {
-		return err
-	}
-
-	if err := product.RemoveDiscount(it.clock.Now()); err != nil {
-		return err
-	}
-
-	plan := committer.NewPlan()
-	plan.Add(it.repo.UpdateMut(product))
-
-	for _, event := range product.DomainEvents() {
-		plan.Add(it.outbox.InsertMut(usecases.EnrichEvent(product.ID(), event)))
-	}
-
-	return it.committer.Apply(ctx, plan)
+	return it.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		product, err := it.repo.FindByID(ctx, req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if err := product.CheckVersion(req.ExpectedVersion); err != nil {
+			return nil, err
+		}
+
+		if err := product.RemoveDiscount(it.clock.Now()); err != nil {
+			return nil, err
+		}
+
+		plan := committer.NewPlan()
+		plan.Require(it.repo.VersionCheck(product))
+		plan.Add(it.repo.UpdateMut(product))
+
+		for _, event := range product.DomainEvents() {
+			outboxEvent, err := usecases.EnrichEvent(product.ID(), event, eventmeta.FromContext(ctx))
+			if err != nil {
+				return nil, err
+			}
+			plan.Add(it.outbox.InsertMut(outboxEvent))
+		}
+
+		return plan, nil
+	})
 }
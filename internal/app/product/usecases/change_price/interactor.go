@@ -0,0 +1,92 @@
+package change_price
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/google/uuid"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases"
+	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/catalog-proj/internal/pkg/eventmeta"
+)
+
+type Request struct {
+	ProductID       string
+	NewPrice        *big.Rat
+	ExpectedVersion int64 // zero skips the optimistic-concurrency check
+}
+
+type Interactor struct {
+	repo      contracts.ProductRepository
+	history   contracts.PriceHistoryRepository
+	outbox    contracts.OutboxRepository
+	committer committer.Committer
+	clock     clock.Clock
+}
+
+func NewInteractor(
+	repo contracts.ProductRepository,
+	history contracts.PriceHistoryRepository,
+	outbox contracts.OutboxRepository,
+	cm committer.Committer,
+	clk clock.Clock,
+) *Interactor {
+	return &Interactor{
+		repo:      repo,
+		history:   history,
+		outbox:    outbox,
+		committer: cm,
+		clock:     clk,
+	}
+}
+
+func (it *Interactor) Execute(ctx context.Context, req Request) error {
+	newPrice, err := domain.NewMoneyFromRat(req.NewPrice)
+	if err != nil {
+		return err
+	}
+
+	return it.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		product, err := it.repo.FindByID(ctx, req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if err := product.CheckVersion(req.ExpectedVersion); err != nil {
+			return nil, err
+		}
+
+		oldPrice := product.BasePrice()
+		now := it.clock.Now()
+		if err := product.ChangePrice(newPrice, now); err != nil {
+			return nil, err
+		}
+
+		plan := committer.NewPlan()
+		plan.Require(it.repo.VersionCheck(product))
+		plan.Add(it.repo.UpdateMut(product))
+
+		if product.Changes().Dirty(domain.FieldBasePrice) {
+			plan.Add(it.history.InsertMut(contracts.PriceChange{
+				ID:        uuid.NewString(),
+				ProductID: product.ID(),
+				OldPrice:  oldPrice,
+				NewPrice:  newPrice,
+				ChangedAt: now,
+			}))
+		}
+
+		for _, event := range product.DomainEvents() {
+			outboxEvent, err := usecases.EnrichEvent(product.ID(), event, eventmeta.FromContext(ctx))
+			if err != nil {
+				return nil, err
+			}
+			plan.Add(it.outbox.InsertMut(outboxEvent))
+		}
+
+		return plan, nil
+	})
+}
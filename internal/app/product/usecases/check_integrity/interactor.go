@@ -0,0 +1,99 @@
+package check_integrity
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+type Request struct {
+	ProductID string
+	// Repair applies domain.ProductRecord.Repair and writes the result.
+	Repair bool
+	// Quarantine records the violations left after any repair, or takes a
+	// clean product out of quarantine.
+	Quarantine bool
+}
+
+// Report is the outcome of checking one product.
+type Report struct {
+	ProductID string
+	// Violations are what is still wrong with the row: everything, unless
+	// the request repaired some of it.
+	Violations []domain.Violation
+	// Repaired are the violations this run fixed.
+	Repaired []domain.Violation
+}
+
+func (r *Report) OK() bool { return len(r.Violations) == 0 }
+
+// Interactor checks a product row against every invariant the aggregate
+// enforces, the way ProductRepository.FindByID does, but reports what it
+// finds instead of failing on it.
+type Interactor struct {
+	records    contracts.ProductRecordStore
+	quarantine contracts.QuarantineRepository
+	committer  committer.Committer
+	clock      clock.Clock
+}
+
+func NewInteractor(
+	records contracts.ProductRecordStore,
+	quarantine contracts.QuarantineRepository,
+	cm committer.Committer,
+	clk clock.Clock,
+) *Interactor {
+	return &Interactor{
+		records:    records,
+		quarantine: quarantine,
+		committer:  cm,
+		clock:      clk,
+	}
+}
+
+// Execute reads, repairs and quarantines in one transaction. Repairs are
+// guarded by the row's version, so one racing a real update is retried
+// against the new row rather than overwriting it.
+func (it *Interactor) Execute(ctx context.Context, req Request) (*Report, error) {
+	report := &Report{ProductID: req.ProductID}
+	err := it.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		report.Violations, report.Repaired = nil, nil
+		plan := committer.NewPlan()
+
+		rec, err := it.records.FindRecord(ctx, req.ProductID)
+		var corrupt *domain.CorruptProductError
+		switch {
+		case errors.As(err, &corrupt):
+			// Undecodable: nothing to repair, but still worth recording.
+			report.Violations = corrupt.Violations
+		case err != nil:
+			return nil, err
+		default:
+			report.Violations = rec.Check()
+			if req.Repair && len(report.Violations) > 0 {
+				if report.Repaired = rec.Repair(); len(report.Repaired) > 0 {
+					plan.Require(it.records.RecordVersionCheck(rec))
+					plan.Add(it.records.RepairMut(rec))
+					report.Violations = rec.Check()
+				}
+			}
+		}
+
+		if req.Quarantine {
+			if report.OK() {
+				plan.Add(it.quarantine.ReleaseMut(req.ProductID))
+			} else {
+				plan.Add(it.quarantine.QuarantineMut(req.ProductID, report.Violations, it.clock.Now()))
+			}
+		}
+		return plan, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
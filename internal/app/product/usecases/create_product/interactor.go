@@ -11,6 +11,7 @@ import (
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases"
 	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
 	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/catalog-proj/internal/pkg/eventmeta"
 )
 
 type Request struct {
@@ -23,14 +24,14 @@ type Request struct {
 type Interactor struct {
 	repo      contracts.ProductRepository
 	outbox    contracts.OutboxRepository
-	committer *committer.Committer
+	committer committer.Committer
 	clock     clock.Clock
 }
 
 func NewInteractor(
 	repo contracts.ProductRepository,
 	outbox contracts.OutboxRepository,
-	cm *committer.Committer,
+	cm committer.Committer,
 	clk clock.Clock,
 ) *Interactor {
 	return &Interactor{
@@ -57,7 +58,11 @@ func (it *Interactor) Execute(ctx context.Context, req Request) (string, error)
 	plan.Add(it.repo.InsertMut(product))
 
 	for _, event := range product.DomainEvents() {
-		plan.Add(it.outbox.InsertMut(usecases.EnrichEvent(product.ID(), event)))
+		outboxEvent, err := usecases.EnrichEvent(product.ID(), event, eventmeta.FromContext(ctx))
+		if err != nil {
+			return "", err
+		}
+		plan.Add(it.outbox.InsertMut(outboxEvent))
 	}
 
 	if err := it.committer.Apply(ctx, plan); err != nil {
@@ -0,0 +1,182 @@
+package usecases
+
+import (
+	"fmt"
+	"math/big"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/pkg/cloudevents"
+	productv1 "github.com/tshubham2/catalog-proj/proto/product/v1"
+)
+
+// eventMessageTypes maps each CloudEvents type to its events.proto message.
+var eventMessageTypes = func() map[string]protoreflect.MessageType {
+	out := map[string]protoreflect.MessageType{}
+	msgs := productv1.File_proto_product_v1_events_proto.Messages()
+	for i := 0; i < msgs.Len(); i++ {
+		md := msgs.Get(i)
+		eventType := proto.GetExtension(md.Options(), productv1.E_EventType).(string)
+		if eventType == "" {
+			continue
+		}
+		mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName())
+		if err != nil {
+			panic(err)
+		}
+		out[eventType] = mt
+	}
+	return out
+}()
+
+// DecodeEvent is the inverse of EnrichEvent: it reads an outbox row back into
+// the domain event it was built from, including the event's time, sequence
+// and field changes. Rows written before CloudEvents envelopes, or with a
+// dataschema other than the current one, can't be decoded.
+func DecodeEvent(row contracts.OutboxEvent) (domain.DomainEvent, error) {
+	ce, err := cloudevents.Parse(row.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("outbox event %s: %w", row.ID, err)
+	}
+	mt, ok := eventMessageTypes[ce.Type]
+	if !ok {
+		return nil, fmt.Errorf("outbox event %s: no events.proto message for type %q", row.ID, ce.Type)
+	}
+	msg := mt.New().Interface()
+	if schema := DataSchema(msg); ce.DataSchema != schema {
+		return nil, fmt.Errorf("outbox event %s: dataschema %q, want %q", row.ID, ce.DataSchema, schema)
+	}
+	if err := protojson.Unmarshal(ce.Data, msg); err != nil {
+		return nil, fmt.Errorf("outbox event %s: decode %s data: %w", row.ID, ce.Type, err)
+	}
+
+	event, changes, err := domainEvent(msg)
+	if err != nil {
+		return nil, fmt.Errorf("outbox event %s: %w", row.ID, err)
+	}
+	return domain.RestoreEvent(event, ce.Time, row.Sequence, changes), nil
+}
+
+// domainEvent is the inverse of buildPayload.
+func domainEvent(msg proto.Message) (domain.DomainEvent, []domain.FieldChange, error) {
+	switch m := msg.(type) {
+	case *productv1.ProductCreated:
+		price, err := moneyDomain(m.GetBasePrice())
+		if err != nil {
+			return nil, nil, err
+		}
+		return &domain.ProductCreatedEvent{
+			ProductID:   m.GetProductId(),
+			Name:        m.GetName(),
+			Category:    m.GetCategory(),
+			Description: m.GetDescription(),
+			BasePrice:   price,
+		}, nil, nil
+	case *productv1.ProductUpdated:
+		changes, err := changesDomain(m.GetChanges())
+		return &domain.ProductUpdatedEvent{
+			ProductID:   m.GetProductId(),
+			Name:        m.GetName(),
+			Description: m.GetDescription(),
+			Category:    m.GetCategory(),
+		}, changes, err
+	case *productv1.ProductActivated:
+		changes, err := changesDomain(m.GetChanges())
+		return &domain.ProductActivatedEvent{ProductID: m.GetProductId()}, changes, err
+	case *productv1.ProductDeactivated:
+		changes, err := changesDomain(m.GetChanges())
+		return &domain.ProductDeactivatedEvent{ProductID: m.GetProductId()}, changes, err
+	case *productv1.ProductArchived:
+		changes, err := changesDomain(m.GetChanges())
+		return &domain.ProductArchivedEvent{
+			ProductID:  m.GetProductId(),
+			ArchivedAt: m.GetArchivedAt().AsTime(),
+		}, changes, err
+	case *productv1.DiscountApplied:
+		changes, err := changesDomain(m.GetChanges())
+		return &domain.DiscountAppliedEvent{
+			ProductID:  m.GetProductId(),
+			Percentage: ratDomain(m.GetPercentage()),
+			StartDate:  m.GetStartDate().AsTime(),
+			EndDate:    m.GetEndDate().AsTime(),
+		}, changes, err
+	case *productv1.DiscountRemoved:
+		changes, err := changesDomain(m.GetChanges())
+		return &domain.DiscountRemovedEvent{ProductID: m.GetProductId()}, changes, err
+	case *productv1.PriceChanged:
+		oldPrice, err := moneyDomain(m.GetOldPrice())
+		if err != nil {
+			return nil, nil, err
+		}
+		newPrice, err := moneyDomain(m.GetNewPrice())
+		if err != nil {
+			return nil, nil, err
+		}
+		changes, err := changesDomain(m.GetChanges())
+		return &domain.PriceChangedEvent{
+			ProductID: m.GetProductId(),
+			OldPrice:  oldPrice,
+			NewPrice:  newPrice,
+		}, changes, err
+	default:
+		return nil, nil, fmt.Errorf("no domain event for %s", msg.ProtoReflect().Descriptor().FullName())
+	}
+}
+
+func changesDomain(changes []*productv1.FieldChange) ([]domain.FieldChange, error) {
+	out := make([]domain.FieldChange, 0, len(changes))
+	for _, c := range changes {
+		oldValue, err := fieldValueDomain(c.GetField(), c.GetOldValue())
+		if err != nil {
+			return nil, err
+		}
+		newValue, err := fieldValueDomain(c.GetField(), c.GetNewValue())
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, domain.FieldChange{Field: c.GetField(), Old: oldValue, New: newValue})
+	}
+	return out, nil
+}
+
+// fieldValueDomain gives a decoded value the domain type FieldChange
+// documents for field.
+func fieldValueDomain(field string, v *productv1.FieldValue) (interface{}, error) {
+	switch field {
+	case domain.FieldName, domain.FieldDescription, domain.FieldCategory:
+		return v.GetText(), nil
+	case domain.FieldStatus:
+		return domain.ProductStatus(v.GetText()), nil
+	case domain.FieldBasePrice:
+		return moneyDomain(v.GetAmount())
+	case domain.FieldDiscount:
+		d := v.GetDiscount()
+		if d == nil {
+			return (*domain.Discount)(nil), nil
+		}
+		return domain.NewDiscount(ratDomain(d.GetPercentage()), d.GetStartDate().AsTime(), d.GetEndDate().AsTime())
+	default:
+		return nil, fmt.Errorf("unknown changed field %q", field)
+	}
+}
+
+func moneyDomain(r *productv1.Rational) (*domain.Money, error) {
+	if r == nil {
+		return nil, nil
+	}
+	return domain.NewMoney(r.GetNumerator(), r.GetDenominator())
+}
+
+// ratDomain reads a missing or malformed rational as zero, which the domain
+// rejects as a discount percentage rather than panicking on.
+func ratDomain(r *productv1.Rational) *big.Rat {
+	if r == nil || r.GetDenominator() == 0 {
+		return new(big.Rat)
+	}
+	return big.NewRat(r.GetNumerator(), r.GetDenominator())
+}
@@ -2,49 +2,204 @@ package usecases
 
 import (
 	"encoding/json"
+	"fmt"
+	"math/big"
 
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
 	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/pkg/cloudevents"
+	"github.com/tshubham2/catalog-proj/internal/pkg/eventmeta"
+	productv1 "github.com/tshubham2/catalog-proj/proto/product/v1"
 )
 
-// EnrichEvent turns a domain event into an OutboxEvent with a JSON payload.
-func EnrichEvent(aggregateID string, event domain.DomainEvent) contracts.OutboxEvent {
-	raw, _ := json.Marshal(buildPayload(event))
+// EventSource is the CloudEvents source of every product event.
+const EventSource = "/catalog/products"
+
+// eventJSON is the wire format of event data: proto3 JSON with the field
+// names from events.proto, and every field present.
+var eventJSON = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// DataSchema identifies the events.proto message and schema version an
+// event's data follows.
+func DataSchema(msg proto.Message) string {
+	desc := msg.ProtoReflect().Descriptor()
+	version := proto.GetExtension(desc.Options(), productv1.E_EventSchemaVersion).(int32)
+	return fmt.Sprintf("urn:catalog:events:%s:v%d", desc.FullName(), version)
+}
+
+// EnrichEvent turns a domain event into an OutboxEvent whose payload is a
+// CloudEvents 1.0 structured envelope around the event data. It fails for
+// event types buildPayload doesn't know, rather than publishing them empty.
+//
+// meta is what the caller put in the context (see eventmeta.FromContext).
+// An event caused by nothing traceable starts its own correlation chain.
+func EnrichEvent(aggregateID string, event domain.DomainEvent, meta eventmeta.Metadata) (contracts.OutboxEvent, error) {
+	msg, err := buildPayload(event)
+	if err != nil {
+		return contracts.OutboxEvent{}, err
+	}
+	data, err := eventJSON.Marshal(msg)
+	if err != nil {
+		return contracts.OutboxEvent{}, fmt.Errorf("marshal %s data: %w", event.EventType(), err)
+	}
+
+	id := uuid.NewString()
+	if meta.CorrelationID == "" {
+		meta.CorrelationID = id
+	}
+	if meta.CausationID == "" {
+		meta.CausationID = meta.CorrelationID
+	}
+
+	raw, err := json.Marshal(cloudevents.Event{
+		SpecVersion:     cloudevents.SpecVersion,
+		ID:              id,
+		Source:          EventSource,
+		Type:            event.EventType(),
+		Subject:         aggregateID,
+		Time:            event.OccurredAt().UTC(),
+		DataContentType: "application/json",
+		DataSchema:      DataSchema(msg),
+		Sequence:        cloudevents.FormatSequence(event.Sequence()),
+		TraceParent:     meta.TraceParent,
+		TraceState:      meta.TraceState,
+		Data:            data,
+	})
+	if err != nil {
+		return contracts.OutboxEvent{}, fmt.Errorf("marshal %s envelope: %w", event.EventType(), err)
+	}
 	return contracts.OutboxEvent{
-		ID:          uuid.NewString(),
+		ID:          id,
 		EventType:   event.EventType(),
 		AggregateID: aggregateID,
+		Sequence:    event.Sequence(),
 		Payload:     raw,
 		CreatedAt:   event.OccurredAt(),
-	}
+		Metadata:    meta,
+	}, nil
 }
 
-func buildPayload(event domain.DomainEvent) interface{} {
+// buildPayload maps every domain event type to its events.proto message.
+// outbox_test.go checks that no DomainEvent type is missing from the switch.
+func buildPayload(event domain.DomainEvent) (proto.Message, error) {
+	var msg proto.Message
 	switch e := event.(type) {
 	case *domain.ProductCreatedEvent:
-		return map[string]interface{}{
-			"product_id": e.ProductID,
-			"name":       e.Name,
-			"category":   e.Category,
+		msg = &productv1.ProductCreated{
+			ProductId:   e.ProductID,
+			Name:        e.Name,
+			Category:    e.Category,
+			Description: e.Description,
+			BasePrice:   moneyProto(e.BasePrice),
 		}
 	case *domain.ProductUpdatedEvent:
-		return map[string]interface{}{"product_id": e.ProductID}
+		msg = &productv1.ProductUpdated{
+			ProductId:   e.ProductID,
+			Name:        e.Name,
+			Description: e.Description,
+			Category:    e.Category,
+			Changes:     changesProto(e.Changes()),
+		}
 	case *domain.ProductActivatedEvent:
-		return map[string]interface{}{"product_id": e.ProductID}
+		msg = &productv1.ProductActivated{
+			ProductId: e.ProductID,
+			Changes:   changesProto(e.Changes()),
+		}
 	case *domain.ProductDeactivatedEvent:
-		return map[string]interface{}{"product_id": e.ProductID}
+		msg = &productv1.ProductDeactivated{
+			ProductId: e.ProductID,
+			Changes:   changesProto(e.Changes()),
+		}
+	case *domain.ProductArchivedEvent:
+		msg = &productv1.ProductArchived{
+			ProductId:  e.ProductID,
+			ArchivedAt: timestamppb.New(e.ArchivedAt),
+			Changes:    changesProto(e.Changes()),
+		}
 	case *domain.DiscountAppliedEvent:
-		return map[string]interface{}{
-			"product_id": e.ProductID,
-			"percentage": e.Percentage.FloatString(2),
-			"start_date": e.StartDate,
-			"end_date":   e.EndDate,
+		msg = &productv1.DiscountApplied{
+			ProductId:  e.ProductID,
+			Percentage: ratProto(e.Percentage),
+			StartDate:  timestamppb.New(e.StartDate),
+			EndDate:    timestamppb.New(e.EndDate),
+			Changes:    changesProto(e.Changes()),
 		}
 	case *domain.DiscountRemovedEvent:
-		return map[string]interface{}{"product_id": e.ProductID}
+		msg = &productv1.DiscountRemoved{
+			ProductId: e.ProductID,
+			Changes:   changesProto(e.Changes()),
+		}
+	case *domain.PriceChangedEvent:
+		msg = &productv1.PriceChanged{
+			ProductId: e.ProductID,
+			OldPrice:  moneyProto(e.OldPrice),
+			NewPrice:  moneyProto(e.NewPrice),
+			Changes:   changesProto(e.Changes()),
+		}
 	default:
-		return map[string]interface{}{}
+		return nil, fmt.Errorf("no outbox payload for domain event %T (%s)", event, event.EventType())
+	}
+	return msg, nil
+}
+
+func changesProto(changes []domain.FieldChange) []*productv1.FieldChange {
+	out := make([]*productv1.FieldChange, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, &productv1.FieldChange{
+			Field:    c.Field,
+			OldValue: fieldValueProto(c.Old),
+			NewValue: fieldValueProto(c.New),
+		})
+	}
+	return out
+}
+
+// fieldValueProto converts a FieldChange value; nil values, including typed
+// nil pointers, come out unset.
+func fieldValueProto(v interface{}) *productv1.FieldValue {
+	switch v := v.(type) {
+	case string:
+		return &productv1.FieldValue{Value: &productv1.FieldValue_Text{Text: v}}
+	case domain.ProductStatus:
+		return &productv1.FieldValue{Value: &productv1.FieldValue_Text{Text: string(v)}}
+	case *domain.Money:
+		if v == nil {
+			return nil
+		}
+		return &productv1.FieldValue{Value: &productv1.FieldValue_Amount{Amount: moneyProto(v)}}
+	case *domain.Discount:
+		if v == nil {
+			return nil
+		}
+		return &productv1.FieldValue{Value: &productv1.FieldValue_Discount{Discount: &productv1.Discount{
+			Percentage: ratProto(v.Percentage()),
+			StartDate:  timestamppb.New(v.StartDate()),
+			EndDate:    timestamppb.New(v.EndDate()),
+		}}}
+	default:
+		return nil
+	}
+}
+
+func moneyProto(m *domain.Money) *productv1.Rational {
+	if m == nil {
+		return nil
+	}
+	return ratProto(m.Amount())
+}
+
+func ratProto(r *big.Rat) *productv1.Rational {
+	if r == nil {
+		return nil
+	}
+	return &productv1.Rational{
+		Numerator:   r.Num().Int64(),
+		Denominator: r.Denom().Int64(),
+		Decimal:     domain.FormatDecimal(r),
 	}
 }
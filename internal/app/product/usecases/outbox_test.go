@@ -0,0 +1,367 @@
+package usecases_test
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases"
+	"github.com/tshubham2/catalog-proj/internal/pkg/cloudevents"
+	"github.com/tshubham2/catalog-proj/internal/pkg/eventmeta"
+	productv1 "github.com/tshubham2/catalog-proj/proto/product/v1"
+)
+
+// allEvents drives a product through every operation that records an
+// event and returns what it recorded.
+func allEvents(t *testing.T) []domain.DomainEvent {
+	t.Helper()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	price, err := domain.NewMoney(1999, 100)
+	require.NoError(t, err)
+	p, err := domain.NewProduct("product-1", "Widget", "A widget", "tools", price, now)
+	require.NoError(t, err)
+
+	require.NoError(t, p.UpdateDetails("Gadget", "A gadget", "gizmos", now))
+	require.NoError(t, p.Deactivate(now))
+	require.NoError(t, p.Activate(now))
+
+	discount, err := domain.NewDiscount(big.NewRat(25, 2), now.Add(-time.Hour), now.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, p.ApplyDiscount(discount, now))
+	require.NoError(t, p.RemoveDiscount(now))
+
+	newPrice, err := domain.NewMoney(2499, 100)
+	require.NoError(t, err)
+	require.NoError(t, p.ChangePrice(newPrice, now))
+	require.NoError(t, p.Archive(now))
+
+	return p.DomainEvents()
+}
+
+// eventMessages maps each CloudEvents type to its events.proto message.
+func eventMessages() map[string]protoreflect.MessageDescriptor {
+	out := map[string]protoreflect.MessageDescriptor{}
+	msgs := productv1.File_proto_product_v1_events_proto.Messages()
+	for i := 0; i < msgs.Len(); i++ {
+		md := msgs.Get(i)
+		if eventType := proto.GetExtension(md.Options(), productv1.E_EventType).(string); eventType != "" {
+			out[eventType] = md
+		}
+	}
+	return out
+}
+
+func TestEnrichEvent_RoundTripsEveryEvent(t *testing.T) {
+	messages := eventMessages()
+	covered := map[string]bool{}
+
+	for _, event := range allEvents(t) {
+		t.Run(event.EventType(), func(t *testing.T) {
+			md, ok := messages[event.EventType()]
+			require.True(t, ok, "no events.proto message declares event_type %q", event.EventType())
+			covered[event.EventType()] = true
+
+			out, err := usecases.EnrichEvent("product-1", event, eventmeta.Metadata{})
+			require.NoError(t, err)
+			ce, err := cloudevents.Parse(out.Payload)
+			require.NoError(t, err)
+			assert.Equal(t, out.ID, ce.ID)
+			assert.Equal(t, event.EventType(), ce.Type)
+			assert.Equal(t, "product-1", ce.Subject)
+			assert.Equal(t, cloudevents.FormatSequence(event.Sequence()), ce.Sequence)
+			assert.True(t, strings.HasPrefix(ce.DataSchema, "urn:catalog:events:"+string(md.FullName())+":v"), ce.DataSchema)
+
+			mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName())
+			require.NoError(t, err)
+			msg := mt.New().Interface()
+			require.NoError(t, protojson.Unmarshal(ce.Data, msg))
+
+			assertDecoded(t, event, msg)
+		})
+	}
+
+	for eventType := range messages {
+		assert.True(t, covered[eventType], "events.proto declares %q but no domain operation produced it", eventType)
+	}
+}
+
+// TestBuildPayload_CoversEveryDomainEvent reads the source so that a new
+// DomainEvent type fails here even before anything emits it.
+func TestBuildPayload_CoversEveryDomainEvent(t *testing.T) {
+	declared := domainEventTypes(t)
+	require.NotEmpty(t, declared)
+
+	mapped := payloadCases(t)
+	for name := range declared {
+		assert.True(t, mapped[name], "buildPayload has no case for *domain.%s", name)
+	}
+
+	emitted := map[string]bool{}
+	for _, event := range allEvents(t) {
+		emitted[reflect.TypeOf(event).Elem().Name()] = true
+	}
+	for name := range declared {
+		assert.True(t, emitted[name], "allEvents never produces *domain.%s, so it isn't round-tripped", name)
+	}
+}
+
+func TestEnrichEvent_RecordsMetadata(t *testing.T) {
+	event := allEvents(t)[0]
+	meta := eventmeta.Metadata{
+		CorrelationID: "corr-1",
+		Actor:         "alice@example.com",
+		Client:        "pricing-tool/2.1",
+		TraceParent:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		TraceState:    "vendor=abc",
+	}
+
+	out, err := usecases.EnrichEvent("product-1", event, meta)
+	require.NoError(t, err)
+	want := meta
+	want.CausationID = "corr-1" // defaults to the correlation ID
+	assert.Equal(t, want, out.Metadata)
+
+	ce, err := cloudevents.Parse(out.Payload)
+	require.NoError(t, err)
+	assert.Equal(t, meta.TraceParent, ce.TraceParent)
+	assert.Equal(t, meta.TraceState, ce.TraceState)
+
+	// Without any metadata the event starts its own chain.
+	out, err = usecases.EnrichEvent("product-1", event, eventmeta.Metadata{})
+	require.NoError(t, err)
+	assert.Equal(t, out.ID, out.Metadata.CorrelationID)
+	assert.Equal(t, out.ID, out.Metadata.CausationID)
+	assert.Empty(t, out.Metadata.Actor)
+}
+
+func TestDecodeEvent_InvertsEnrichEvent(t *testing.T) {
+	events := allEvents(t)
+	decoded := make([]domain.DomainEvent, 0, len(events))
+	for _, event := range events {
+		out, err := usecases.EnrichEvent("product-1", event, eventmeta.Metadata{})
+		require.NoError(t, err)
+
+		back, err := usecases.DecodeEvent(out)
+		require.NoError(t, err, event.EventType())
+		assert.IsType(t, event, back)
+		assert.Equal(t, event.EventType(), back.EventType())
+		assert.Equal(t, event.Sequence(), back.Sequence())
+		assert.True(t, event.OccurredAt().Equal(back.OccurredAt()))
+		if len(event.Changes()) > 0 {
+			assert.Equal(t, event.Changes(), back.Changes())
+		}
+		decoded = append(decoded, back)
+	}
+
+	replayed, err := domain.Replay(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, "Gadget", replayed.Name())
+	assert.Equal(t, "24.99", replayed.BasePrice().String())
+	assert.Nil(t, replayed.Discount())
+	assert.Equal(t, domain.ProductStatusArchived, replayed.Status())
+	assert.Equal(t, int64(len(events)), replayed.Version())
+}
+
+func TestDecodeEvent_RejectsUndecodableRows(t *testing.T) {
+	_, err := usecases.DecodeEvent(contracts.OutboxEvent{
+		ID:      "legacy",
+		Payload: []byte(`{"product_id":"product-1"}`),
+	})
+	assert.Error(t, err, "bare payloads predate CloudEvents envelopes")
+
+	out, err := usecases.EnrichEvent("product-1", allEvents(t)[0], eventmeta.Metadata{})
+	require.NoError(t, err)
+	ce, err := cloudevents.Parse(out.Payload)
+	require.NoError(t, err)
+	ce.DataSchema = strings.Replace(ce.DataSchema, ":v1", ":v2", 1)
+	out.Payload, err = json.Marshal(ce)
+	require.NoError(t, err)
+	_, err = usecases.DecodeEvent(out)
+	assert.ErrorContains(t, err, "dataschema")
+}
+
+type unmappedEvent struct{}
+
+func (unmappedEvent) EventType() string             { return "product.unmapped" }
+func (unmappedEvent) OccurredAt() time.Time         { return time.Time{} }
+func (unmappedEvent) Sequence() int64               { return 1 }
+func (unmappedEvent) Changes() []domain.FieldChange { return nil }
+
+func TestEnrichEvent_RejectsUnmappedEvent(t *testing.T) {
+	_, err := usecases.EnrichEvent("product-1", unmappedEvent{}, eventmeta.Metadata{})
+	assert.ErrorContains(t, err, "product.unmapped")
+}
+
+// domainEventTypes returns the types in the domain package that have an
+// EventType method, i.e. that implement DomainEvent.
+func domainEventTypes(t *testing.T) map[string]bool {
+	t.Helper()
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, "../domain", func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	require.NoError(t, err)
+
+	out := map[string]bool{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv == nil || fn.Name.Name != "EventType" {
+					continue
+				}
+				recv := fn.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				out[recv.(*ast.Ident).Name] = true
+			}
+		}
+	}
+	return out
+}
+
+// payloadCases returns the domain types named in buildPayload's type switch.
+func payloadCases(t *testing.T) map[string]bool {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "outbox.go", nil, 0)
+	require.NoError(t, err)
+
+	out := map[string]bool{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "buildPayload" {
+			continue
+		}
+		ast.Inspect(fn, func(n ast.Node) bool {
+			clause, ok := n.(*ast.CaseClause)
+			if !ok {
+				return true
+			}
+			for _, expr := range clause.List {
+				if star, ok := expr.(*ast.StarExpr); ok {
+					if sel, ok := star.X.(*ast.SelectorExpr); ok {
+						out[sel.Sel.Name] = true
+					}
+				}
+			}
+			return true
+		})
+	}
+	return out
+}
+
+func assertDecoded(t *testing.T, event domain.DomainEvent, msg proto.Message) {
+	switch e := event.(type) {
+	case *domain.ProductCreatedEvent:
+		m := msg.(*productv1.ProductCreated)
+		assert.Equal(t, e.ProductID, m.GetProductId())
+		assert.Equal(t, e.Name, m.GetName())
+		assert.Equal(t, e.Category, m.GetCategory())
+		assert.Equal(t, e.Description, m.GetDescription())
+		assertRational(t, e.BasePrice.Amount(), m.GetBasePrice())
+	case *domain.ProductUpdatedEvent:
+		m := msg.(*productv1.ProductUpdated)
+		assert.Equal(t, e.ProductID, m.GetProductId())
+		assert.Equal(t, e.Name, m.GetName())
+		assert.Equal(t, e.Description, m.GetDescription())
+		assert.Equal(t, e.Category, m.GetCategory())
+		assertChanges(t, e.Changes(), m.GetChanges())
+	case *domain.ProductActivatedEvent:
+		m := msg.(*productv1.ProductActivated)
+		assert.Equal(t, e.ProductID, m.GetProductId())
+		assertChanges(t, e.Changes(), m.GetChanges())
+	case *domain.ProductDeactivatedEvent:
+		m := msg.(*productv1.ProductDeactivated)
+		assert.Equal(t, e.ProductID, m.GetProductId())
+		assertChanges(t, e.Changes(), m.GetChanges())
+	case *domain.ProductArchivedEvent:
+		m := msg.(*productv1.ProductArchived)
+		assert.Equal(t, e.ProductID, m.GetProductId())
+		assert.True(t, e.ArchivedAt.Equal(m.GetArchivedAt().AsTime()))
+		assertChanges(t, e.Changes(), m.GetChanges())
+	case *domain.DiscountAppliedEvent:
+		m := msg.(*productv1.DiscountApplied)
+		assert.Equal(t, e.ProductID, m.GetProductId())
+		assertRational(t, e.Percentage, m.GetPercentage())
+		assert.True(t, e.StartDate.Equal(m.GetStartDate().AsTime()))
+		assert.True(t, e.EndDate.Equal(m.GetEndDate().AsTime()))
+		assertChanges(t, e.Changes(), m.GetChanges())
+	case *domain.DiscountRemovedEvent:
+		m := msg.(*productv1.DiscountRemoved)
+		assert.Equal(t, e.ProductID, m.GetProductId())
+		assertChanges(t, e.Changes(), m.GetChanges())
+	case *domain.PriceChangedEvent:
+		m := msg.(*productv1.PriceChanged)
+		assert.Equal(t, e.ProductID, m.GetProductId())
+		assertRational(t, e.OldPrice.Amount(), m.GetOldPrice())
+		assertRational(t, e.NewPrice.Amount(), m.GetNewPrice())
+		assertChanges(t, e.Changes(), m.GetChanges())
+	default:
+		t.Errorf("no round-trip assertions for %T", event)
+	}
+}
+
+func assertRational(t *testing.T, want *big.Rat, got *productv1.Rational) {
+	t.Helper()
+	require.NotNil(t, got)
+	assert.Zero(t, want.Cmp(big.NewRat(got.GetNumerator(), got.GetDenominator())), "%s != %d/%d", want, got.GetNumerator(), got.GetDenominator())
+	assert.Equal(t, domain.FormatDecimal(want), got.GetDecimal())
+}
+
+func assertChanges(t *testing.T, want []domain.FieldChange, got []*productv1.FieldChange) {
+	t.Helper()
+	require.NotEmpty(t, want, "state-changing events record their changes")
+	require.Len(t, got, len(want))
+	for i := range want {
+		assert.Equal(t, want[i].Field, got[i].GetField())
+		assertFieldValue(t, want[i].Old, got[i].GetOldValue())
+		assertFieldValue(t, want[i].New, got[i].GetNewValue())
+	}
+}
+
+func assertFieldValue(t *testing.T, want interface{}, got *productv1.FieldValue) {
+	t.Helper()
+	switch w := want.(type) {
+	case string:
+		assert.Equal(t, w, got.GetText())
+	case domain.ProductStatus:
+		assert.Equal(t, string(w), got.GetText())
+	case *domain.Money:
+		if w == nil {
+			assert.Nil(t, got)
+			return
+		}
+		assertRational(t, w.Amount(), got.GetAmount())
+	case *domain.Discount:
+		if w == nil {
+			assert.Nil(t, got)
+			return
+		}
+		d := got.GetDiscount()
+		require.NotNil(t, d)
+		assertRational(t, w.Percentage(), d.GetPercentage())
+		assert.True(t, w.StartDate().Equal(d.GetStartDate().AsTime()))
+		assert.True(t, w.EndDate().Equal(d.GetEndDate().AsTime()))
+	default:
+		t.Errorf("unexpected field value type %T", want)
+	}
+}
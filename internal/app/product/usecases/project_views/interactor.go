@@ -0,0 +1,190 @@
+package project_views
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+// Report is the outcome of rebuilding one product's view.
+type Report struct {
+	ProductID string
+	// Version is the product version the view was written at.
+	Version int64
+	// FromRow means the events couldn't be replayed, e.g. because retention
+	// purged the oldest ones, and the view was copied from the products row;
+	// ReplayError says why.
+	FromRow     bool
+	ReplayError string
+}
+
+// Interactor keeps product_views in step with the outbox. The views hold
+// each product as its events leave it, so they are only as fresh as the
+// last projected event: the lag is published in the expvar metrics.
+type Interactor struct {
+	views     contracts.ProductViewStore
+	events    contracts.ProductEventStore
+	products  contracts.ProductRepository
+	decode    contracts.EventDecoder
+	committer committer.Committer
+	clock     clock.Clock
+}
+
+func NewInteractor(
+	views contracts.ProductViewStore,
+	events contracts.ProductEventStore,
+	products contracts.ProductRepository,
+	decode contracts.EventDecoder,
+	cm committer.Committer,
+	clk clock.Clock,
+) *Interactor {
+	return &Interactor{
+		views:     views,
+		events:    events,
+		products:  products,
+		decode:    decode,
+		committer: cm,
+		clock:     clk,
+	}
+}
+
+// Project applies one outbox event to its product's view. Its signature
+// matches outbox.Handler, so the relay can run it before publishing.
+//
+// It is idempotent: an event the view already reflects is skipped, so
+// redeliveries are harmless. The relay delivers each product's events in
+// sequence order, but a view can still be behind by more than one event,
+// e.g. when it was never built; then the product is rebuilt as Rebuild
+// does, instead of failing the event.
+func (it *Interactor) Project(ctx context.Context, row contracts.OutboxEvent) error {
+	event, err := it.decode(row)
+	if err != nil {
+		projectionMetrics.Add("failures", 1)
+		return err
+	}
+
+	var (
+		outcome string
+		fromRow bool
+	)
+	err = it.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		current, err := it.current(ctx, row.AggregateID)
+		if err != nil {
+			return nil, err
+		}
+
+		var next *domain.Product
+		fromRow = false
+		switch {
+		case current != nil && event.Sequence() <= current.Version():
+			outcome = "skipped"
+			return nil, nil
+		case current != nil && event.Sequence() == current.Version()+1:
+			if err := current.Apply(event); err != nil {
+				return nil, err
+			}
+			next, outcome = current, "projected"
+		case current == nil && event.Sequence() == 1:
+			if next, err = domain.Replay([]domain.DomainEvent{event}); err != nil {
+				return nil, err
+			}
+			outcome = "projected"
+		default:
+			var replayErr error
+			if next, replayErr, err = it.rebuild(ctx, row.AggregateID); err != nil {
+				return nil, err
+			}
+			outcome, fromRow = "rebuilt", replayErr != nil
+		}
+
+		plan := committer.NewPlan()
+		plan.Add(it.views.UpsertViewMut(next, it.clock.Now()))
+		return plan, nil
+	})
+	if err != nil {
+		projectionMetrics.Add("failures", 1)
+		return err
+	}
+
+	now := it.clock.Now()
+	projectionMetrics.Add(outcome, 1)
+	if fromRow {
+		projectionMetrics.Add("copied_from_row", 1)
+	}
+	lagSeconds.Set(now.Sub(row.CreatedAt).Seconds())
+	lastProjected.Set(now.Unix())
+	return nil
+}
+
+// Rebuild replaces a product's view with the replay of its whole history,
+// whatever the view held before. A product whose history can't be replayed
+// is copied from its products row instead, so every product keeps a view.
+func (it *Interactor) Rebuild(ctx context.Context, productID string) (*Report, error) {
+	report := &Report{ProductID: productID}
+	err := it.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		p, replayErr, err := it.rebuild(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		report.Version, report.FromRow, report.ReplayError = p.Version(), replayErr != nil, ""
+		if replayErr != nil {
+			report.ReplayError = replayErr.Error()
+		}
+
+		plan := committer.NewPlan()
+		plan.Add(it.views.UpsertViewMut(p, it.clock.Now()))
+		return plan, nil
+	})
+	if err != nil {
+		projectionMetrics.Add("failures", 1)
+		return nil, err
+	}
+	projectionMetrics.Add("rebuilt", 1)
+	if report.FromRow {
+		projectionMetrics.Add("copied_from_row", 1)
+	}
+	return report, nil
+}
+
+// current loads the product as its view has it, or nil when there is no
+// view. A view that breaks the aggregate's invariants counts as missing, so
+// it is rebuilt rather than trusted.
+func (it *Interactor) current(ctx context.Context, productID string) (*domain.Product, error) {
+	rec, err := it.views.FindViewRecord(ctx, productID)
+	var corrupt *domain.CorruptProductError
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound), errors.As(err, &corrupt):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	p, err := rec.Restore()
+	if errors.As(err, &corrupt) {
+		return nil, nil
+	}
+	return p, err
+}
+
+// rebuild replays the product's events, falling back to its products row
+// when they can't be replayed; replayErr says why it fell back.
+func (it *Interactor) rebuild(ctx context.Context, productID string) (p *domain.Product, replayErr, err error) {
+	p, err = it.events.LoadFromEvents(ctx, productID)
+	switch {
+	case err == nil:
+		return p, nil, nil
+	case errors.Is(err, contracts.ErrHistoryUnreplayable), errors.Is(err, domain.ErrProductNotFound):
+		replayErr = err
+	default:
+		return nil, nil, err
+	}
+
+	p, err = it.products.FindByID(ctx, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, replayErr, nil
+}
@@ -0,0 +1,18 @@
+package project_views
+
+import "expvar"
+
+// Projection counters, published under /debug/vars as "product_views":
+// projected, skipped (already in the view), rebuilt, copied_from_row,
+// failures, lag_seconds (how long the last projected event waited in the
+// outbox) and last_projected_unix.
+var (
+	projectionMetrics = expvar.NewMap("product_views")
+	lagSeconds        = new(expvar.Float)
+	lastProjected     = new(expvar.Int)
+)
+
+func init() {
+	projectionMetrics.Set("lag_seconds", lagSeconds)
+	projectionMetrics.Set("last_projected_unix", lastProjected)
+}
@@ -7,26 +7,28 @@ import (
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases"
 	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
 	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/catalog-proj/internal/pkg/eventmeta"
 )
 
 type Request struct {
-	ProductID   string
-	Name        *string
-	Description *string
-	Category    *string
+	ProductID       string
+	Name            *string
+	Description     *string
+	Category        *string
+	ExpectedVersion int64 // zero skips the optimistic-concurrency check
 }
 
 type Interactor struct {
 	repo      contracts.ProductRepository
 	outbox    contracts.OutboxRepository
-	committer *committer.Committer
+	committer committer.Committer
 	clock     clock.Clock
 }
 
 func NewInteractor(
 	repo contracts.ProductRepository,
 	outbox contracts.OutboxRepository,
-	cm *committer.Committer,
+	cm committer.Committer,
 	clk clock.Clock,
 ) *Interactor {
 	return &Interactor{
@@ -38,39 +40,45 @@ func NewInteractor(
 }
 
 func (it *Interactor) Execute(ctx context.Context, req Request) error {
-	product, err := it.repo.FindByID(ctx, req.ProductID)
-	if err != nil {
-		return err
-	}
-
-	name := product.Name()
-	if req.Name != nil {
-		name = *req.Name
-	}
-	desc := product.Description()
-	if req.Description != nil {
-		desc = *req.Description
-	}
-	cat := product.Category()
-	if req.Category != nil {
-		cat = *req.Category
-	}
+	return it.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		product, err := it.repo.FindByID(ctx, req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if err := product.CheckVersion(req.ExpectedVersion); err != nil {
+			return nil, err
+		}
 
-	now := it.clock.Now()
-	if err := product.UpdateDetails(name, desc, cat, now); err != nil {
-		return err
-	}
+		name := product.Name()
+		if req.Name != nil {
+			name = *req.Name
+		}
+		desc := product.Description()
+		if req.Description != nil {
+			desc = *req.Description
+		}
+		cat := product.Category()
+		if req.Category != nil {
+			cat = *req.Category
+		}
 
-	plan := committer.NewPlan()
-	plan.Add(it.repo.UpdateMut(product))
+		now := it.clock.Now()
+		if err := product.UpdateDetails(name, desc, cat, now); err != nil {
+			return nil, err
+		}
 
-	for _, event := range product.DomainEvents() {
-		plan.Add(it.outbox.InsertMut(usecases.EnrichEvent(product.ID(), event)))
-	}
+		plan := committer.NewPlan()
+		plan.Require(it.repo.VersionCheck(product))
+		plan.Add(it.repo.UpdateMut(product))
 
-	if err := it.committer.Apply(ctx, plan); err != nil {
-		return err
-	}
+		for _, event := range product.DomainEvents() {
+			outboxEvent, err := usecases.EnrichEvent(product.ID(), event, eventmeta.FromContext(ctx))
+			if err != nil {
+				return nil, err
+			}
+			plan.Add(it.outbox.InsertMut(outboxEvent))
+		}
 
-	return nil
+		return plan, nil
+	})
 }
@@ -0,0 +1,191 @@
+package verify_history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+type Request struct {
+	ProductID string
+}
+
+// Drift is one field where the products row and the replayed events
+// disagree. Values are formatted for display; "<none>" means unset.
+type Drift struct {
+	Field    string
+	Stored   string
+	Replayed string
+}
+
+// Report is the outcome of verifying one product. A product with no drift
+// and no replay error is consistent.
+type Report struct {
+	ProductID string
+	Drift     []Drift
+	// ReplayError says why the events couldn't be replayed at all, e.g. a
+	// gap left by outbox retention or a row written before CloudEvents.
+	ReplayError string
+}
+
+func (r *Report) OK() bool { return len(r.Drift) == 0 && r.ReplayError == "" }
+
+// Interactor compares a product's row with the product its outbox events
+// replay to. Drift means something wrote the row without going through the
+// aggregate, or the aggregate changed state without recording it.
+type Interactor struct {
+	repo      contracts.ProductRepository
+	events    contracts.ProductEventStore
+	committer committer.Committer
+}
+
+func NewInteractor(
+	repo contracts.ProductRepository,
+	events contracts.ProductEventStore,
+	cm committer.Committer,
+) *Interactor {
+	return &Interactor{
+		repo:      repo,
+		events:    events,
+		committer: cm,
+	}
+}
+
+// Execute reads the row and the events in one transaction, so a write
+// landing in between can't show up as drift. It commits nothing. A row that
+// breaks the aggregate's invariants is reported as drift on the fields it
+// breaks, rather than failing, so a scan carries on past it.
+func (it *Interactor) Execute(ctx context.Context, req Request) (*Report, error) {
+	report := &Report{ProductID: req.ProductID}
+	err := it.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		report.Drift, report.ReplayError = nil, ""
+
+		stored, err := it.repo.FindByID(ctx, req.ProductID)
+		var corrupt *domain.CorruptProductError
+		switch {
+		case errors.As(err, &corrupt):
+		case err != nil && !errors.Is(err, domain.ErrProductNotFound):
+			return nil, err
+		}
+		replayed, err := it.events.LoadFromEvents(ctx, req.ProductID)
+		switch {
+		case errors.Is(err, contracts.ErrHistoryUnreplayable):
+			report.ReplayError = err.Error()
+			return nil, nil
+		case err != nil && !errors.Is(err, domain.ErrProductNotFound):
+			return nil, err
+		}
+
+		if corrupt != nil {
+			report.Drift = CompareCorrupt(corrupt, replayed)
+			return nil, nil
+		}
+		if stored == nil && replayed == nil {
+			return nil, domain.ErrProductNotFound
+		}
+		report.Drift = Compare(stored, replayed)
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Compare lists the fields where stored and replayed differ. Either may be
+// nil, meaning the row or the events are missing altogether. Timestamps are
+// compared at microsecond precision, which is all Spanner keeps.
+func Compare(stored, replayed *domain.Product) []Drift {
+	if stored == nil || replayed == nil {
+		return []Drift{{Field: "product", Stored: presence(stored), Replayed: presence(replayed)}}
+	}
+
+	var drift []Drift
+	replayedValues := fields(replayed)
+	for i, s := range fields(stored) {
+		if r := replayedValues[i]; s.value != r.value {
+			drift = append(drift, Drift{Field: s.name, Stored: s.value, Replayed: r.value})
+		}
+	}
+	return drift
+}
+
+// CompareCorrupt lists each invariant the stored row breaks as drift on its
+// field, with the row's error in place of its value. replayed may be nil
+// when the product has no events.
+func CompareCorrupt(corrupt *domain.CorruptProductError, replayed *domain.Product) []Drift {
+	replayedValues := map[string]string{}
+	if replayed != nil {
+		for _, f := range fields(replayed) {
+			replayedValues[f.name] = f.value
+		}
+	}
+	drift := make([]Drift, len(corrupt.Violations))
+	for i, v := range corrupt.Violations {
+		r, ok := replayedValues[v.Field]
+		if !ok {
+			r = presence(replayed)
+		}
+		drift[i] = Drift{Field: v.Field, Stored: "corrupt: " + v.Err.Error(), Replayed: r}
+	}
+	return drift
+}
+
+type field struct{ name, value string }
+
+// fields formats every compared field of p, in a fixed order.
+func fields(p *domain.Product) []field {
+	return []field{
+		{domain.FieldName, p.Name()},
+		{domain.FieldDescription, p.Description()},
+		{domain.FieldCategory, p.Category()},
+		{domain.FieldBasePrice, formatMoney(p.BasePrice())},
+		{domain.FieldDiscount, formatDiscount(p.Discount())},
+		{domain.FieldStatus, string(p.Status())},
+		{"created_at", formatTime(p.CreatedAt())},
+		{"updated_at", formatTime(p.UpdatedAt())},
+		{"archived_at", formatTimePtr(p.ArchivedAt())},
+		{"version", fmt.Sprint(p.Version())},
+	}
+}
+
+const none = "<none>"
+
+func presence(p *domain.Product) string {
+	if p == nil {
+		return "missing"
+	}
+	return "present"
+}
+
+// formatMoney shows the exact rational, so prices that round alike still
+// count as drift.
+func formatMoney(m *domain.Money) string {
+	if m == nil {
+		return none
+	}
+	return m.Amount().RatString()
+}
+
+func formatDiscount(d *domain.Discount) string {
+	if d == nil {
+		return none
+	}
+	return fmt.Sprintf("%s%% [%s, %s)", d.Percentage().RatString(), formatTime(d.StartDate()), formatTime(d.EndDate()))
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano)
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return none
+	}
+	return formatTime(*t)
+}
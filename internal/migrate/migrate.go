@@ -0,0 +1,273 @@
+// Package migrate applies schema migrations and tracks them in a
+// schema_migrations table, one row per applied file with its checksum.
+// A Target adapts one database; the Runner decides what to apply and
+// refuses to go on when an applied file has changed or disappeared, since
+// the database no longer matches the files it would be checked against.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Table is where targets record applied migrations.
+const Table = "schema_migrations"
+
+// ErrPending is returned by Verify when migrations remain to be applied.
+var ErrPending = errors.New("migrate: schema has pending migrations")
+
+// Migration is one file of a migrations directory.
+type Migration struct {
+	Version int64
+	// Name is the file name, e.g. "003_price_history.sql".
+	Name string
+	SQL  string
+	// Checksum is the hex SHA-256 of the file's bytes.
+	Checksum string
+}
+
+// Statements splits the file on semicolons. Migrations must not contain
+// semicolons inside literals or comments.
+func (m Migration) Statements() []string {
+	var out []string
+	for _, part := range strings.Split(m.SQL, ";") {
+		if s := strings.TrimSpace(part); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Load reads the *.sql files at the root of fsys, sorted by version. Each
+// name must start with its version number followed by an underscore.
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: list migrations: %w", err)
+	}
+
+	seen := map[int64]string{}
+	migrations := make([]Migration, 0, len(names))
+	for _, name := range names {
+		prefix, _, ok := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migrate: %s: name must be NNN_description.sql", name)
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migrate: %s and %s share version %d", other, name, version)
+		}
+		seen[version] = name
+
+		raw, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: read %s: %w", name, err)
+		}
+		sum := sha256.Sum256(raw)
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     name,
+			SQL:      string(raw),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Record is a schema_migrations row.
+type Record struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Target is the database migrations are applied to.
+type Target interface {
+	// Init creates the schema_migrations table if it doesn't exist.
+	Init(ctx context.Context) error
+	// Applied returns the recorded migrations. A missing schema_migrations
+	// table means none, not an error, so read-only commands don't need Init.
+	Applied(ctx context.Context) ([]Record, error)
+	// Apply runs the migration's statements and records it. Targets with
+	// transactional DDL do both atomically.
+	Apply(ctx context.Context, m Migration, at time.Time) error
+	// Mark records the migration without running it.
+	Mark(ctx context.Context, m Migration, at time.Time) error
+}
+
+// State is where a migration stands against the database.
+type State string
+
+const (
+	StateApplied State = "applied"
+	StatePending State = "pending"
+	// StateChanged means the file differs from the one that was applied.
+	StateChanged State = "changed"
+	// StateMissing means an applied migration has no file any more.
+	StateMissing State = "missing"
+)
+
+// Status is one line of Runner.Status.
+type Status struct {
+	Version int64
+	Name    string
+	State   State
+	// AppliedAt is zero for pending migrations.
+	AppliedAt time.Time
+}
+
+// DriftError lists applied migrations whose files changed or disappeared.
+type DriftError struct {
+	Drifted []Status
+}
+
+func (e *DriftError) Error() string {
+	parts := make([]string, len(e.Drifted))
+	for i, s := range e.Drifted {
+		parts[i] = fmt.Sprintf("%s (%s)", s.Name, s.State)
+	}
+	return "migrate: applied migrations no longer match their files: " + strings.Join(parts, ", ")
+}
+
+type Runner struct {
+	target     Target
+	migrations []Migration
+	now        func() time.Time
+}
+
+// NewRunner applies migrations, as returned by Load, to target.
+func NewRunner(target Target, migrations []Migration) *Runner {
+	return &Runner{target: target, migrations: migrations, now: time.Now}
+}
+
+// Status reports every migration file and every recorded migration, in
+// version order.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	applied, err := r.target.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[int64]Record, len(applied))
+	for _, rec := range applied {
+		records[rec.Version] = rec
+	}
+
+	out := make([]Status, 0, len(r.migrations)+len(applied))
+	for _, m := range r.migrations {
+		s := Status{Version: m.Version, Name: m.Name, State: StatePending}
+		if rec, ok := records[m.Version]; ok {
+			delete(records, m.Version)
+			s.AppliedAt = rec.AppliedAt
+			s.State = StateApplied
+			if rec.Checksum != m.Checksum {
+				s.State = StateChanged
+			}
+		}
+		out = append(out, s)
+	}
+	for _, rec := range records {
+		out = append(out, Status{Version: rec.Version, Name: rec.Name, State: StateMissing, AppliedAt: rec.AppliedAt})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// Pending returns the migrations still to apply, or a *DriftError.
+func (r *Runner) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := r.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		drifted []Status
+		pending = map[int64]bool{}
+	)
+	for _, s := range statuses {
+		switch s.State {
+		case StateChanged, StateMissing:
+			drifted = append(drifted, s)
+		case StatePending:
+			pending[s.Version] = true
+		}
+	}
+	if len(drifted) > 0 {
+		return nil, &DriftError{Drifted: drifted}
+	}
+
+	var out []Migration
+	for _, m := range r.migrations {
+		if pending[m.Version] {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+// Up applies the pending migrations in order and returns those it applied.
+// With dryRun it only returns what it would apply, and writes nothing. It
+// stops at the first failure; the migrations before it stay applied.
+func (r *Runner) Up(ctx context.Context, dryRun bool) ([]Migration, error) {
+	pending, err := r.Pending(ctx)
+	if err != nil || dryRun {
+		return pending, err
+	}
+	if err := r.target.Init(ctx); err != nil {
+		return nil, fmt.Errorf("migrate: create %s: %w", Table, err)
+	}
+
+	for i, m := range pending {
+		if err := r.target.Apply(ctx, m, r.now().UTC()); err != nil {
+			return pending[:i], fmt.Errorf("migrate: apply %s: %w", m.Name, err)
+		}
+	}
+	return pending, nil
+}
+
+// Baseline records the pending migrations up to and including version as
+// applied, without running them. It adopts databases whose schema was
+// created before migrations were tracked.
+func (r *Runner) Baseline(ctx context.Context, version int64) ([]Migration, error) {
+	pending, err := r.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.target.Init(ctx); err != nil {
+		return nil, fmt.Errorf("migrate: create %s: %w", Table, err)
+	}
+
+	var marked []Migration
+	for _, m := range pending {
+		if m.Version > version {
+			break
+		}
+		if err := r.target.Mark(ctx, m, r.now().UTC()); err != nil {
+			return marked, fmt.Errorf("migrate: mark %s: %w", m.Name, err)
+		}
+		marked = append(marked, m)
+	}
+	return marked, nil
+}
+
+// Verify returns nil when every migration is applied unchanged, a
+// *DriftError when applied files changed, and ErrPending when some are
+// still to apply. It only reads.
+func (r *Runner) Verify(ctx context.Context) error {
+	pending, err := r.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d, starting with %s", ErrPending, len(pending), pending[0].Name)
+	}
+	return nil
+}
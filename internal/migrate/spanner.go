@@ -0,0 +1,139 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	database "cloud.google.com/go/spanner/admin/database/apiv1"
+	databasepb "cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
+)
+
+var _ Target = (*SpannerTarget)(nil)
+
+// SpannerTarget migrates a Cloud Spanner database through the database
+// admin API. Spanner can't run DDL in a transaction, so a migration's row
+// is written once its DDL has finished: if that write fails, the schema
+// has changed but the migration reads as pending, and has to be marked by
+// hand with Baseline.
+type SpannerTarget struct {
+	client *spanner.Client
+	admin  *database.DatabaseAdminClient
+}
+
+// NewSpannerTarget migrates the client's database. admin may be nil when
+// the target is only read, by Status or Verify.
+func NewSpannerTarget(client *spanner.Client, admin *database.DatabaseAdminClient) *SpannerTarget {
+	return &SpannerTarget{client: client, admin: admin}
+}
+
+func (t *SpannerTarget) Init(ctx context.Context) error {
+	exists, err := t.exists(ctx)
+	if err != nil || exists {
+		return err
+	}
+	return t.updateDDL(ctx, []string{`CREATE TABLE ` + Table + ` (
+		version INT64 NOT NULL,
+		name STRING(MAX) NOT NULL,
+		checksum STRING(64) NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	) PRIMARY KEY (version)`})
+}
+
+func (t *SpannerTarget) Applied(ctx context.Context) ([]Record, error) {
+	exists, err := t.exists(ctx)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	var out []Record
+	stmt := spanner.Statement{SQL: `SELECT version, name, checksum, applied_at FROM ` + Table + ` ORDER BY version`}
+	err = t.client.Single().Query(ctx, stmt).Do(func(row *spanner.Row) error {
+		var rec Record
+		if err := row.Columns(&rec.Version, &rec.Name, &rec.Checksum, &rec.AppliedAt); err != nil {
+			return err
+		}
+		out = append(out, rec)
+		return nil
+	})
+	return out, err
+}
+
+// Apply sends each run of consecutive DDL statements as one batch, and each
+// DML statement, such as a backfill, as partitioned DML. Neither is atomic
+// with the rest of the migration, so DML must be safe to run again.
+func (t *SpannerTarget) Apply(ctx context.Context, m Migration, at time.Time) error {
+	var ddl []string
+	flush := func() error {
+		if len(ddl) == 0 {
+			return nil
+		}
+		err := t.updateDDL(ctx, ddl)
+		ddl = nil
+		return err
+	}
+	for _, stmt := range m.Statements() {
+		if !isDML(stmt) {
+			ddl = append(ddl, stmt)
+			continue
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+		if _, err := t.client.PartitionedUpdate(ctx, spanner.Statement{SQL: stmt}); err != nil {
+			return fmt.Errorf("%w\n%s", err, stmt)
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	return t.Mark(ctx, m, at)
+}
+
+func (t *SpannerTarget) Mark(ctx context.Context, m Migration, at time.Time) error {
+	_, err := t.client.Apply(ctx, []*spanner.Mutation{
+		spanner.Insert(Table,
+			[]string{"version", "name", "checksum", "applied_at"},
+			[]interface{}{m.Version, m.Name, m.Checksum, at}),
+	})
+	return err
+}
+
+func (t *SpannerTarget) exists(ctx context.Context) (bool, error) {
+	stmt := spanner.Statement{
+		SQL: `SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES
+			WHERE TABLE_CATALOG = '' AND TABLE_SCHEMA = '' AND TABLE_NAME = @name`,
+		Params: map[string]interface{}{"name": Table},
+	}
+	var n int64
+	err := t.client.Single().Query(ctx, stmt).Do(func(row *spanner.Row) error {
+		return row.Columns(&n)
+	})
+	return n > 0, err
+}
+
+func isDML(stmt string) bool {
+	verb, _, _ := strings.Cut(strings.TrimSpace(stmt), " ")
+	switch strings.ToUpper(verb) {
+	case "INSERT", "UPDATE", "DELETE":
+		return true
+	}
+	return false
+}
+
+func (t *SpannerTarget) updateDDL(ctx context.Context, statements []string) error {
+	if t.admin == nil {
+		return errors.New("migrate: spanner target has no admin client")
+	}
+	op, err := t.admin.UpdateDatabaseDdl(ctx, &databasepb.UpdateDatabaseDdlRequest{
+		Database:   t.client.DatabaseName(),
+		Statements: statements,
+	})
+	if err != nil {
+		return err
+	}
+	return op.Wait(ctx)
+}
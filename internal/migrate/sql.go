@@ -0,0 +1,159 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	postgresdriver "github.com/tshubham2/commitplan/drivers/postgres"
+	"github.com/tshubham2/commitplan/drivers/sqldb"
+	sqlitedriver "github.com/tshubham2/commitplan/drivers/sqlite"
+)
+
+var _ Target = (*SQLTarget)(nil)
+
+// SQLTarget migrates a database/sql database with transactional DDL: each
+// migration's statements and its schema_migrations row commit together, so
+// a failed migration leaves nothing behind.
+type SQLTarget struct {
+	db      *sql.DB
+	dialect sqldb.Dialect
+	// createTable creates schema_migrations if it's missing; tableExists
+	// selects one row when it's there.
+	createTable string
+	tableExists string
+}
+
+// NewPostgresTarget migrates a PostgreSQL database.
+func NewPostgresTarget(db *sql.DB) *SQLTarget {
+	return &SQLTarget{
+		db:      db,
+		dialect: postgresdriver.Dialect,
+		createTable: `CREATE TABLE IF NOT EXISTS ` + Table + ` (
+			version BIGINT NOT NULL PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL
+		)`,
+		tableExists: `SELECT 1 FROM information_schema.tables
+			WHERE table_schema = current_schema() AND table_name = '` + Table + `'`,
+	}
+}
+
+// NewSQLiteTarget migrates a SQLite database opened with sqlitedriver.DSN.
+func NewSQLiteTarget(db *sql.DB) *SQLTarget {
+	return &SQLTarget{
+		db:      db,
+		dialect: sqlitedriver.Dialect,
+		createTable: `CREATE TABLE IF NOT EXISTS ` + Table + ` (
+			version INTEGER NOT NULL PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TEXT NOT NULL
+		)`,
+		tableExists: `SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = '` + Table + `'`,
+	}
+}
+
+func (t *SQLTarget) Init(ctx context.Context) error {
+	_, err := t.db.ExecContext(ctx, t.createTable)
+	return err
+}
+
+func (t *SQLTarget) Applied(ctx context.Context) ([]Record, error) {
+	var one int
+	err := t.db.QueryRowContext(ctx, t.tableExists).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.db.QueryContext(ctx,
+		`SELECT version, name, checksum, applied_at FROM `+Table+` ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Record
+	for rows.Next() {
+		var (
+			rec Record
+			at  appliedAt
+		)
+		if err := rows.Scan(&rec.Version, &rec.Name, &rec.Checksum, &at); err != nil {
+			return nil, err
+		}
+		rec.AppliedAt = at.Time
+		out = append(out, rec)
+	}
+	return out, rows.Err()
+}
+
+func (t *SQLTarget) Apply(ctx context.Context, m Migration, at time.Time) error {
+	return t.inTx(ctx, func(tx *sql.Tx) error {
+		for _, stmt := range m.Statements() {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("%w\n%s", err, stmt)
+			}
+		}
+		return t.record(ctx, tx, m, at)
+	})
+}
+
+func (t *SQLTarget) Mark(ctx context.Context, m Migration, at time.Time) error {
+	return t.inTx(ctx, func(tx *sql.Tx) error {
+		return t.record(ctx, tx, m, at)
+	})
+}
+
+func (t *SQLTarget) inTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // no-op after Commit
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (t *SQLTarget) record(ctx context.Context, tx *sql.Tx, m Migration, at time.Time) error {
+	p := t.dialect.Placeholder
+	var when interface{} = at.UTC()
+	if t.dialect.Time != nil {
+		when = t.dialect.Time(at)
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO `+Table+` (version, name, checksum, applied_at) VALUES (`+
+			p(1)+`, `+p(2)+`, `+p(3)+`, `+p(4)+`)`,
+		m.Version, m.Name, m.Checksum, when)
+	return err
+}
+
+// appliedAt scans applied_at, which comes back as a time.Time from
+// PostgreSQL and as RFC 3339 text from SQLite.
+type appliedAt struct {
+	Time time.Time
+}
+
+func (a *appliedAt) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case time.Time:
+		a.Time = v.UTC()
+		return nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return err
+		}
+		a.Time = t
+		return nil
+	}
+	return fmt.Errorf("migrate: cannot scan %T into applied_at", src)
+}
@@ -0,0 +1,179 @@
+// Code generated by modelgen from the Spanner migrations. DO NOT EDIT.
+
+package m_outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"github.com/tshubham2/commitplan"
+)
+
+const Table = "outbox_events"
+
+const (
+	EventID        = "event_id"
+	EventType      = "event_type"
+	AggregateID    = "aggregate_id"
+	Payload        = "payload"
+	Status         = "status"
+	CreatedAt      = "created_at"
+	ProcessedAt    = "processed_at"
+	Attempts       = "attempts"
+	LastError      = "last_error"
+	NextAttemptAt  = "next_attempt_at"
+	ClaimedBy      = "claimed_by"
+	LeaseExpiresAt = "lease_expires_at"
+	Sequence       = "sequence"
+	Metadata       = "metadata"
+)
+
+// KeyColumns is the primary key, in key order.
+var KeyColumns = []string{EventID}
+
+// AllColumns is every column in schema order, the order FromRow scans.
+var AllColumns = []string{
+	EventID,
+	EventType,
+	AggregateID,
+	Payload,
+	Status,
+	CreatedAt,
+	ProcessedAt,
+	Attempts,
+	LastError,
+	NextAttemptAt,
+	ClaimedBy,
+	LeaseExpiresAt,
+	Sequence,
+	Metadata,
+}
+
+// SelectList is AllColumns for a query, with the JSON columns
+// converted to strings so FromRow can scan them without reinterpretation.
+func SelectList() string {
+	return "event_id, event_type, aggregate_id, TO_JSON_STRING(payload) AS payload, status, created_at, processed_at, attempts, last_error, next_attempt_at, claimed_by, lease_expires_at, sequence, TO_JSON_STRING(metadata) AS metadata"
+}
+
+// ScanDest orders scan destinations, keyed by column, as AllColumns, for
+// database/sql rows selected with AllColumns. It fails if a column has no
+// destination, rather than letting the others scan into the wrong fields.
+func ScanDest(byColumn map[string]interface{}) ([]interface{}, error) {
+	if len(byColumn) != len(AllColumns) {
+		return nil, fmt.Errorf("%s: %d scan destinations for %d columns", Table, len(byColumn), len(AllColumns))
+	}
+	dest := make([]interface{}, len(AllColumns))
+	for i, c := range AllColumns {
+		d, ok := byColumn[c]
+		if !ok {
+			return nil, fmt.Errorf("%s: no scan destination for %s", Table, c)
+		}
+		dest[i] = d
+	}
+	return dest, nil
+}
+
+type Data struct {
+	EventID        string
+	EventType      string
+	AggregateID    string
+	Payload        json.RawMessage
+	Status         string
+	CreatedAt      time.Time
+	ProcessedAt    spanner.NullTime
+	Attempts       int64
+	LastError      spanner.NullString
+	NextAttemptAt  spanner.NullTime
+	ClaimedBy      spanner.NullString
+	LeaseExpiresAt spanner.NullTime
+	Sequence       int64
+	Metadata       json.RawMessage // nil when NULL
+}
+
+type Model struct{}
+
+func New() *Model { return &Model{} }
+
+func (m *Model) InsertMap(values map[string]interface{}) *commitplan.Mutation {
+	return commitplan.InsertMap(Table, KeyColumns, values)
+}
+
+func (m *Model) UpsertMap(values map[string]interface{}) *commitplan.Mutation {
+	return commitplan.UpsertMap(Table, KeyColumns, values)
+}
+
+func (m *Model) UpdateMap(id string, values map[string]interface{}) *commitplan.Mutation {
+	values[EventID] = id
+	return commitplan.UpdateMap(Table, KeyColumns, values)
+}
+
+func (m *Model) Delete(id string) *commitplan.Mutation {
+	return commitplan.Delete(Table, commitplan.KeyOf(EventID, id))
+}
+
+// ToRow leaves NULL columns out.
+func (m *Model) ToRow(d *Data) map[string]interface{} {
+	row := map[string]interface{}{
+		EventID:     d.EventID,
+		EventType:   d.EventType,
+		AggregateID: d.AggregateID,
+		Payload:     string(d.Payload),
+		Status:      d.Status,
+		CreatedAt:   d.CreatedAt,
+		Attempts:    d.Attempts,
+		Sequence:    d.Sequence,
+	}
+	if d.ProcessedAt.Valid {
+		row[ProcessedAt] = d.ProcessedAt.Time
+	}
+	if d.LastError.Valid {
+		row[LastError] = d.LastError.StringVal
+	}
+	if d.NextAttemptAt.Valid {
+		row[NextAttemptAt] = d.NextAttemptAt.Time
+	}
+	if d.ClaimedBy.Valid {
+		row[ClaimedBy] = d.ClaimedBy.StringVal
+	}
+	if d.LeaseExpiresAt.Valid {
+		row[LeaseExpiresAt] = d.LeaseExpiresAt.Time
+	}
+	if len(d.Metadata) > 0 {
+		row[Metadata] = string(d.Metadata)
+	}
+	return row
+}
+
+// FromRow scans a row read with AllColumns; queries must select
+// SelectList(), since JSON columns are scanned as strings.
+func (m *Model) FromRow(row *spanner.Row) (*Data, error) {
+	d := &Data{}
+	var payload string
+	var metadata spanner.NullString
+	err := row.Columns(
+		&d.EventID,
+		&d.EventType,
+		&d.AggregateID,
+		&payload,
+		&d.Status,
+		&d.CreatedAt,
+		&d.ProcessedAt,
+		&d.Attempts,
+		&d.LastError,
+		&d.NextAttemptAt,
+		&d.ClaimedBy,
+		&d.LeaseExpiresAt,
+		&d.Sequence,
+		&metadata,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = json.RawMessage(payload)
+	if metadata.Valid {
+		d.Metadata = json.RawMessage(metadata.StringVal)
+	}
+	return d, nil
+}
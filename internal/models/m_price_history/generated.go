@@ -0,0 +1,105 @@
+// Code generated by modelgen from the Spanner migrations. DO NOT EDIT.
+
+package m_price_history
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"github.com/tshubham2/commitplan"
+)
+
+const Table = "product_price_history"
+
+const (
+	ProductID = "product_id"
+	ChangeID  = "change_id"
+	ChangedAt = "changed_at"
+	OldPrice  = "old_price"
+	NewPrice  = "new_price"
+)
+
+// KeyColumns is the primary key, in key order.
+var KeyColumns = []string{ProductID, ChangeID}
+
+// AllColumns is every column in schema order, the order FromRow scans.
+var AllColumns = []string{
+	ProductID,
+	ChangeID,
+	ChangedAt,
+	OldPrice,
+	NewPrice,
+}
+
+// SelectList is AllColumns for a query.
+func SelectList() string {
+	return "product_id, change_id, changed_at, old_price, new_price"
+}
+
+// ScanDest orders scan destinations, keyed by column, as AllColumns, for
+// database/sql rows selected with AllColumns. It fails if a column has no
+// destination, rather than letting the others scan into the wrong fields.
+func ScanDest(byColumn map[string]interface{}) ([]interface{}, error) {
+	if len(byColumn) != len(AllColumns) {
+		return nil, fmt.Errorf("%s: %d scan destinations for %d columns", Table, len(byColumn), len(AllColumns))
+	}
+	dest := make([]interface{}, len(AllColumns))
+	for i, c := range AllColumns {
+		d, ok := byColumn[c]
+		if !ok {
+			return nil, fmt.Errorf("%s: no scan destination for %s", Table, c)
+		}
+		dest[i] = d
+	}
+	return dest, nil
+}
+
+type Data struct {
+	ProductID string
+	ChangeID  string
+	ChangedAt time.Time
+	OldPrice  big.Rat
+	NewPrice  big.Rat
+}
+
+type Model struct{}
+
+func New() *Model { return &Model{} }
+
+func (m *Model) InsertMap(values map[string]interface{}) *commitplan.Mutation {
+	return commitplan.InsertMap(Table, KeyColumns, values)
+}
+
+func (m *Model) UpsertMap(values map[string]interface{}) *commitplan.Mutation {
+	return commitplan.UpsertMap(Table, KeyColumns, values)
+}
+
+// ToRow leaves NULL columns out.
+func (m *Model) ToRow(d *Data) map[string]interface{} {
+	row := map[string]interface{}{
+		ProductID: d.ProductID,
+		ChangeID:  d.ChangeID,
+		ChangedAt: d.ChangedAt,
+		OldPrice:  d.OldPrice,
+		NewPrice:  d.NewPrice,
+	}
+	return row
+}
+
+// FromRow scans a row read with AllColumns.
+func (m *Model) FromRow(row *spanner.Row) (*Data, error) {
+	d := &Data{}
+	err := row.Columns(
+		&d.ProductID,
+		&d.ChangeID,
+		&d.ChangedAt,
+		&d.OldPrice,
+		&d.NewPrice,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
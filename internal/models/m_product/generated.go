@@ -0,0 +1,164 @@
+// Code generated by modelgen from the Spanner migrations. DO NOT EDIT.
+
+package m_product
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"github.com/tshubham2/commitplan"
+)
+
+const Table = "products"
+
+const (
+	ProductID         = "product_id"
+	Name              = "name"
+	Description       = "description"
+	Category          = "category"
+	DiscountPercent   = "discount_percent"
+	DiscountStartDate = "discount_start_date"
+	DiscountEndDate   = "discount_end_date"
+	Status            = "status"
+	CreatedAt         = "created_at"
+	UpdatedAt         = "updated_at"
+	ArchivedAt        = "archived_at"
+	Version           = "version"
+	BasePrice         = "base_price"
+)
+
+// KeyColumns is the primary key, in key order.
+var KeyColumns = []string{ProductID}
+
+// AllColumns is every column in schema order, the order FromRow scans.
+var AllColumns = []string{
+	ProductID,
+	Name,
+	Description,
+	Category,
+	DiscountPercent,
+	DiscountStartDate,
+	DiscountEndDate,
+	Status,
+	CreatedAt,
+	UpdatedAt,
+	ArchivedAt,
+	Version,
+	BasePrice,
+}
+
+// SelectList is AllColumns for a query.
+func SelectList() string {
+	return "product_id, name, description, category, discount_percent, discount_start_date, discount_end_date, status, created_at, updated_at, archived_at, version, base_price"
+}
+
+// ScanDest orders scan destinations, keyed by column, as AllColumns, for
+// database/sql rows selected with AllColumns. It fails if a column has no
+// destination, rather than letting the others scan into the wrong fields.
+func ScanDest(byColumn map[string]interface{}) ([]interface{}, error) {
+	if len(byColumn) != len(AllColumns) {
+		return nil, fmt.Errorf("%s: %d scan destinations for %d columns", Table, len(byColumn), len(AllColumns))
+	}
+	dest := make([]interface{}, len(AllColumns))
+	for i, c := range AllColumns {
+		d, ok := byColumn[c]
+		if !ok {
+			return nil, fmt.Errorf("%s: no scan destination for %s", Table, c)
+		}
+		dest[i] = d
+	}
+	return dest, nil
+}
+
+type Data struct {
+	ProductID         string
+	Name              string
+	Description       spanner.NullString
+	Category          string
+	DiscountPercent   spanner.NullNumeric
+	DiscountStartDate spanner.NullTime
+	DiscountEndDate   spanner.NullTime
+	Status            string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	ArchivedAt        spanner.NullTime
+	Version           int64
+	BasePrice         big.Rat
+}
+
+type Model struct{}
+
+func New() *Model { return &Model{} }
+
+func (m *Model) InsertMap(values map[string]interface{}) *commitplan.Mutation {
+	return commitplan.InsertMap(Table, KeyColumns, values)
+}
+
+func (m *Model) UpsertMap(values map[string]interface{}) *commitplan.Mutation {
+	return commitplan.UpsertMap(Table, KeyColumns, values)
+}
+
+func (m *Model) UpdateMap(id string, values map[string]interface{}) *commitplan.Mutation {
+	values[ProductID] = id
+	return commitplan.UpdateMap(Table, KeyColumns, values)
+}
+
+func (m *Model) Delete(id string) *commitplan.Mutation {
+	return commitplan.Delete(Table, commitplan.KeyOf(ProductID, id))
+}
+
+// ToRow leaves NULL columns out.
+func (m *Model) ToRow(d *Data) map[string]interface{} {
+	row := map[string]interface{}{
+		ProductID: d.ProductID,
+		Name:      d.Name,
+		Category:  d.Category,
+		Status:    d.Status,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+		Version:   d.Version,
+		BasePrice: d.BasePrice,
+	}
+	if d.Description.Valid {
+		row[Description] = d.Description.StringVal
+	}
+	if d.DiscountPercent.Valid {
+		row[DiscountPercent] = d.DiscountPercent.Numeric
+	}
+	if d.DiscountStartDate.Valid {
+		row[DiscountStartDate] = d.DiscountStartDate.Time
+	}
+	if d.DiscountEndDate.Valid {
+		row[DiscountEndDate] = d.DiscountEndDate.Time
+	}
+	if d.ArchivedAt.Valid {
+		row[ArchivedAt] = d.ArchivedAt.Time
+	}
+	return row
+}
+
+// FromRow scans a row read with AllColumns.
+func (m *Model) FromRow(row *spanner.Row) (*Data, error) {
+	d := &Data{}
+	err := row.Columns(
+		&d.ProductID,
+		&d.Name,
+		&d.Description,
+		&d.Category,
+		&d.DiscountPercent,
+		&d.DiscountStartDate,
+		&d.DiscountEndDate,
+		&d.Status,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+		&d.ArchivedAt,
+		&d.Version,
+		&d.BasePrice,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,103 @@
+// Code generated by modelgen from the Spanner migrations. DO NOT EDIT.
+
+package m_product_quarantine
+
+import (
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"github.com/tshubham2/commitplan"
+)
+
+const Table = "product_quarantine"
+
+const (
+	ProductID  = "product_id"
+	Violations = "violations"
+	FoundAt    = "found_at"
+)
+
+// KeyColumns is the primary key, in key order.
+var KeyColumns = []string{ProductID}
+
+// AllColumns is every column in schema order, the order FromRow scans.
+var AllColumns = []string{
+	ProductID,
+	Violations,
+	FoundAt,
+}
+
+// SelectList is AllColumns for a query.
+func SelectList() string {
+	return "product_id, violations, found_at"
+}
+
+// ScanDest orders scan destinations, keyed by column, as AllColumns, for
+// database/sql rows selected with AllColumns. It fails if a column has no
+// destination, rather than letting the others scan into the wrong fields.
+func ScanDest(byColumn map[string]interface{}) ([]interface{}, error) {
+	if len(byColumn) != len(AllColumns) {
+		return nil, fmt.Errorf("%s: %d scan destinations for %d columns", Table, len(byColumn), len(AllColumns))
+	}
+	dest := make([]interface{}, len(AllColumns))
+	for i, c := range AllColumns {
+		d, ok := byColumn[c]
+		if !ok {
+			return nil, fmt.Errorf("%s: no scan destination for %s", Table, c)
+		}
+		dest[i] = d
+	}
+	return dest, nil
+}
+
+type Data struct {
+	ProductID  string
+	Violations string
+	FoundAt    time.Time
+}
+
+type Model struct{}
+
+func New() *Model { return &Model{} }
+
+func (m *Model) InsertMap(values map[string]interface{}) *commitplan.Mutation {
+	return commitplan.InsertMap(Table, KeyColumns, values)
+}
+
+func (m *Model) UpsertMap(values map[string]interface{}) *commitplan.Mutation {
+	return commitplan.UpsertMap(Table, KeyColumns, values)
+}
+
+func (m *Model) UpdateMap(id string, values map[string]interface{}) *commitplan.Mutation {
+	values[ProductID] = id
+	return commitplan.UpdateMap(Table, KeyColumns, values)
+}
+
+func (m *Model) Delete(id string) *commitplan.Mutation {
+	return commitplan.Delete(Table, commitplan.KeyOf(ProductID, id))
+}
+
+// ToRow leaves NULL columns out.
+func (m *Model) ToRow(d *Data) map[string]interface{} {
+	row := map[string]interface{}{
+		ProductID:  d.ProductID,
+		Violations: d.Violations,
+		FoundAt:    d.FoundAt,
+	}
+	return row
+}
+
+// FromRow scans a row read with AllColumns.
+func (m *Model) FromRow(row *spanner.Row) (*Data, error) {
+	d := &Data{}
+	err := row.Columns(
+		&d.ProductID,
+		&d.Violations,
+		&d.FoundAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
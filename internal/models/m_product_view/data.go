@@ -0,0 +1,20 @@
+package m_product_view
+
+import "math/big"
+
+// DiscountPercentRat returns the discount as *big.Rat, or nil if NULL.
+func (d *Data) DiscountPercentRat() *big.Rat {
+	if !d.DiscountPercent.Valid {
+		return nil
+	}
+	return &d.DiscountPercent.Numeric
+}
+
+// DiscountedPriceRat returns the discounted price as *big.Rat, or nil if
+// NULL.
+func (d *Data) DiscountedPriceRat() *big.Rat {
+	if !d.DiscountedPrice.Valid {
+		return nil
+	}
+	return &d.DiscountedPrice.Numeric
+}
@@ -0,0 +1,181 @@
+// Code generated by modelgen from the Spanner migrations. DO NOT EDIT.
+
+package m_product_view
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"github.com/tshubham2/commitplan"
+)
+
+const Table = "product_views"
+
+const (
+	ProductID         = "product_id"
+	Name              = "name"
+	Description       = "description"
+	Category          = "category"
+	BasePrice         = "base_price"
+	DiscountPercent   = "discount_percent"
+	DiscountStartDate = "discount_start_date"
+	DiscountEndDate   = "discount_end_date"
+	DiscountedPrice   = "discounted_price"
+	Status            = "status"
+	SearchText        = "search_text"
+	CreatedAt         = "created_at"
+	UpdatedAt         = "updated_at"
+	ArchivedAt        = "archived_at"
+	Version           = "version"
+	ProjectedAt       = "projected_at"
+)
+
+// KeyColumns is the primary key, in key order.
+var KeyColumns = []string{ProductID}
+
+// AllColumns is every column in schema order, the order FromRow scans.
+var AllColumns = []string{
+	ProductID,
+	Name,
+	Description,
+	Category,
+	BasePrice,
+	DiscountPercent,
+	DiscountStartDate,
+	DiscountEndDate,
+	DiscountedPrice,
+	Status,
+	SearchText,
+	CreatedAt,
+	UpdatedAt,
+	ArchivedAt,
+	Version,
+	ProjectedAt,
+}
+
+// SelectList is AllColumns for a query.
+func SelectList() string {
+	return "product_id, name, description, category, base_price, discount_percent, discount_start_date, discount_end_date, discounted_price, status, search_text, created_at, updated_at, archived_at, version, projected_at"
+}
+
+// ScanDest orders scan destinations, keyed by column, as AllColumns, for
+// database/sql rows selected with AllColumns. It fails if a column has no
+// destination, rather than letting the others scan into the wrong fields.
+func ScanDest(byColumn map[string]interface{}) ([]interface{}, error) {
+	if len(byColumn) != len(AllColumns) {
+		return nil, fmt.Errorf("%s: %d scan destinations for %d columns", Table, len(byColumn), len(AllColumns))
+	}
+	dest := make([]interface{}, len(AllColumns))
+	for i, c := range AllColumns {
+		d, ok := byColumn[c]
+		if !ok {
+			return nil, fmt.Errorf("%s: no scan destination for %s", Table, c)
+		}
+		dest[i] = d
+	}
+	return dest, nil
+}
+
+type Data struct {
+	ProductID         string
+	Name              string
+	Description       spanner.NullString
+	Category          string
+	BasePrice         big.Rat
+	DiscountPercent   spanner.NullNumeric
+	DiscountStartDate spanner.NullTime
+	DiscountEndDate   spanner.NullTime
+	DiscountedPrice   spanner.NullNumeric
+	Status            string
+	SearchText        string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	ArchivedAt        spanner.NullTime
+	Version           int64
+	ProjectedAt       time.Time
+}
+
+type Model struct{}
+
+func New() *Model { return &Model{} }
+
+func (m *Model) InsertMap(values map[string]interface{}) *commitplan.Mutation {
+	return commitplan.InsertMap(Table, KeyColumns, values)
+}
+
+func (m *Model) UpsertMap(values map[string]interface{}) *commitplan.Mutation {
+	return commitplan.UpsertMap(Table, KeyColumns, values)
+}
+
+func (m *Model) UpdateMap(id string, values map[string]interface{}) *commitplan.Mutation {
+	values[ProductID] = id
+	return commitplan.UpdateMap(Table, KeyColumns, values)
+}
+
+func (m *Model) Delete(id string) *commitplan.Mutation {
+	return commitplan.Delete(Table, commitplan.KeyOf(ProductID, id))
+}
+
+// ToRow leaves NULL columns out.
+func (m *Model) ToRow(d *Data) map[string]interface{} {
+	row := map[string]interface{}{
+		ProductID:   d.ProductID,
+		Name:        d.Name,
+		Category:    d.Category,
+		BasePrice:   d.BasePrice,
+		Status:      d.Status,
+		SearchText:  d.SearchText,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+		Version:     d.Version,
+		ProjectedAt: d.ProjectedAt,
+	}
+	if d.Description.Valid {
+		row[Description] = d.Description.StringVal
+	}
+	if d.DiscountPercent.Valid {
+		row[DiscountPercent] = d.DiscountPercent.Numeric
+	}
+	if d.DiscountStartDate.Valid {
+		row[DiscountStartDate] = d.DiscountStartDate.Time
+	}
+	if d.DiscountEndDate.Valid {
+		row[DiscountEndDate] = d.DiscountEndDate.Time
+	}
+	if d.DiscountedPrice.Valid {
+		row[DiscountedPrice] = d.DiscountedPrice.Numeric
+	}
+	if d.ArchivedAt.Valid {
+		row[ArchivedAt] = d.ArchivedAt.Time
+	}
+	return row
+}
+
+// FromRow scans a row read with AllColumns.
+func (m *Model) FromRow(row *spanner.Row) (*Data, error) {
+	d := &Data{}
+	err := row.Columns(
+		&d.ProductID,
+		&d.Name,
+		&d.Description,
+		&d.Category,
+		&d.BasePrice,
+		&d.DiscountPercent,
+		&d.DiscountStartDate,
+		&d.DiscountEndDate,
+		&d.DiscountedPrice,
+		&d.Status,
+		&d.SearchText,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+		&d.ArchivedAt,
+		&d.Version,
+		&d.ProjectedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
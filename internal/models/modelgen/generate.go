@@ -0,0 +1,306 @@
+package modelgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Spec says which table to generate into which package.
+type Spec struct {
+	Table   string
+	Package string
+	// Skip lists columns left out of the model, such as ones a migration
+	// has retired but not yet dropped.
+	Skip []string
+}
+
+// FileName is the file Generate's output is written to in the package
+// directory.
+const FileName = "generated.go"
+
+// goType is how a Spanner type is held in Data: value is the NOT NULL
+// form, null the nullable one, and field the member of the null form that
+// holds the value. JSON columns are raw bytes either way, nil when NULL,
+// and scanned as strings (see SelectList).
+type goType struct {
+	value, null, field string
+	imports            []string
+}
+
+var goTypes = map[string]goType{
+	"STRING":    {"string", "spanner.NullString", "StringVal", nil},
+	"INT64":     {"int64", "spanner.NullInt64", "Int64", nil},
+	"FLOAT64":   {"float64", "spanner.NullFloat64", "Float64", nil},
+	"BOOL":      {"bool", "spanner.NullBool", "Bool", nil},
+	"NUMERIC":   {"big.Rat", "spanner.NullNumeric", "Numeric", []string{"math/big"}},
+	"TIMESTAMP": {"time.Time", "spanner.NullTime", "Time", []string{"time"}},
+	"DATE":      {"civil.Date", "spanner.NullDate", "Date", []string{"cloud.google.com/go/civil"}},
+	"BYTES":     {"[]byte", "[]byte", "", nil},
+	"JSON":      {"json.RawMessage", "json.RawMessage", "", []string{"encoding/json"}},
+}
+
+// initialisms are the column-name words Go spells in capitals.
+var initialisms = map[string]bool{"id": true, "url": true, "json": true, "api": true, "uuid": true, "http": true}
+
+func goName(column string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(column, "_") {
+		if initialisms[word] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		if word != "" {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+type field struct {
+	Column   string // SQL name
+	Name     string // Go name of the constant and Data field
+	Type     string // Go type in Data
+	Nullable bool
+	JSON     bool
+	// Get is the null form's value member, e.g. "StringVal"; empty when
+	// the Go type is nil-able itself.
+	Get string
+}
+
+type model struct {
+	Package string
+	Table   string
+	Fields  []field
+	Key     []field
+	// Imports are grouped standard library first, as goimports would.
+	Imports [][]string
+}
+
+// Generate renders spec's table as Go source, gofmt'ed.
+func Generate(schema *Schema, spec Spec) ([]byte, error) {
+	t, ok := schema.Tables[spec.Table]
+	if !ok {
+		return nil, fmt.Errorf("modelgen: no table %s in the migrations", spec.Table)
+	}
+	skip := map[string]bool{}
+	for _, c := range spec.Skip {
+		if _, exists := t.column(c); !exists {
+			return nil, fmt.Errorf("modelgen: %s has no column %s to skip", t.Name, c)
+		}
+		skip[c] = true
+	}
+
+	imports := map[string]bool{"fmt": true, "github.com/tshubham2/commitplan": true}
+	m := model{Package: spec.Package, Table: t.Name}
+	byColumn := map[string]field{}
+	for _, c := range t.Columns {
+		if skip[c.Name] {
+			continue
+		}
+		gt, ok := goTypes[c.Type]
+		if !ok {
+			return nil, fmt.Errorf("modelgen: %s.%s: unsupported type %s", t.Name, c.Name, c.Type)
+		}
+		f := field{Column: c.Name, Name: goName(c.Name), Type: gt.value, JSON: c.Type == "JSON"}
+		if !c.NotNull {
+			f.Type, f.Nullable, f.Get = gt.null, true, gt.field
+		}
+		for _, imp := range gt.imports {
+			imports[imp] = true
+		}
+		m.Fields = append(m.Fields, f)
+		byColumn[c.Name] = f
+	}
+	imports["cloud.google.com/go/spanner"] = true // FromRow takes a *spanner.Row
+	for _, k := range t.Key {
+		f, ok := byColumn[k]
+		if !ok {
+			return nil, fmt.Errorf("modelgen: %s: key column %s is skipped or missing", t.Name, k)
+		}
+		m.Key = append(m.Key, f)
+	}
+	var std, other []string
+	for imp := range imports {
+		if strings.Contains(strings.Split(imp, "/")[0], ".") {
+			other = append(other, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	m.Imports = [][]string{std, other}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, m); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("modelgen: %s: generated invalid Go: %w", t.Name, err)
+	}
+	return src, nil
+}
+
+func (m model) SelectList() string {
+	parts := make([]string, len(m.Fields))
+	for i, f := range m.Fields {
+		parts[i] = f.Column
+		if f.JSON {
+			parts[i] = "TO_JSON_STRING(" + f.Column + ") AS " + f.Column
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+var fileTemplate = template.Must(template.New("model").Funcs(template.FuncMap{
+	"lowerFirst": func(s string) string { return strings.ToLower(s[:1]) + s[1:] },
+}).Parse(`// Code generated by modelgen from the Spanner migrations. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range $i, $group := .Imports}}{{if $i}}
+{{end}}
+{{- range $group}}
+	"{{.}}"
+{{- end}}
+{{- end}}
+)
+
+const Table = "{{.Table}}"
+
+const (
+{{- range .Fields}}
+	{{.Name}} = "{{.Column}}"
+{{- end}}
+)
+
+// KeyColumns is the primary key, in key order.
+var KeyColumns = []string{ {{- range $i, $k := .Key}}{{if $i}}, {{end}}{{$k.Name}}{{end -}} }
+
+// AllColumns is every column in schema order, the order FromRow scans.
+var AllColumns = []string{
+{{- range .Fields}}
+	{{.Name}},
+{{- end}}
+}
+
+// SelectList is AllColumns for a query{{if .HasJSON}}, with the JSON columns
+// converted to strings so FromRow can scan them without reinterpretation{{end}}.
+func SelectList() string {
+	return "{{.SelectList}}"
+}
+
+// ScanDest orders scan destinations, keyed by column, as AllColumns, for
+// database/sql rows selected with AllColumns. It fails if a column has no
+// destination, rather than letting the others scan into the wrong fields.
+func ScanDest(byColumn map[string]interface{}) ([]interface{}, error) {
+	if len(byColumn) != len(AllColumns) {
+		return nil, fmt.Errorf("%s: %d scan destinations for %d columns", Table, len(byColumn), len(AllColumns))
+	}
+	dest := make([]interface{}, len(AllColumns))
+	for i, c := range AllColumns {
+		d, ok := byColumn[c]
+		if !ok {
+			return nil, fmt.Errorf("%s: no scan destination for %s", Table, c)
+		}
+		dest[i] = d
+	}
+	return dest, nil
+}
+
+type Data struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}}{{if and .JSON .Nullable}} // nil when NULL{{end}}
+{{- end}}
+}
+
+type Model struct{}
+
+func New() *Model { return &Model{} }
+
+func (m *Model) InsertMap(values map[string]interface{}) *commitplan.Mutation {
+	return commitplan.InsertMap(Table, KeyColumns, values)
+}
+
+func (m *Model) UpsertMap(values map[string]interface{}) *commitplan.Mutation {
+	return commitplan.UpsertMap(Table, KeyColumns, values)
+}
+{{- if eq (len .Key) 1}}{{$k := index .Key 0}}
+
+func (m *Model) UpdateMap(id {{$k.Type}}, values map[string]interface{}) *commitplan.Mutation {
+	values[{{$k.Name}}] = id
+	return commitplan.UpdateMap(Table, KeyColumns, values)
+}
+
+func (m *Model) Delete(id {{$k.Type}}) *commitplan.Mutation {
+	return commitplan.Delete(Table, commitplan.KeyOf({{$k.Name}}, id))
+}
+{{- end}}
+
+// ToRow leaves NULL columns out.
+func (m *Model) ToRow(d *Data) map[string]interface{} {
+	row := map[string]interface{}{
+{{- range .Fields}}{{if not .Nullable}}
+		{{.Name}}: {{if .JSON}}string(d.{{.Name}}){{else}}d.{{.Name}}{{end}},
+{{- end}}{{end}}
+	}
+{{- range .Fields}}{{if .Nullable}}
+{{- if .JSON}}
+	if len(d.{{.Name}}) > 0 {
+		row[{{.Name}}] = string(d.{{.Name}})
+	}
+{{- else if .Get}}
+	if d.{{.Name}}.Valid {
+		row[{{.Name}}] = d.{{.Name}}.{{.Get}}
+	}
+{{- else}}
+	if d.{{.Name}} != nil {
+		row[{{.Name}}] = d.{{.Name}}
+	}
+{{- end}}
+{{- end}}{{end}}
+	return row
+}
+
+// FromRow scans a row read with AllColumns{{if .HasJSON}}; queries must select
+// SelectList(), since JSON columns are scanned as strings{{end}}.
+func (m *Model) FromRow(row *spanner.Row) (*Data, error) {
+	d := &Data{}
+{{- range .Fields}}{{if .JSON}}
+	var {{lowerFirst .Name}} {{if .Nullable}}spanner.NullString{{else}}string{{end}}
+{{- end}}{{end}}
+	err := row.Columns(
+{{- range .Fields}}
+		&{{if .JSON}}{{lowerFirst .Name}}{{else}}d.{{.Name}}{{end}},
+{{- end}}
+	)
+	if err != nil {
+		return nil, err
+	}
+{{- range .Fields}}{{if .JSON}}
+{{- if .Nullable}}
+	if {{lowerFirst .Name}}.Valid {
+		d.{{.Name}} = json.RawMessage({{lowerFirst .Name}}.StringVal)
+	}
+{{- else}}
+	d.{{.Name}} = json.RawMessage({{lowerFirst .Name}})
+{{- end}}
+{{- end}}{{end}}
+	return d, nil
+}
+`))
+
+func (m model) HasJSON() bool {
+	for _, f := range m.Fields {
+		if f.JSON {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package modelgen_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tshubham2/catalog-proj/internal/models/modelgen"
+)
+
+func parse(t *testing.T, files map[string]string) *modelgen.Schema {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	for name, ddl := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(ddl)}
+	}
+	s, err := modelgen.Parse(fsys)
+	require.NoError(t, err)
+	return s
+}
+
+func TestParse_ReplaysAlterAndDrop(t *testing.T) {
+	s := parse(t, map[string]string{
+		"001_widgets.sql": `CREATE TABLE widgets (
+  widget_id STRING(36) NOT NULL,
+  name STRING(MAX),
+  price INT64 NOT NULL,
+  tags ARRAY<STRING(20)>,
+) PRIMARY KEY (widget_id);
+
+CREATE TABLE scratch (id INT64 NOT NULL) PRIMARY KEY (id DESC);`,
+		"002_widgets.sql": `ALTER TABLE widgets ADD COLUMN amount NUMERIC;
+
+UPDATE widgets SET amount = CAST(price AS NUMERIC) WHERE amount IS NULL;
+
+ALTER TABLE widgets ALTER COLUMN amount NUMERIC NOT NULL;
+
+ALTER TABLE widgets ALTER COLUMN name SET OPTIONS (allow_commit_timestamp = NULL);
+
+ALTER TABLE widgets DROP COLUMN price;
+
+CREATE INDEX idx_widgets_name ON widgets(name);
+
+DROP TABLE scratch;`,
+	})
+
+	require.NotContains(t, s.Tables, "scratch")
+	widgets := s.Tables["widgets"]
+	require.NotNil(t, widgets)
+	assert.Equal(t, []string{"widget_id"}, widgets.Key)
+	assert.Equal(t, []modelgen.Column{
+		{Name: "widget_id", Type: "STRING", NotNull: true},
+		{Name: "name", Type: "STRING"},
+		{Name: "tags", Type: "ARRAY<STRING(20)>"},
+		{Name: "amount", Type: "NUMERIC", NotNull: true},
+	}, widgets.Columns)
+}
+
+func TestParse_RejectsAlterOfUnknownColumn(t *testing.T) {
+	fsys := fstest.MapFS{
+		"001_widgets.sql": {Data: []byte("CREATE TABLE widgets (id INT64 NOT NULL) PRIMARY KEY (id);")},
+		"002_widgets.sql": {Data: []byte("ALTER TABLE widgets DROP COLUMN name;")},
+	}
+	_, err := modelgen.Parse(fsys)
+	assert.ErrorContains(t, err, "002_widgets")
+}
+
+func TestGenerate(t *testing.T) {
+	s := parse(t, map[string]string{
+		"001_widgets.sql": `CREATE TABLE widgets (
+  widget_id STRING(36) NOT NULL,
+  legacy INT64,
+  body JSON NOT NULL,
+  note STRING(MAX),
+) PRIMARY KEY (widget_id);`,
+	})
+
+	src, err := modelgen.Generate(s, modelgen.Spec{Table: "widgets", Package: "m_widget", Skip: []string{"legacy"}})
+	require.NoError(t, err)
+	out := string(src)
+	assert.Contains(t, out, "package m_widget")
+	assert.Contains(t, out, "WidgetID = \"widget_id\"")
+	assert.Contains(t, out, "Note     spanner.NullString")
+	assert.Contains(t, out, "TO_JSON_STRING(body) AS body")
+	assert.Contains(t, out, "func (m *Model) Delete(id string)")
+	assert.NotContains(t, out, "legacy")
+
+	_, err = modelgen.Generate(s, modelgen.Spec{Table: "widgets", Package: "m_widget", Skip: []string{"nope"}})
+	assert.ErrorContains(t, err, "no column nope")
+	_, err = modelgen.Generate(s, modelgen.Spec{Table: "widgets", Package: "m_widget", Skip: []string{"widget_id"}})
+	assert.ErrorContains(t, err, "key column widget_id")
+	_, err = modelgen.Generate(s, modelgen.Spec{Table: "gadgets", Package: "m_gadget"})
+	assert.ErrorContains(t, err, "no table gadgets")
+}
+
+func TestGenerate_RejectsUnsupportedTypes(t *testing.T) {
+	s := parse(t, map[string]string{
+		"001_widgets.sql": "CREATE TABLE widgets (id INT64 NOT NULL, tags ARRAY<STRING(20)>) PRIMARY KEY (id);",
+	})
+	_, err := modelgen.Generate(s, modelgen.Spec{Table: "widgets", Package: "m_widget"})
+	assert.ErrorContains(t, err, "unsupported type ARRAY<STRING(20)>")
+}
@@ -0,0 +1,191 @@
+// Package modelgen generates the internal/models table packages from the
+// Spanner migrations. Parse replays the migrations' DDL into the schema
+// they leave behind, and Generate writes one table's constants, Data
+// struct, and ToRow/FromRow from it, so the models can't drift from the
+// schema or scan columns in the wrong order.
+package modelgen
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+
+	"github.com/tshubham2/catalog-proj/internal/migrate"
+)
+
+// Column is one column of a table, as the DDL declares it.
+type Column struct {
+	Name string
+	// Type is the Spanner type without its length, e.g. "STRING".
+	Type    string
+	NotNull bool
+}
+
+// Table is a table as the migrations leave it. Columns are in the order
+// they were created, which is the order models scan them in.
+type Table struct {
+	Name    string
+	Columns []Column
+	Key     []string
+}
+
+func (t *Table) column(name string) (int, bool) {
+	for i, c := range t.Columns {
+		if c.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Schema is every table the migrations create and don't drop.
+type Schema struct {
+	Tables map[string]*Table
+}
+
+var (
+	createTable = regexp.MustCompile(`(?is)^CREATE\s+TABLE\s+(\w+)\s*\((.*)\)\s*PRIMARY\s+KEY\s*\(([^)]*)\)`)
+	alterColumn = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(\w+)\s+(ADD|ALTER|DROP)\s+COLUMN\s+(?:IF\s+NOT\s+EXISTS\s+)?(.*)$`)
+	dropTable   = regexp.MustCompile(`(?is)^DROP\s+TABLE\s+(\w+)\s*$`)
+	columnDef   = regexp.MustCompile(`(?is)^(\w+)\s+(ARRAY\s*<[^>]*>|\w+)(\s*\([^)]*\))?(.*)$`)
+	notNull     = regexp.MustCompile(`(?i)\bNOT\s+NULL\b`)
+	setOptions  = regexp.MustCompile(`(?i)^\w+\s+SET\s`)
+)
+
+// Parse replays the migrations in fsys, which must be Spanner DDL, in
+// version order. Statements other than CREATE TABLE, DROP TABLE and
+// ALTER TABLE ... COLUMN, such as indexes and backfills, don't change the
+// columns and are skipped.
+func Parse(fsys fs.FS) (*Schema, error) {
+	ms, err := migrate.Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	s := &Schema{Tables: map[string]*Table{}}
+	for _, m := range ms {
+		for _, stmt := range m.Statements() {
+			if err := s.apply(stmt); err != nil {
+				return nil, fmt.Errorf("modelgen: %s: %w", m.Name, err)
+			}
+		}
+	}
+	return s, nil
+}
+
+func (s *Schema) apply(stmt string) error {
+	if m := createTable.FindStringSubmatch(stmt); m != nil {
+		t := &Table{Name: m[1]}
+		for _, def := range splitTopLevel(m[2]) {
+			upper := strings.ToUpper(def)
+			if strings.HasPrefix(upper, "CONSTRAINT") || strings.HasPrefix(upper, "FOREIGN KEY") {
+				continue
+			}
+			c, err := parseColumn(def)
+			if err != nil {
+				return fmt.Errorf("table %s: %w", t.Name, err)
+			}
+			t.Columns = append(t.Columns, c)
+		}
+		for _, k := range strings.Split(m[3], ",") {
+			// Key parts may carry ASC/DESC.
+			if f := strings.Fields(k); len(f) > 0 {
+				t.Key = append(t.Key, f[0])
+			}
+		}
+		if _, dup := s.Tables[t.Name]; dup {
+			return fmt.Errorf("table %s created twice", t.Name)
+		}
+		s.Tables[t.Name] = t
+		return nil
+	}
+
+	if m := dropTable.FindStringSubmatch(stmt); m != nil {
+		delete(s.Tables, m[1])
+		return nil
+	}
+
+	m := alterColumn.FindStringSubmatch(stmt)
+	if m == nil {
+		return nil
+	}
+	t, ok := s.Tables[m[1]]
+	if !ok {
+		return fmt.Errorf("ALTER TABLE %s: no such table", m[1])
+	}
+	switch op, rest := strings.ToUpper(m[2]), strings.TrimSpace(m[3]); op {
+	case "ADD":
+		c, err := parseColumn(rest)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", t.Name, err)
+		}
+		if _, exists := t.column(c.Name); exists {
+			return fmt.Errorf("table %s: column %s added twice", t.Name, c.Name)
+		}
+		t.Columns = append(t.Columns, c)
+	case "ALTER":
+		if setOptions.MatchString(rest) {
+			return nil // options and defaults don't change the model
+		}
+		c, err := parseColumn(rest)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", t.Name, err)
+		}
+		i, exists := t.column(c.Name)
+		if !exists {
+			return fmt.Errorf("table %s: ALTER COLUMN %s: no such column", t.Name, c.Name)
+		}
+		t.Columns[i] = c
+	case "DROP":
+		i, exists := t.column(rest)
+		if !exists {
+			return fmt.Errorf("table %s: DROP COLUMN %s: no such column", t.Name, rest)
+		}
+		t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+	}
+	return nil
+}
+
+func parseColumn(def string) (Column, error) {
+	m := columnDef.FindStringSubmatch(strings.TrimSpace(def))
+	if m == nil {
+		return Column{}, fmt.Errorf("can't parse column %q", def)
+	}
+	return Column{
+		Name:    m[1],
+		Type:    strings.ToUpper(strings.Join(strings.Fields(m[2]), "")),
+		NotNull: notNull.MatchString(m[4]),
+	}, nil
+}
+
+// splitTopLevel splits a CREATE TABLE body on the commas outside
+// parentheses and angle brackets, dropping empty parts.
+func splitTopLevel(body string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i, r := range body {
+		switch r {
+		case '(', '<':
+			depth++
+		case ')', '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, body[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, body[start:])
+
+	out := parts[:0]
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+package models_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tshubham2/catalog-proj/internal/models"
+	"github.com/tshubham2/catalog-proj/internal/models/modelgen"
+	"github.com/tshubham2/catalog-proj/migrations"
+)
+
+// TestGenerated fails when a migration changed a table and the models
+// weren't regenerated.
+func TestGenerated(t *testing.T) {
+	schema, err := modelgen.Parse(migrations.Spanner())
+	require.NoError(t, err)
+
+	for _, spec := range models.Specs {
+		t.Run(spec.Package, func(t *testing.T) {
+			want, err := modelgen.Generate(schema, spec)
+			require.NoError(t, err)
+			got, err := os.ReadFile(filepath.Join(spec.Package, modelgen.FileName))
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(got), "stale; run go generate ./internal/models")
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+const defaultDeadLetterPageSize = 50
+
+var ErrRequeueTarget = errors.New("exactly one of event_id or aggregate_id is required")
+
+// Admin holds the operator actions on dead-lettered events.
+type Admin struct {
+	store     contracts.DeadLetterStore
+	committer committer.Committer
+}
+
+func NewAdmin(store contracts.DeadLetterStore, cm committer.Committer) *Admin {
+	return &Admin{store: store, committer: cm}
+}
+
+func (a *Admin) ListDeadLetters(ctx context.Context, pageSize int, pageToken string) ([]contracts.OutboxEvent, string, error) {
+	if pageSize <= 0 {
+		pageSize = defaultDeadLetterPageSize
+	}
+	return a.store.ListDeadLetters(ctx, pageSize, pageToken)
+}
+
+// Requeue sends dead-lettered events back to the relay with a fresh attempt
+// budget, either a single event or every dead letter of one aggregate. It
+// returns how many events were requeued.
+func (a *Admin) Requeue(ctx context.Context, eventID, aggregateID string) (int, error) {
+	if (eventID == "") == (aggregateID == "") {
+		return 0, ErrRequeueTarget
+	}
+
+	var requeued int
+	err := a.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		var events []contracts.OutboxEvent
+		if eventID != "" {
+			event, err := a.store.FindDeadLetter(ctx, eventID)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, *event)
+		} else {
+			found, err := a.store.FindDeadLettersByAggregate(ctx, aggregateID)
+			if err != nil {
+				return nil, err
+			}
+			events = found
+		}
+
+		plan := committer.NewPlan()
+		for _, event := range events {
+			plan.Add(a.store.RequeueMut(event.ID))
+		}
+		requeued = len(events)
+		return plan, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return requeued, nil
+}
@@ -0,0 +1,72 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+)
+
+// Handler consumes events delivered through a Bus.
+type Handler func(ctx context.Context, event contracts.OutboxEvent) error
+
+// Bus is an in-process publisher: subscribers in the same binary get each
+// event synchronously from the relay. If any handler fails, Publish fails
+// and the relay retries the event for every subscriber, so handlers must be
+// idempotent.
+type Bus struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   []subscription
+}
+
+type subscription struct {
+	id        int
+	eventType string
+	handler   Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers h for events of eventType, or for every event when
+// eventType is empty. The returned func removes the subscription.
+func (b *Bus) Subscribe(eventType string, h Handler) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	id := b.nextID
+	b.subs = append(b.subs, subscription{id: id, eventType: eventType, handler: h})
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, s := range b.subs {
+			if s.id == id {
+				b.subs = append(b.subs[:i:i], b.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (b *Bus) Publish(ctx context.Context, event contracts.OutboxEvent) error {
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subs {
+		if s.eventType != "" && s.eventType != event.EventType {
+			continue
+		}
+		if err := s.handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("subscriber %d: %w", s.id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
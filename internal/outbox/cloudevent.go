@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"encoding/json"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/pkg/cloudevents"
+)
+
+// legacySource is used for events written before payloads were CloudEvents
+// envelopes; it matches usecases.EventSource.
+const legacySource = "/catalog/products"
+
+// CloudEvent returns the CloudEvents envelope stored in the event's payload.
+// Rows written before envelopes were introduced hold bare data; they get an
+// envelope built from the row's columns instead.
+func CloudEvent(event contracts.OutboxEvent) cloudevents.Event {
+	if ce, err := cloudevents.Parse(event.Payload); err == nil {
+		return ce
+	}
+
+	data := event.Payload
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+	return cloudevents.Event{
+		SpecVersion:     cloudevents.SpecVersion,
+		ID:              event.ID,
+		Source:          legacySource,
+		Type:            event.EventType,
+		Subject:         event.AggregateID,
+		Time:            event.CreatedAt.UTC(),
+		DataContentType: "application/json",
+		Sequence:        cloudevents.FormatSequence(event.Sequence),
+		Data:            data,
+	}
+}
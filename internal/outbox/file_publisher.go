@@ -0,0 +1,53 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+)
+
+// FilePublisher appends each event as one JSON line to a file, in
+// CloudEvents structured format. Every write
+// is synced before Publish returns, so an event marked processed is on disk.
+// Meant for local development and for tailing in integration environments.
+type FilePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFilePublisher opens path for appending, creating it if needed.
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file publisher: path is required")
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("file publisher: %w", err)
+	}
+	return &FilePublisher{file: f}, nil
+}
+
+func (p *FilePublisher) Publish(_ context.Context, event contracts.OutboxEvent) error {
+	line, err := json.Marshal(CloudEvent(event))
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.file.Write(line); err != nil {
+		return err
+	}
+	return p.file.Sync()
+}
+
+func (p *FilePublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.file.Close()
+}
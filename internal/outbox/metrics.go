@@ -0,0 +1,15 @@
+package outbox
+
+import "expvar"
+
+// Retention counters, published under /debug/vars as "outbox_retention":
+// runs, failures, purged_processed, purged_dead_letter and last_run_unix
+// (the last run that finished without error).
+var (
+	retentionMetrics = expvar.NewMap("outbox_retention")
+	lastRun          = new(expvar.Int)
+)
+
+func init() {
+	retentionMetrics.Set("last_run_unix", lastRun)
+}
@@ -0,0 +1,90 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+)
+
+// Publisher delivers a single outbox event to the outside world. Returning
+// nil means the event was handed off and may be marked processed. The relay
+// publishes events of different aggregates concurrently, so implementations
+// must be safe for concurrent use.
+type Publisher interface {
+	Publish(ctx context.Context, event contracts.OutboxEvent) error
+}
+
+// LogPublisher writes each event to the standard logger. It's the default
+// when no real sink is configured, so the relay still drains the outbox.
+type LogPublisher struct{}
+
+func (LogPublisher) Publish(_ context.Context, event contracts.OutboxEvent) error {
+	log.Printf("outbox: %s %s aggregate=%s payload=%s", event.ID, event.EventType, event.AggregateID, event.Payload)
+	return nil
+}
+
+// Publisher kinds accepted by PublisherConfig.
+const (
+	PublisherLog     = "log"
+	PublisherFile    = "file"
+	PublisherWebhook = "webhook"
+	PublisherBus     = "bus"
+)
+
+// PublisherConfig selects and configures the sink the relay delivers to.
+// Only the fields of the selected kind are used.
+type PublisherConfig struct {
+	// Kind is one of the Publisher* constants; empty means PublisherLog.
+	Kind string
+	// FilePath is the JSON-lines file appended to by PublisherFile.
+	FilePath string
+	// Webhook configures PublisherWebhook.
+	Webhook WebhookConfig
+}
+
+// NewPublisher builds the publisher cfg asks for. Kinds that hold resources
+// (the file sink) return something that also implements io.Closer.
+func NewPublisher(cfg PublisherConfig) (Publisher, error) {
+	switch cfg.Kind {
+	case "", PublisherLog:
+		return LogPublisher{}, nil
+	case PublisherFile:
+		p, err := NewFilePublisher(cfg.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	case PublisherWebhook:
+		p, err := NewWebhookPublisher(cfg.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	case PublisherBus:
+		return NewBus(), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q", cfg.Kind)
+	}
+}
+
+// Before returns a publisher that runs h on each event before handing it to
+// next, for in-process consumers that must see every event the relay
+// delivers, such as the product_views projection. If h fails, next isn't
+// called and the relay retries the event, so h must be idempotent.
+func Before(h Handler, next Publisher) Publisher {
+	return beforePublisher{handler: h, next: next}
+}
+
+type beforePublisher struct {
+	handler Handler
+	next    Publisher
+}
+
+func (p beforePublisher) Publish(ctx context.Context, event contracts.OutboxEvent) error {
+	if err := p.handler(ctx, event); err != nil {
+		return err
+	}
+	return p.next.Publish(ctx, event)
+}
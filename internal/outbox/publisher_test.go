@@ -0,0 +1,289 @@
+package outbox_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases"
+	"github.com/tshubham2/catalog-proj/internal/outbox"
+	"github.com/tshubham2/catalog-proj/internal/pkg/cloudevents"
+	"github.com/tshubham2/catalog-proj/internal/pkg/eventmeta"
+)
+
+// sampleEvent has a bare payload, like rows written before CloudEvents
+// envelopes; publishers wrap it on the way out.
+func sampleEvent(id, eventType string) contracts.OutboxEvent {
+	return contracts.OutboxEvent{
+		ID:          id,
+		EventType:   eventType,
+		AggregateID: "product-1",
+		Sequence:    3,
+		Payload:     json.RawMessage(`{"product_id":"product-1"}`),
+		CreatedAt:   time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestFilePublisher_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	ctx := context.Background()
+
+	pub, err := outbox.NewFilePublisher(path)
+	require.NoError(t, err)
+	require.NoError(t, pub.Publish(ctx, sampleEvent("e1", "product.created")))
+	require.NoError(t, pub.Close())
+
+	// Reopening appends rather than truncating.
+	pub, err = outbox.NewFilePublisher(path)
+	require.NoError(t, err)
+	require.NoError(t, pub.Publish(ctx, sampleEvent("e2", "product.updated")))
+	require.NoError(t, pub.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 2)
+	assert.Equal(t, "e1", lines[0]["id"])
+	assert.Equal(t, "1.0", lines[1]["specversion"])
+	assert.Equal(t, "product.updated", lines[1]["type"])
+	assert.Equal(t, "product-1", lines[1]["subject"])
+	assert.Equal(t, "00000000000000000003", lines[1]["sequence"])
+	assert.Equal(t, map[string]interface{}{"product_id": "product-1"}, lines[1]["data"])
+}
+
+func TestCloudEvent_UsesStoredEnvelope(t *testing.T) {
+	price, _ := domain.NewMoney(1999, 100)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	p, err := domain.NewProduct("product-1", "Widget", "", "tools", price, now)
+	require.NoError(t, err)
+	event, err := usecases.EnrichEvent(p.ID(), p.DomainEvents()[0], eventmeta.Metadata{})
+	require.NoError(t, err)
+
+	ce := outbox.CloudEvent(event)
+	require.NoError(t, ce.Validate())
+	assert.Equal(t, event.ID, ce.ID)
+	assert.Equal(t, usecases.EventSource, ce.Source)
+	assert.Equal(t, "product.created", ce.Type)
+	assert.Equal(t, "product-1", ce.Subject)
+	assert.True(t, now.Equal(ce.Time))
+	assert.Equal(t, "application/json", ce.DataContentType)
+	assert.Equal(t, "urn:catalog:events:product.v1.ProductCreated:v1", ce.DataSchema)
+	seq, err := cloudevents.ParseSequence(ce.Sequence)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), seq)
+	assert.JSONEq(t, `{
+		"product_id": "product-1",
+		"name": "Widget",
+		"category": "tools",
+		"description": "",
+		"base_price": {"numerator": "1999", "denominator": "100", "decimal": "19.99"}
+	}`, string(ce.Data))
+}
+
+func TestWebhookPublisher_SignsRequests(t *testing.T) {
+	secret := "s3cret"
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	pub, err := outbox.NewWebhookPublisher(outbox.WebhookConfig{URL: srv.URL, Secret: secret})
+	require.NoError(t, err)
+	require.NoError(t, pub.Publish(context.Background(), sampleEvent("e1", "product.created")))
+
+	require.NotNil(t, got)
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, cloudevents.ContentType, got.Header.Get("Content-Type"))
+	assert.Equal(t, "e1", got.Header.Get(outbox.HeaderEventID))
+
+	ce, err := cloudevents.Parse(body)
+	require.NoError(t, err)
+	assert.Equal(t, "e1", ce.ID)
+	assert.JSONEq(t, `{"product_id":"product-1"}`, string(ce.Data))
+	assert.Equal(t, "product.created", got.Header.Get(outbox.HeaderEventType))
+
+	ts := got.Header.Get(outbox.HeaderTimestamp)
+	require.NotEmpty(t, ts)
+	assert.Equal(t, outbox.SignWebhook([]byte(secret), ts, ce, body), got.Header.Get(outbox.HeaderSignature))
+	assert.NotEqual(t, outbox.SignWebhook([]byte("other"), ts, ce, body), got.Header.Get(outbox.HeaderSignature))
+}
+
+func TestWebhookPublisher_BinaryMode(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	pub, err := outbox.NewWebhookPublisher(outbox.WebhookConfig{
+		URL:    srv.URL,
+		Mode:   outbox.ModeBinary,
+		Secret: "s3cret",
+	})
+	require.NoError(t, err)
+	event := sampleEvent("e1", "product.created")
+	require.NoError(t, pub.Publish(context.Background(), event))
+
+	require.NotNil(t, got)
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	assert.JSONEq(t, string(event.Payload), string(body))
+
+	ce, err := cloudevents.FromBinary(got.Header, body)
+	require.NoError(t, err)
+	assert.Equal(t, outbox.CloudEvent(event).ID, ce.ID)
+	assert.Equal(t, "product.created", ce.Type)
+	assert.Equal(t, "product-1", ce.Subject)
+	assert.True(t, event.CreatedAt.Equal(ce.Time))
+
+	ts := got.Header.Get(outbox.HeaderTimestamp)
+	assert.Equal(t, outbox.SignWebhook([]byte("s3cret"), ts, ce, body), got.Header.Get(outbox.HeaderSignature))
+
+	// The ce-* headers are signed too: the same body under another type or
+	// subject does not verify.
+	retyped := ce
+	retyped.Type = "product.archived"
+	assert.NotEqual(t, outbox.SignWebhook([]byte("s3cret"), ts, retyped, body), got.Header.Get(outbox.HeaderSignature))
+	resubjected := ce
+	resubjected.Subject = "product-2"
+	assert.NotEqual(t, outbox.SignWebhook([]byte("s3cret"), ts, resubjected, body), got.Header.Get(outbox.HeaderSignature))
+
+	// So are the ordering headers: a replay with another ce-sequence fails.
+	require.NotEmpty(t, got.Header.Get(cloudevents.HeaderSequence))
+	replayed := got.Header.Clone()
+	replayed.Set(cloudevents.HeaderSequence, cloudevents.FormatSequence(99))
+	reordered, err := cloudevents.FromBinary(replayed, body)
+	require.NoError(t, err)
+	assert.NotEqual(t, outbox.SignWebhook([]byte("s3cret"), ts, reordered, body), got.Header.Get(outbox.HeaderSignature))
+
+	_, err = outbox.NewWebhookPublisher(outbox.WebhookConfig{URL: srv.URL, Mode: "batched", Secret: "s3cret"})
+	assert.Error(t, err)
+}
+
+func TestWebhookPublisher_Failures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(outbox.HeaderEventID) == "slow" {
+			time.Sleep(200 * time.Millisecond)
+			return
+		}
+		http.Error(w, "try later", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	pub, err := outbox.NewWebhookPublisher(outbox.WebhookConfig{
+		URL:     srv.URL,
+		Secret:  "s3cret",
+		Timeout: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	err = pub.Publish(context.Background(), sampleEvent("e1", "product.created"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+	assert.Contains(t, err.Error(), "try later")
+
+	assert.Error(t, pub.Publish(context.Background(), sampleEvent("slow", "product.created")))
+
+	_, err = outbox.NewWebhookPublisher(outbox.WebhookConfig{URL: srv.URL})
+	assert.Error(t, err, "secret is required")
+}
+
+func TestBus_RoutesByEventType(t *testing.T) {
+	bus := outbox.NewBus()
+	ctx := context.Background()
+
+	var all, created []string
+	bus.Subscribe("", func(_ context.Context, e contracts.OutboxEvent) error {
+		all = append(all, e.ID)
+		return nil
+	})
+	unsubscribe := bus.Subscribe("product.created", func(_ context.Context, e contracts.OutboxEvent) error {
+		created = append(created, e.ID)
+		return nil
+	})
+
+	require.NoError(t, bus.Publish(ctx, sampleEvent("e1", "product.created")))
+	require.NoError(t, bus.Publish(ctx, sampleEvent("e2", "product.updated")))
+	unsubscribe()
+	require.NoError(t, bus.Publish(ctx, sampleEvent("e3", "product.created")))
+
+	assert.Equal(t, []string{"e1", "e2", "e3"}, all)
+	assert.Equal(t, []string{"e1"}, created)
+}
+
+func TestBus_HandlerErrorFailsPublish(t *testing.T) {
+	bus := outbox.NewBus()
+	boom := errors.New("boom")
+
+	var calls int
+	bus.Subscribe("", func(context.Context, contracts.OutboxEvent) error { return boom })
+	bus.Subscribe("", func(context.Context, contracts.OutboxEvent) error { calls++; return nil })
+
+	err := bus.Publish(context.Background(), sampleEvent("e1", "product.created"))
+	assert.ErrorIs(t, err, boom)
+	assert.Equal(t, 1, calls, "other subscribers still get the event")
+}
+
+func TestBefore_RunsHandlerFirstAndStopsOnError(t *testing.T) {
+	var calls []string
+	bus := outbox.NewBus()
+	bus.Subscribe("", func(context.Context, contracts.OutboxEvent) error {
+		calls = append(calls, "publish")
+		return nil
+	})
+	fail := false
+	pub := outbox.Before(func(context.Context, contracts.OutboxEvent) error {
+		calls = append(calls, "handler")
+		if fail {
+			return errors.New("projection down")
+		}
+		return nil
+	}, bus)
+
+	require.NoError(t, pub.Publish(context.Background(), sampleEvent("e1", "product.created")))
+	fail = true
+	assert.Error(t, pub.Publish(context.Background(), sampleEvent("e2", "product.updated")))
+	assert.Equal(t, []string{"handler", "publish", "handler"}, calls)
+}
+
+func TestNewPublisher(t *testing.T) {
+	pub, err := outbox.NewPublisher(outbox.PublisherConfig{})
+	require.NoError(t, err)
+	assert.IsType(t, outbox.LogPublisher{}, pub)
+
+	pub, err = outbox.NewPublisher(outbox.PublisherConfig{Kind: outbox.PublisherBus})
+	require.NoError(t, err)
+	assert.IsType(t, &outbox.Bus{}, pub)
+
+	_, err = outbox.NewPublisher(outbox.PublisherConfig{Kind: outbox.PublisherWebhook})
+	assert.Error(t, err)
+
+	_, err = outbox.NewPublisher(outbox.PublisherConfig{Kind: "kafka"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,336 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Retry        RetryPolicy
+
+	// Owner identifies this relay in claimed_by. It must be unique across
+	// replicas.
+	Owner string
+	// Lease is how long a claim lasts without renewal. The relay renews at a
+	// third of it while publishing, so it only runs out if the relay dies.
+	Lease time.Duration
+	// Workers bounds how many events of a batch are published at once. A
+	// batch never holds two events of the same aggregate, so this only
+	// parallelizes across aggregates.
+	Workers int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		Retry:        DefaultRetryPolicy(),
+		Owner:        DefaultOwner(),
+		Lease:        30 * time.Second,
+		Workers:      8,
+	}
+}
+
+// withDefaults fills zero fields from DefaultConfig.
+func (c Config) withDefaults() Config {
+	def := DefaultConfig()
+	if c.PollInterval <= 0 {
+		c.PollInterval = def.PollInterval
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = def.BatchSize
+	}
+	if c.Owner == "" {
+		c.Owner = def.Owner
+	}
+	if c.Lease <= 0 {
+		c.Lease = def.Lease
+	}
+	if c.Workers <= 0 {
+		c.Workers = def.Workers
+	}
+	if c.Retry.MaxAttempts <= 0 {
+		c.Retry.MaxAttempts = def.Retry.MaxAttempts
+	}
+	if c.Retry.BaseDelay <= 0 {
+		c.Retry.BaseDelay = def.Retry.BaseDelay
+	}
+	if c.Retry.MaxDelay <= 0 {
+		c.Retry.MaxDelay = def.Retry.MaxDelay
+	}
+	return c
+}
+
+// DefaultOwner returns hostname plus a random suffix, so two relays in the
+// same process or pod still get distinct identities.
+func DefaultOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "relay"
+	}
+	return host + "-" + uuid.NewString()[:8]
+}
+
+// Relay moves events from outbox_events to a Publisher.
+//
+// Each poll claims a batch by writing a lease (claimed_by, lease_expires_at)
+// in a read-write transaction, so concurrent relays never claim the same
+// event. Leases are renewed while the batch is being published; if a relay
+// dies, its leases expire and another replica picks the events up.
+//
+// Events of one aggregate are delivered strictly in sequence order: the store
+// only hands out an event once every earlier event of its aggregate has been
+// processed, so a batch holds at most one event per aggregate and its events
+// can be published in parallel.
+//
+// Each event is settled in its own transaction right after the publish, and
+// only if this relay still holds it. A crash, or a lease lost to a stalled
+// publish, can cause a redelivery but never a lost event. Failed deliveries
+// are retried with exponential backoff and dead-lettered once the retry
+// policy is exhausted. A failing event holds back later events of its own
+// aggregate, but never those of other aggregates.
+type Relay struct {
+	store     contracts.OutboxStore
+	publisher Publisher
+	committer committer.Committer
+	clock     clock.Clock
+	cfg       Config
+	jitter    func() float64
+}
+
+// NewRelay builds a relay; zero fields in cfg take their DefaultConfig value.
+func NewRelay(
+	store contracts.OutboxStore,
+	pub Publisher,
+	cm committer.Committer,
+	clk clock.Clock,
+	cfg Config,
+) *Relay {
+	return &Relay{
+		store:     store,
+		publisher: pub,
+		committer: cm,
+		clock:     clk,
+		cfg:       cfg.withDefaults(),
+		jitter:    rand.Float64,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled. A non-empty batch is followed
+// by another poll straight away: settling it may have unblocked the next
+// event of each aggregate, so a backlog drains without waiting.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.RelayOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("outbox relay: %v", err)
+		}
+		if err == nil && n > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce claims one batch of due events, delivers them on up to
+// cfg.Workers goroutines and returns how many it claimed. Errors are only
+// returned for storage failures; delivery failures are recorded on the event.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	events, err := r.claim(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("claim: %w", err)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	held := newLeaseSet(events)
+	renewCtx, stopRenewing := context.WithCancel(ctx)
+	defer stopRenewing()
+	go r.keepLeases(renewCtx, held)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		slots    = make(chan struct{}, r.cfg.Workers)
+	)
+	for _, event := range events {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-slots; wg.Done() }()
+			if err := r.deliver(ctx, event); err != nil {
+				errOnce.Do(func() { firstErr = err })
+				return
+			}
+			held.release(event.ID)
+		}()
+	}
+	wg.Wait()
+	return len(events), firstErr
+}
+
+// deliver publishes one claimed event and settles it.
+func (r *Relay) deliver(ctx context.Context, event contracts.OutboxEvent) error {
+	pubErr := r.publisher.Publish(ctx, event)
+	if pubErr != nil && ctx.Err() != nil {
+		// Shutting down; the lease will lapse and the event is retried
+		// without counting this attempt.
+		return ctx.Err()
+	}
+
+	if err := r.settle(ctx, event, pubErr); err != nil {
+		return fmt.Errorf("settle %s: %w", event.ID, err)
+	}
+	return nil
+}
+
+func (r *Relay) claim(ctx context.Context) ([]contracts.OutboxEvent, error) {
+	var claimed []contracts.OutboxEvent
+	err := r.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		now := r.clock.Now()
+		events, err := r.store.ListClaimable(ctx, now, r.cfg.BatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		plan := committer.NewPlan()
+		for _, event := range events {
+			plan.Add(r.store.ClaimMut(event.ID, r.cfg.Owner, now.Add(r.cfg.Lease)))
+		}
+		claimed = events
+		return plan, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// settle records the outcome of a publish, unless another relay has taken
+// the event over after our lease ran out. In that case its outcome wins.
+func (r *Relay) settle(ctx context.Context, event contracts.OutboxEvent, pubErr error) error {
+	return r.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		owner, err := r.store.ClaimOwner(ctx, event.ID)
+		if err != nil {
+			return nil, err
+		}
+		if owner != r.cfg.Owner {
+			log.Printf("outbox relay: lease on %s lost to %q, leaving it alone", event.ID, owner)
+			return nil, nil
+		}
+
+		plan := committer.NewPlan()
+		if pubErr != nil {
+			r.recordFailure(plan, event, pubErr)
+		} else {
+			plan.Add(r.store.MarkProcessedMut(event.ID, r.clock.Now()))
+		}
+		return plan, nil
+	})
+}
+
+func (r *Relay) recordFailure(plan *committer.Plan, event contracts.OutboxEvent, cause error) {
+	attempts := event.Attempts + 1
+	now := r.clock.Now()
+
+	if r.cfg.Retry.Exhausted(attempts) {
+		log.Printf("outbox relay: dead-lettering %s (%s) after %d attempts: %v", event.ID, event.EventType, attempts, cause)
+		plan.Add(r.store.DeadLetterMut(event.ID, attempts, cause.Error(), now))
+		return
+	}
+
+	delay := r.cfg.Retry.Backoff(attempts, r.jitter())
+	log.Printf("outbox relay: delivering %s (%s) failed, attempt %d, retrying in %s: %v", event.ID, event.EventType, attempts, delay, cause)
+	plan.Add(r.store.RetryLaterMut(event.ID, attempts, cause.Error(), now.Add(delay)))
+}
+
+// keepLeases extends the leases still held until ctx is done.
+func (r *Relay) keepLeases(ctx context.Context, held *leaseSet) {
+	ticker := time.NewTicker(r.cfg.Lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := r.renew(ctx, held.ids()); err != nil && ctx.Err() == nil {
+			log.Printf("outbox relay: renewing leases: %v", err)
+		}
+	}
+}
+
+func (r *Relay) renew(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.committer.RunInTransaction(ctx, func(ctx context.Context) (*committer.Plan, error) {
+		expires := r.clock.Now().Add(r.cfg.Lease)
+		plan := committer.NewPlan()
+		for _, id := range ids {
+			owner, err := r.store.ClaimOwner(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if owner == r.cfg.Owner {
+				plan.Add(r.store.ClaimMut(id, r.cfg.Owner, expires))
+			}
+		}
+		return plan, nil
+	})
+}
+
+// leaseSet tracks the claimed events that haven't been settled yet.
+type leaseSet struct {
+	mu   sync.Mutex
+	held map[string]struct{}
+}
+
+func newLeaseSet(events []contracts.OutboxEvent) *leaseSet {
+	s := &leaseSet{held: make(map[string]struct{}, len(events))}
+	for _, e := range events {
+		s.held[e.ID] = struct{}{}
+	}
+	return s
+}
+
+func (s *leaseSet) release(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.held, id)
+}
+
+func (s *leaseSet) ids() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, 0, len(s.held))
+	for id := range s.held {
+		out = append(out, id)
+	}
+	return out
+}
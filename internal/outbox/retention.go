@@ -0,0 +1,170 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+)
+
+type RetentionConfig struct {
+	// Interval between purges when running as a loop.
+	Interval time.Duration
+	// ProcessedTTL is how long delivered events are kept.
+	ProcessedTTL time.Duration
+	// DeadLetterTTL is how long dead-lettered events are kept. They are
+	// kept at least as long as processed ones, to leave time to requeue.
+	// A dead letter holds back every later event of its aggregate (see
+	// ListClaimable), so purging it releases them to the relay, which then
+	// publishes them with the dead-lettered one missing.
+	DeadLetterTTL time.Duration
+	// Window bounds one delete: each statement only removes events settled
+	// within Window of the oldest one left.
+	Window time.Duration
+	// PurgeHistory allows the job to delete anything at all. outbox_events
+	// is also each product's event history: EventStore.LoadFromEvents,
+	// verify-history and rebuild-views replay it from ProductCreated on,
+	// and fail for a product once any of its events is gone. Leave it
+	// false while relying on those.
+	PurgeHistory bool
+}
+
+func DefaultRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		Interval:      time.Hour,
+		ProcessedTTL:  7 * 24 * time.Hour,
+		DeadLetterTTL: 30 * 24 * time.Hour,
+		Window:        time.Hour,
+	}
+}
+
+// withDefaults fills zero fields from DefaultRetentionConfig.
+func (c RetentionConfig) withDefaults() RetentionConfig {
+	def := DefaultRetentionConfig()
+	if c.Interval <= 0 {
+		c.Interval = def.Interval
+	}
+	if c.ProcessedTTL <= 0 {
+		c.ProcessedTTL = def.ProcessedTTL
+	}
+	if c.DeadLetterTTL <= 0 {
+		c.DeadLetterTTL = def.DeadLetterTTL
+	}
+	if c.DeadLetterTTL < c.ProcessedTTL {
+		c.DeadLetterTTL = c.ProcessedTTL
+	}
+	if c.Window <= 0 {
+		c.Window = def.Window
+	}
+	return c
+}
+
+// ErrHistoryRetained is returned by PurgeOnce unless
+// RetentionConfig.PurgeHistory is set.
+var ErrHistoryRetained = errors.New("outbox retention: events are kept as product history; set PurgeHistory to purge them")
+
+// PurgeStats counts the events removed by one purge.
+type PurgeStats struct {
+	Processed   int64
+	DeadLetters int64
+}
+
+// Retention deletes settled outbox events once they are past their TTL.
+// Pending events are never touched, and nothing is deleted unless
+// PurgeHistory is set.
+//
+// Deletes walk forward from the oldest settled event one Window at a time,
+// so a large backlog is removed in many small partitioned deletes instead
+// of one that scans everything. Running it on several replicas at once is
+// harmless: deleting an already deleted row is a no-op.
+type Retention struct {
+	store contracts.OutboxRetentionStore
+	clock clock.Clock
+	cfg   RetentionConfig
+}
+
+// NewRetention builds the job; zero fields in cfg take their
+// DefaultRetentionConfig value.
+func NewRetention(store contracts.OutboxRetentionStore, clk clock.Clock, cfg RetentionConfig) *Retention {
+	return &Retention{store: store, clock: clk, cfg: cfg.withDefaults()}
+}
+
+// Run purges once straight away and then every Interval until ctx is
+// cancelled. Without PurgeHistory it logs why and returns.
+func (r *Retention) Run(ctx context.Context) {
+	if !r.cfg.PurgeHistory {
+		log.Print(ErrHistoryRetained)
+		return
+	}
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		stats, err := r.PurgeOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("outbox retention: %v", err)
+		}
+		if stats.Processed > 0 || stats.DeadLetters > 0 {
+			log.Printf("outbox retention: purged %d processed and %d dead-lettered events", stats.Processed, stats.DeadLetters)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce deletes everything past its TTL. On error the stats still
+// count what was deleted before it.
+func (r *Retention) PurgeOnce(ctx context.Context) (PurgeStats, error) {
+	var stats PurgeStats
+	if !r.cfg.PurgeHistory {
+		return stats, ErrHistoryRetained
+	}
+	now := r.clock.Now()
+	retentionMetrics.Add("runs", 1)
+
+	n, err := r.purge(ctx, contracts.OutboxStatusProcessed, now.Add(-r.cfg.ProcessedTTL))
+	stats.Processed = n
+	if err == nil {
+		n, err = r.purge(ctx, contracts.OutboxStatusDeadLetter, now.Add(-r.cfg.DeadLetterTTL))
+		stats.DeadLetters = n
+	}
+	if err != nil {
+		retentionMetrics.Add("failures", 1)
+		return stats, err
+	}
+
+	lastRun.Set(now.Unix())
+	return stats, nil
+}
+
+func (r *Retention) purge(ctx context.Context, status string, cutoff time.Time) (int64, error) {
+	var total int64
+	for {
+		oldest, ok, err := r.store.OldestSettled(ctx, status)
+		if err != nil {
+			return total, fmt.Errorf("oldest %s: %w", status, err)
+		}
+		if !ok || !oldest.Before(cutoff) {
+			return total, nil
+		}
+
+		before := oldest.Add(r.cfg.Window)
+		if before.After(cutoff) {
+			before = cutoff
+		}
+		n, err := r.store.PurgeSettled(ctx, status, before)
+		if err != nil {
+			return total, fmt.Errorf("purge %s before %s: %w", status, before.Format(time.RFC3339), err)
+		}
+		total += n
+		retentionMetrics.Add("purged_"+status, n)
+	}
+}
@@ -0,0 +1,100 @@
+package outbox_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/outbox"
+	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+)
+
+// settledStore keeps processed_at per status and records every delete.
+type settledStore struct {
+	settled map[string][]time.Time
+	deletes []time.Time
+}
+
+func (s *settledStore) OldestSettled(_ context.Context, status string) (time.Time, bool, error) {
+	times := s.settled[status]
+	if len(times) == 0 {
+		return time.Time{}, false, nil
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times[0], true, nil
+}
+
+func (s *settledStore) PurgeSettled(_ context.Context, status string, before time.Time) (int64, error) {
+	s.deletes = append(s.deletes, before)
+	var kept []time.Time
+	for _, t := range s.settled[status] {
+		if !t.Before(before) {
+			kept = append(kept, t)
+		}
+	}
+	n := int64(len(s.settled[status]) - len(kept))
+	s.settled[status] = kept
+	return n, nil
+}
+
+func TestRetention_PurgeOnce(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	days := func(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+
+	store := &settledStore{settled: map[string][]time.Time{
+		contracts.OutboxStatusProcessed:  {days(40), days(10), days(8), days(2)},
+		contracts.OutboxStatusDeadLetter: {days(40), days(10)},
+	}}
+	retention := outbox.NewRetention(store, clock.FixedClock{T: now}, outbox.RetentionConfig{
+		ProcessedTTL:  7 * 24 * time.Hour,
+		DeadLetterTTL: 30 * 24 * time.Hour,
+		Window:        24 * time.Hour,
+		PurgeHistory:  true,
+	})
+
+	stats, err := retention.PurgeOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, outbox.PurgeStats{Processed: 3, DeadLetters: 1}, stats)
+	assert.Equal(t, []time.Time{days(2)}, store.settled[contracts.OutboxStatusProcessed])
+	assert.Equal(t, []time.Time{days(10)}, store.settled[contracts.OutboxStatusDeadLetter])
+
+	// Each delete covers at most one window past the oldest remaining event,
+	// and the gap between 40 and 10 days is skipped rather than walked.
+	assert.Equal(t, []time.Time{days(39), days(9), days(7), days(39)}, store.deletes)
+}
+
+func TestRetention_DeadLetterTTLAtLeastProcessedTTL(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := &settledStore{settled: map[string][]time.Time{
+		contracts.OutboxStatusDeadLetter: {now.Add(-48 * time.Hour)},
+	}}
+	retention := outbox.NewRetention(store, clock.FixedClock{T: now}, outbox.RetentionConfig{
+		ProcessedTTL:  72 * time.Hour,
+		DeadLetterTTL: 24 * time.Hour, // shorter than ProcessedTTL, raised to it
+		PurgeHistory:  true,
+	})
+
+	stats, err := retention.PurgeOnce(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, stats.DeadLetters)
+}
+
+func TestRetention_KeepsHistoryByDefault(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := &settledStore{settled: map[string][]time.Time{
+		contracts.OutboxStatusProcessed:  {now.Add(-400 * 24 * time.Hour)},
+		contracts.OutboxStatusDeadLetter: {now.Add(-400 * 24 * time.Hour)},
+	}}
+	retention := outbox.NewRetention(store, clock.FixedClock{T: now}, outbox.RetentionConfig{})
+
+	stats, err := retention.PurgeOnce(context.Background())
+	require.ErrorIs(t, err, outbox.ErrHistoryRetained)
+	assert.Zero(t, stats)
+	assert.Empty(t, store.deletes)
+	assert.Len(t, store.settled[contracts.OutboxStatusProcessed], 1)
+}
@@ -0,0 +1,42 @@
+package outbox
+
+import "time"
+
+// RetryPolicy controls how failed deliveries are rescheduled and when an
+// event is given up on.
+type RetryPolicy struct {
+	MaxAttempts int           // failed deliveries before an event is dead-lettered
+	BaseDelay   time.Duration // wait after the first failure
+	MaxDelay    time.Duration // cap for the exponential growth
+	Jitter      float64       // fraction of the delay that is randomised away, 0..1
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   time.Second,
+		MaxDelay:    5 * time.Minute,
+		Jitter:      0.2,
+	}
+}
+
+// Backoff returns how long to wait after the given number of failed
+// attempts. The delay doubles per attempt up to MaxDelay, then up to Jitter
+// of it is shaved off using u, a uniform sample in [0, 1), so replicas that
+// failed together don't retry together.
+func (p RetryPolicy) Backoff(attempts int64, u float64) time.Duration {
+	d := p.BaseDelay
+	for i := int64(1); i < attempts && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d - time.Duration(float64(d)*p.Jitter*u)
+}
+
+// Exhausted reports whether an event that has failed attempts times should
+// be dead-lettered.
+func (p RetryPolicy) Exhausted(attempts int64) bool {
+	return attempts >= int64(p.MaxAttempts)
+}
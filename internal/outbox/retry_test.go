@@ -0,0 +1,37 @@
+package outbox_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tshubham2/catalog-proj/internal/outbox"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := outbox.RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Second,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.5,
+	}
+
+	assert.Equal(t, time.Second, p.Backoff(1, 0))
+	assert.Equal(t, 2*time.Second, p.Backoff(2, 0))
+	assert.Equal(t, 8*time.Second, p.Backoff(4, 0))
+	assert.Equal(t, 10*time.Second, p.Backoff(5, 0))  // capped
+	assert.Equal(t, 10*time.Second, p.Backoff(60, 0)) // no overflow
+
+	// Jitter only ever shortens the delay, by at most Jitter of it.
+	assert.Equal(t, 4*time.Second, p.Backoff(4, 1))
+	assert.Equal(t, 6*time.Second, p.Backoff(4, 0.5))
+}
+
+func TestRetryPolicy_Exhausted(t *testing.T) {
+	p := outbox.RetryPolicy{MaxAttempts: 3}
+
+	assert.False(t, p.Exhausted(2))
+	assert.True(t, p.Exhausted(3))
+	assert.True(t, p.Exhausted(4))
+}
@@ -0,0 +1,147 @@
+// Package cloudevents holds the CloudEvents 1.0 envelope used for outbox
+// events, in JSON structured form and as HTTP binary-mode headers.
+// See https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/spec.md.
+package cloudevents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	SpecVersion = "1.0"
+	// ContentType is the media type of a structured-mode JSON event.
+	ContentType = "application/cloudevents+json"
+)
+
+// Event is a CloudEvents 1.0 event with the attributes this service sets.
+// Sequence is the sequence extension: the aggregate's event sequence,
+// zero-padded so it also orders correctly as a string. TraceParent and
+// TraceState are the distributed tracing extension: the W3C trace context
+// of the request that caused the event.
+type Event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	DataSchema      string          `json:"dataschema,omitempty"`
+	Sequence        string          `json:"sequence,omitempty"`
+	TraceParent     string          `json:"traceparent,omitempty"`
+	TraceState      string          `json:"tracestate,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// FormatSequence renders n for the sequence extension.
+func FormatSequence(n int64) string {
+	return fmt.Sprintf("%020d", n)
+}
+
+// ParseSequence is the inverse of FormatSequence.
+func ParseSequence(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// Validate checks the attributes the spec requires.
+func (e Event) Validate() error {
+	switch {
+	case e.SpecVersion != SpecVersion:
+		return fmt.Errorf("cloudevents: unsupported specversion %q", e.SpecVersion)
+	case e.ID == "":
+		return errors.New("cloudevents: id is required")
+	case e.Source == "":
+		return errors.New("cloudevents: source is required")
+	case e.Type == "":
+		return errors.New("cloudevents: type is required")
+	}
+	return nil
+}
+
+// Parse decodes and validates a structured-mode JSON event.
+func Parse(raw []byte) (Event, error) {
+	var e Event
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return Event{}, fmt.Errorf("cloudevents: %w", err)
+	}
+	return e, e.Validate()
+}
+
+// Binary-mode HTTP header names.
+const (
+	HeaderSpecVersion = "ce-specversion"
+	HeaderID          = "ce-id"
+	HeaderSource      = "ce-source"
+	HeaderType        = "ce-type"
+	HeaderSubject     = "ce-subject"
+	HeaderTime        = "ce-time"
+	HeaderDataSchema  = "ce-dataschema"
+	HeaderSequence    = "ce-sequence"
+	// The distributed tracing extension uses the W3C headers themselves.
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+)
+
+// BinaryHeaders maps the attributes to HTTP binary-mode headers. The data
+// goes in the body as-is, described by Content-Type.
+func (e Event) BinaryHeaders() http.Header {
+	h := http.Header{}
+	h.Set(HeaderSpecVersion, e.SpecVersion)
+	h.Set(HeaderID, e.ID)
+	h.Set(HeaderSource, e.Source)
+	h.Set(HeaderType, e.Type)
+	if e.Subject != "" {
+		h.Set(HeaderSubject, e.Subject)
+	}
+	if !e.Time.IsZero() {
+		h.Set(HeaderTime, e.Time.Format(time.RFC3339Nano))
+	}
+	if e.DataSchema != "" {
+		h.Set(HeaderDataSchema, e.DataSchema)
+	}
+	if e.Sequence != "" {
+		h.Set(HeaderSequence, e.Sequence)
+	}
+	if e.TraceParent != "" {
+		h.Set(HeaderTraceParent, e.TraceParent)
+	}
+	if e.TraceState != "" {
+		h.Set(HeaderTraceState, e.TraceState)
+	}
+	if e.DataContentType != "" {
+		h.Set("Content-Type", e.DataContentType)
+	}
+	return h
+}
+
+// FromBinary rebuilds an event from a binary-mode HTTP message.
+func FromBinary(h http.Header, body []byte) (Event, error) {
+	e := Event{
+		SpecVersion:     h.Get(HeaderSpecVersion),
+		ID:              h.Get(HeaderID),
+		Source:          h.Get(HeaderSource),
+		Type:            h.Get(HeaderType),
+		Subject:         h.Get(HeaderSubject),
+		DataContentType: h.Get("Content-Type"),
+		DataSchema:      h.Get(HeaderDataSchema),
+		Sequence:        h.Get(HeaderSequence),
+		TraceParent:     h.Get(HeaderTraceParent),
+		TraceState:      h.Get(HeaderTraceState),
+	}
+	if ts := h.Get(HeaderTime); ts != "" {
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return Event{}, fmt.Errorf("cloudevents: bad %s: %w", HeaderTime, err)
+		}
+		e.Time = t
+	}
+	if len(body) > 0 {
+		e.Data = json.RawMessage(body)
+	}
+	return e, e.Validate()
+}
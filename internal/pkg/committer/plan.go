@@ -3,14 +3,22 @@ package committer
 import (
 	"context"
 
-	"cloud.google.com/go/spanner"
-
 	"github.com/tshubham2/commitplan"
-	spannerdriver "github.com/tshubham2/commitplan/drivers/spanner"
 )
 
-// Plan is a typed wrapper that only accepts *spanner.Mutation, so callers
-// don't have to deal with interface{} from the generic commitplan.Plan.
+// Mutation is a storage-neutral row write. Repositories build them; the
+// backend's driver translates and applies them.
+type Mutation = commitplan.Mutation
+
+// Precondition is a check the plan is conditional on, e.g. a *VersionCheck.
+type Precondition = commitplan.Precondition
+
+// VersionCheck is the optimistic-concurrency precondition every backend
+// understands.
+type VersionCheck = commitplan.VersionCheck
+
+// Plan collects the mutations of one business operation, to be applied
+// atomically by a Committer.
 type Plan struct {
 	inner *commitplan.Plan
 }
@@ -19,24 +27,74 @@ func NewPlan() *Plan {
 	return &Plan{inner: commitplan.NewPlan()}
 }
 
-func (p *Plan) Add(m *spanner.Mutation) {
+// Add appends a mutation. A nil mutation, which repositories return when
+// there is nothing to write, is skipped.
+func (p *Plan) Add(m *Mutation) {
 	if m != nil {
 		p.inner.Add(m)
 	}
 }
 
+// Require makes the whole plan conditional on the precondition.
+func (p *Plan) Require(c Precondition) {
+	if c != nil {
+		p.inner.Require(c)
+	}
+}
+
 func (p *Plan) IsEmpty() bool {
 	return p.inner.IsEmpty()
 }
 
-type Committer struct {
-	driver *spannerdriver.Committer
+func (p *Plan) Mutations() []*Mutation        { return p.inner.Mutations() }
+func (p *Plan) Preconditions() []Precondition { return p.inner.Preconditions() }
+
+// Committer applies plans to one storage backend. Usecases depend on this
+// interface only; the backend is picked when the services are wired.
+type Committer interface {
+	// Apply writes the plan atomically, after checking its preconditions.
+	Apply(ctx context.Context, plan *Plan) error
+
+	// RunInTransaction runs fn inside a read-write transaction and commits
+	// the plan it returns in that same transaction. Repositories handed the
+	// ctx that fn receives read through the transaction, so business rules
+	// checked against loaded state still hold at commit time.
+	//
+	// fn may be re-run from scratch when the backend aborts the
+	// transaction, so it must not do anything besides reading and building
+	// the plan.
+	RunInTransaction(ctx context.Context, fn func(ctx context.Context) (*Plan, error)) error
+}
+
+// New adapts a commitplan driver to Committer.
+func New(driver commitplan.Driver) Committer {
+	return &driverCommitter{driver: driver}
+}
+
+// Open builds a Committer on the named commitplan driver; see
+// commitplan.Open for what conn must be.
+func Open(name string, conn interface{}) (Committer, error) {
+	driver, err := commitplan.Open(name, conn)
+	if err != nil {
+		return nil, err
+	}
+	return New(driver), nil
 }
 
-func NewCommitter(client *spanner.Client) *Committer {
-	return &Committer{driver: spannerdriver.NewCommitter(client)}
+type driverCommitter struct {
+	driver commitplan.Driver
 }
 
-func (c *Committer) Apply(ctx context.Context, plan *Plan) error {
+func (c *driverCommitter) Apply(ctx context.Context, plan *Plan) error {
 	return c.driver.Apply(ctx, plan.inner)
 }
+
+func (c *driverCommitter) RunInTransaction(ctx context.Context, fn func(ctx context.Context) (*Plan, error)) error {
+	return c.driver.RunInTransaction(ctx, func(ctx context.Context) (*commitplan.Plan, error) {
+		plan, err := fn(ctx)
+		if err != nil || plan == nil {
+			return nil, err
+		}
+		return plan.inner, nil
+	})
+}
@@ -0,0 +1,30 @@
+package committer
+
+import (
+	"context"
+
+	"cloud.google.com/go/spanner"
+
+	spannerdriver "github.com/tshubham2/commitplan/drivers/spanner"
+)
+
+// NewSpannerCommitter applies plans through the commitplan Spanner driver.
+func NewSpannerCommitter(client *spanner.Client) Committer {
+	return New(spannerdriver.NewCommitter(client))
+}
+
+// ReadTxn is the read surface shared by Spanner's read-only and read-write
+// transactions.
+type ReadTxn interface {
+	ReadRow(ctx context.Context, table string, key spanner.Key, columns []string) (*spanner.Row, error)
+	Query(ctx context.Context, statement spanner.Statement) *spanner.RowIterator
+}
+
+// Reader returns the read-write transaction bound to ctx by RunInTransaction,
+// or a single-use read-only transaction when ctx carries none.
+func Reader(ctx context.Context, client *spanner.Client) ReadTxn {
+	if txn, ok := spannerdriver.Transaction(ctx); ok {
+		return txn
+	}
+	return client.Single()
+}
@@ -0,0 +1,37 @@
+package committer
+
+import (
+	"context"
+	"database/sql"
+
+	postgresdriver "github.com/tshubham2/commitplan/drivers/postgres"
+	"github.com/tshubham2/commitplan/drivers/sqldb"
+	sqlitedriver "github.com/tshubham2/commitplan/drivers/sqlite"
+)
+
+// NewPostgresCommitter applies plans through the commitplan PostgreSQL
+// driver. db can come from any PostgreSQL database/sql driver.
+func NewPostgresCommitter(db *sql.DB) Committer {
+	return New(postgresdriver.NewCommitter(db))
+}
+
+// NewSQLiteCommitter applies plans through the commitplan SQLite driver. db
+// must be opened with sqlitedriver.DSN.
+func NewSQLiteCommitter(db *sql.DB) Committer {
+	return New(sqlitedriver.NewCommitter(db))
+}
+
+// SQLReadTxn is the read surface shared by *sql.DB and *sql.Tx.
+type SQLReadTxn interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// SQLReader returns the transaction bound to ctx by RunInTransaction, or db
+// itself when ctx carries none.
+func SQLReader(ctx context.Context, db *sql.DB) SQLReadTxn {
+	if tx, ok := sqldb.Transaction(ctx); ok {
+		return tx
+	}
+	return db
+}
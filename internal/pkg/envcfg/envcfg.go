@@ -0,0 +1,51 @@
+// Package envcfg reads process configuration from environment variables.
+// Invalid values are fatal: a binary should not start half-configured.
+package envcfg
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+func String(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func Duration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+	return d
+}
+
+func Int(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+	return n
+}
+
+// SpannerDatabasePath builds the database name from SPANNER_PROJECT,
+// SPANNER_INSTANCE and SPANNER_DATABASE, defaulting to the emulator setup.
+func SpannerDatabasePath() string {
+	project := String("SPANNER_PROJECT", "test-project")
+	instance := String("SPANNER_INSTANCE", "test-instance")
+	database := String("SPANNER_DATABASE", "test-database")
+	return fmt.Sprintf("projects/%s/instances/%s/databases/%s", project, instance, database)
+}
@@ -0,0 +1,57 @@
+// Package eventmeta carries who and what caused a write, from the transport
+// layer through the usecases to the outbox events the write produces.
+package eventmeta
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// Metadata is stored with every outbox event. All fields are optional.
+type Metadata struct {
+	// CorrelationID is shared by everything done on behalf of one original
+	// request, across services.
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// CausationID is the ID of the request or event that directly caused
+	// this one.
+	CausationID string `json:"causation_id,omitempty"`
+	// Actor is the person or service account the caller acted for.
+	Actor string `json:"actor,omitempty"`
+	// Client names the tool or service that sent the request.
+	Client string `json:"client,omitempty"`
+	// TraceParent and TraceState are the W3C trace context of the request.
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
+}
+
+func (m Metadata) IsZero() bool { return m == Metadata{} }
+
+type ctxKey struct{}
+
+// NewContext returns ctx carrying m.
+func NewContext(ctx context.Context, m Metadata) context.Context {
+	return context.WithValue(ctx, ctxKey{}, m)
+}
+
+// FromContext returns the metadata NewContext stored in ctx, or the zero
+// value for writes that didn't come through the transport layer.
+func FromContext(ctx context.Context) Metadata {
+	m, _ := ctx.Value(ctxKey{}).(Metadata)
+	return m
+}
+
+var traceParentRE = regexp.MustCompile(`^([0-9a-f]{2})-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}$`)
+
+// ValidTraceParent reports whether s is a W3C traceparent header value
+// (https://www.w3.org/TR/trace-context/#traceparent-header) that a
+// receiver should honour.
+func ValidTraceParent(s string) bool {
+	m := traceParentRE.FindStringSubmatch(s)
+	if m == nil {
+		return false
+	}
+	return m[1] != "ff" &&
+		m[2] != strings.Repeat("0", 32) &&
+		m[3] != strings.Repeat("0", 16)
+}
@@ -0,0 +1,282 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/cached"
+	"github.com/tshubham2/catalog-proj/internal/outbox"
+	"github.com/tshubham2/catalog-proj/internal/services"
+	pb "github.com/tshubham2/catalog-proj/proto/product/v1"
+)
+
+func memoryContainer(t *testing.T) *services.Container {
+	t.Helper()
+	c, err := services.NewContainer(nil, services.Options{
+		Backend:         services.BackendMemory,
+		PublisherConfig: outbox.PublisherConfig{Kind: outbox.PublisherBus},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestMemoryBackend_WriteReadAndRelay(t *testing.T) {
+	c := memoryContainer(t)
+	ctx := context.Background()
+
+	var delivered []string
+	c.Bus.Subscribe("", func(_ context.Context, e contracts.OutboxEvent) error {
+		delivered = append(delivered, e.EventType)
+		return nil
+	})
+
+	created, err := c.Handler.CreateProduct(ctx, &pb.CreateProductRequest{
+		Name: "Widget", Category: "tools", BasePrice: "19.99",
+	})
+	require.NoError(t, err)
+	id := created.GetProductId()
+
+	now := time.Now()
+	_, err = c.Handler.ApplyDiscount(ctx, &pb.ApplyDiscountRequest{
+		ProductId:  id,
+		Percentage: "25",
+		StartDate:  timestamppb.New(now.Add(-time.Hour)),
+		EndDate:    timestamppb.New(now.Add(time.Hour)),
+	})
+	require.NoError(t, err)
+
+	got, err := c.Handler.GetProduct(ctx, &pb.GetProductRequest{ProductId: id})
+	require.NoError(t, err)
+	assert.Equal(t, "Widget", got.GetProduct().GetName())
+	assert.Equal(t, "19.99", got.GetProduct().GetBasePrice())
+	assert.Equal(t, "14.9925", got.GetProduct().GetEffectivePrice())
+	assert.Equal(t, int64(2), got.GetProduct().GetVersion())
+
+	n, err := c.Relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "the discount waits for the created event")
+	n, err = c.Relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"product.created", "discount.applied"}, delivered)
+}
+
+func TestMemoryBackend_RetentionPurgesDeliveredEvents(t *testing.T) {
+	c, err := services.NewContainer(nil, services.Options{
+		Backend:         services.BackendMemory,
+		PublisherConfig: outbox.PublisherConfig{Kind: outbox.PublisherBus},
+		Retention:       outbox.RetentionConfig{ProcessedTTL: time.Nanosecond, PurgeHistory: true},
+	})
+	require.NoError(t, err)
+	defer c.Close()
+	ctx := context.Background()
+
+	for _, name := range []string{"A", "B"} {
+		_, err := c.Handler.CreateProduct(ctx, &pb.CreateProductRequest{
+			Name: name, Category: "tools", BasePrice: "1.00",
+		})
+		require.NoError(t, err)
+	}
+	n, err := c.Relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	time.Sleep(time.Millisecond)
+
+	stats, err := c.Retention.PurgeOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.Processed)
+
+	stats, err = c.Retention.PurgeOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, stats.Processed)
+}
+
+func TestMemoryBackend_RejectsStaleVersion(t *testing.T) {
+	c := memoryContainer(t)
+	ctx := context.Background()
+
+	created, err := c.Handler.CreateProduct(ctx, &pb.CreateProductRequest{
+		Name: "Widget", Category: "tools", BasePrice: "10",
+	})
+	require.NoError(t, err)
+	id := created.GetProductId()
+
+	name, stale := "First", int64(1)
+	_, err = c.Handler.UpdateProduct(ctx, &pb.UpdateProductRequest{ProductId: id, Name: &name, ExpectedVersion: &stale})
+	require.NoError(t, err)
+
+	name = "Second"
+	_, err = c.Handler.UpdateProduct(ctx, &pb.UpdateProductRequest{ProductId: id, Name: &name, ExpectedVersion: &stale})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	got, err := c.Handler.GetProduct(ctx, &pb.GetProductRequest{ProductId: id})
+	require.NoError(t, err)
+	assert.Equal(t, "First", got.GetProduct().GetName())
+}
+
+func TestMemoryBackend_ListPagination(t *testing.T) {
+	c := memoryContainer(t)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		_, err := c.Handler.CreateProduct(ctx, &pb.CreateProductRequest{
+			Name: fmt.Sprintf("Item %d", i), Category: "paged", BasePrice: "1",
+		})
+		require.NoError(t, err)
+	}
+
+	seen := map[string]bool{}
+	token := ""
+	for page := 0; page < 5; page++ {
+		reply, err := c.Handler.ListProducts(ctx, &pb.ListProductsRequest{PageSize: 2, PageToken: token, Category: "paged"})
+		require.NoError(t, err)
+		for _, p := range reply.GetProducts() {
+			seen[p.GetId()] = true
+		}
+		if token = reply.GetNextPageToken(); token == "" {
+			break
+		}
+	}
+	assert.Len(t, seen, 5, "no product is skipped between pages")
+}
+
+func TestMemoryBackend_ReadsFromProjectedViews(t *testing.T) {
+	c, err := services.NewContainer(nil, services.Options{
+		Backend:         services.BackendMemory,
+		ReadModel:       services.ReadModelViews,
+		PublisherConfig: outbox.PublisherConfig{Kind: outbox.PublisherBus},
+	})
+	require.NoError(t, err)
+	defer c.Close()
+	ctx := context.Background()
+
+	created, err := c.Handler.CreateProduct(ctx, &pb.CreateProductRequest{
+		Name: "Widget", Category: "tools", BasePrice: "19.99",
+	})
+	require.NoError(t, err)
+	id := created.GetProductId()
+	now := time.Now()
+	_, err = c.Handler.ApplyDiscount(ctx, &pb.ApplyDiscountRequest{
+		ProductId:  id,
+		Percentage: "25",
+		StartDate:  timestamppb.New(now.Add(-time.Hour)),
+		EndDate:    timestamppb.New(now.Add(time.Hour)),
+	})
+	require.NoError(t, err)
+
+	_, err = c.Handler.GetProduct(ctx, &pb.GetProductRequest{ProductId: id})
+	assert.Equal(t, codes.NotFound, status.Code(err), "nothing is projected before the relay runs")
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Relay.RelayOnce(ctx)
+		require.NoError(t, err)
+	}
+	got, err := c.Handler.GetProduct(ctx, &pb.GetProductRequest{ProductId: id})
+	require.NoError(t, err)
+	assert.Equal(t, "14.9925", got.GetProduct().GetEffectivePrice())
+	assert.Equal(t, int64(2), got.GetProduct().GetVersion())
+
+	list, err := c.Handler.ListProducts(ctx, &pb.ListProductsRequest{Category: "tools"})
+	require.NoError(t, err)
+	require.Len(t, list.GetProducts(), 1)
+	assert.Equal(t, id, list.GetProducts()[0].GetId())
+
+	report, err := c.Views.Rebuild(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.Version)
+	assert.False(t, report.FromRow)
+}
+
+func TestMemoryBackend_WritesInvalidateCache(t *testing.T) {
+	c, err := services.NewContainer(nil, services.Options{
+		Backend:         services.BackendMemory,
+		Cache:           &cached.Config{TTL: time.Hour},
+		PublisherConfig: outbox.PublisherConfig{Kind: outbox.PublisherBus},
+	})
+	require.NoError(t, err)
+	defer c.Close()
+	ctx := context.Background()
+
+	created, err := c.Handler.CreateProduct(ctx, &pb.CreateProductRequest{
+		Name: "Widget", Category: "tools", BasePrice: "19.99",
+	})
+	require.NoError(t, err)
+	id := created.GetProductId()
+	_, err = c.Handler.GetProduct(ctx, &pb.GetProductRequest{ProductId: id})
+	require.NoError(t, err)
+
+	name := "Gadget"
+	_, err = c.Handler.UpdateProduct(ctx, &pb.UpdateProductRequest{ProductId: id, Name: &name})
+	require.NoError(t, err)
+	got, err := c.Handler.GetProduct(ctx, &pb.GetProductRequest{ProductId: id})
+	require.NoError(t, err)
+	assert.Equal(t, "Gadget", got.GetProduct().GetName(), "the write dropped its product from the cache")
+	assert.Equal(t, int64(2), got.GetProduct().GetVersion())
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Relay.RelayOnce(ctx)
+		require.NoError(t, err)
+	}
+	got, err = c.Handler.GetProduct(ctx, &pb.GetProductRequest{ProductId: id})
+	require.NoError(t, err)
+	assert.Equal(t, "Gadget", got.GetProduct().GetName())
+}
+
+// product_views only changes when the relay projects an event, so that is
+// when the cache drops the product too.
+func TestMemoryBackend_RelayInvalidatesCachedViews(t *testing.T) {
+	c, err := services.NewContainer(nil, services.Options{
+		Backend:         services.BackendMemory,
+		ReadModel:       services.ReadModelViews,
+		Cache:           &cached.Config{TTL: time.Hour},
+		PublisherConfig: outbox.PublisherConfig{Kind: outbox.PublisherBus},
+	})
+	require.NoError(t, err)
+	defer c.Close()
+	ctx := context.Background()
+
+	created, err := c.Handler.CreateProduct(ctx, &pb.CreateProductRequest{
+		Name: "Widget", Category: "tools", BasePrice: "19.99",
+	})
+	require.NoError(t, err)
+	id := created.GetProductId()
+	_, err = c.Relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	_, err = c.Handler.GetProduct(ctx, &pb.GetProductRequest{ProductId: id})
+	require.NoError(t, err)
+
+	name := "Gadget"
+	_, err = c.Handler.UpdateProduct(ctx, &pb.UpdateProductRequest{ProductId: id, Name: &name})
+	require.NoError(t, err)
+	got, err := c.Handler.GetProduct(ctx, &pb.GetProductRequest{ProductId: id})
+	require.NoError(t, err)
+	assert.Equal(t, "Widget", got.GetProduct().GetName(), "the view is stale until the event is projected")
+
+	_, err = c.Relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	got, err = c.Handler.GetProduct(ctx, &pb.GetProductRequest{ProductId: id})
+	require.NoError(t, err)
+	assert.Equal(t, "Gadget", got.GetProduct().GetName())
+	assert.Equal(t, int64(2), got.GetProduct().GetVersion())
+}
+
+func TestNewContainer_Backends(t *testing.T) {
+	_, err := services.NewContainer(nil, services.Options{})
+	assert.Error(t, err, "spanner needs a client")
+
+	_, err = services.NewContainer(nil, services.Options{Backend: "dynamo"})
+	assert.Error(t, err)
+
+	_, err = services.NewContainer(nil, services.Options{Backend: services.BackendMemory, ReadModel: "cache"})
+	assert.Error(t, err)
+}
@@ -1,48 +1,279 @@
 package services
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
 	"cloud.google.com/go/spanner"
 
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
 	"github.com/tshubham2/catalog-proj/internal/app/product/queries/get_product"
 	"github.com/tshubham2/catalog-proj/internal/app/product/queries/list_products"
 	"github.com/tshubham2/catalog-proj/internal/app/product/repo"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/cached"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/memory"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/postgres"
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/sqlite"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases"
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/activate_product"
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/apply_discount"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/change_price"
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/create_product"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/project_views"
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/update_product"
+	"github.com/tshubham2/catalog-proj/internal/outbox"
 	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
 	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/catalog-proj/internal/transport/grpc/outboxadmin"
 	transport "github.com/tshubham2/catalog-proj/internal/transport/grpc/product"
 )
 
+// Storage backends accepted by Options.Backend.
+const (
+	BackendSpanner = "spanner"
+	// BackendPostgres needs Options.Postgres, migrated with
+	// migrations/postgres.
+	BackendPostgres = "postgres"
+	// BackendSQLite needs Options.SQLite, opened with the commitplan sqlite
+	// driver's DSN and migrated with migrations/sqlite.
+	BackendSQLite = "sqlite"
+	// BackendMemory keeps everything in process memory and needs no
+	// database; data is lost on exit.
+	BackendMemory = "memory"
+)
+
+// Read models accepted by Options.ReadModel.
+const (
+	// ReadModelProducts serves queries from the products table, so a read
+	// sees every committed write.
+	ReadModelProducts = "products"
+	// ReadModelViews serves queries from product_views, which the relay
+	// projects each event into before publishing it. Reads lag writes by
+	// the relay's delay; run cmd/rebuild-views once before switching.
+	ReadModelViews = "product_views"
+)
+
+// Options tweaks the wiring done by NewContainer. The zero value is usable.
+type Options struct {
+	// Backend is one of the Backend* constants; empty means BackendSpanner.
+	Backend string
+	// Postgres is the database BackendPostgres uses.
+	Postgres *sql.DB
+	// SQLite is the database BackendSQLite uses.
+	SQLite *sql.DB
+	// ReadModel is one of the ReadModel* constants; empty means
+	// ReadModelProducts.
+	ReadModel string
+	// Cache, when set, keeps recently read products in memory in front of
+	// the read model. The relay invalidates a product as it relays its
+	// events; with ReadModelProducts a write also does once it commits.
+	// Zero fields fall back to cached.DefaultConfig.
+	Cache *cached.Config
+	// Publisher receives relayed outbox events. When nil, one is built from
+	// PublisherConfig.
+	Publisher outbox.Publisher
+	// PublisherConfig selects the built-in sink; the zero value logs events.
+	PublisherConfig outbox.PublisherConfig
+	// Relay configures the outbox relay. Zero fields fall back to
+	// outbox.DefaultConfig.
+	Relay outbox.Config
+	// Retention configures the outbox retention job. Zero fields fall back
+	// to outbox.DefaultRetentionConfig.
+	Retention outbox.RetentionConfig
+}
+
 type Container struct {
-	Handler *transport.Handler
+	Handler     *transport.Handler
+	OutboxAdmin *outboxadmin.Handler
+	Relay       *outbox.Relay
+	Retention   *outbox.Retention
+	// Bus is set when events are published in-process; subscribe to it
+	// before starting the relay.
+	Bus *outbox.Bus
+	// Views maintains product_views. The relay runs its Project on every
+	// event when Options.ReadModel is ReadModelViews.
+	Views *project_views.Interactor
+	// Cache is the read cache, set when Options.Cache is.
+	Cache *cached.ReadModel
+
+	publisher outbox.Publisher
 }
 
-func NewContainer(spannerClient *spanner.Client) *Container {
-	clk := clock.RealClock{}
-	cm := committer.NewCommitter(spannerClient)
+// Close releases what the container opened, such as the file sink.
+func (c *Container) Close() error {
+	if closer, ok := c.publisher.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
 
-	productRepo := repo.NewProductRepo(spannerClient)
-	outboxRepo := repo.NewOutboxRepo()
-	readModel := repo.NewProductReadModel(spannerClient)
+// outboxStore is everything the outbox repositories of a backend provide.
+type outboxStore interface {
+	contracts.OutboxRepository
+	contracts.OutboxStore
+	contracts.DeadLetterStore
+	contracts.OutboxRetentionStore
+	repo.AggregateEvents
+}
 
-	createUC := create_product.NewInteractor(productRepo, outboxRepo, cm, clk)
-	updateUC := update_product.NewInteractor(productRepo, outboxRepo, cm, clk)
-	applyUC := apply_discount.NewApplyInteractor(productRepo, outboxRepo, cm, clk)
-	removeUC := apply_discount.NewRemoveInteractor(productRepo, outboxRepo, cm, clk)
-	activateUC := activate_product.NewActivateInteractor(productRepo, outboxRepo, cm, clk)
-	deactivateUC := activate_product.NewDeactivateInteractor(productRepo, outboxRepo, cm, clk)
-	archiveUC := activate_product.NewArchiveInteractor(productRepo, outboxRepo, cm, clk)
+// viewStore is a backend's product_views repository.
+type viewStore interface {
+	contracts.ProductReadModel
+	contracts.ProductViewStore
+}
+
+// storage is one backend's committer and repositories.
+type storage struct {
+	committer    committer.Committer
+	products     contracts.ProductRepository
+	priceHistory contracts.PriceHistoryRepository
+	readModel    contracts.ProductReadModel
+	views        viewStore
+	outbox       outboxStore
+}
+
+func newStorage(spannerClient *spanner.Client, opts Options) (*storage, error) {
+	switch opts.Backend {
+	case "", BackendSpanner:
+		if spannerClient == nil {
+			return nil, errors.New("spanner backend needs a spanner client")
+		}
+		return &storage{
+			committer:    committer.NewSpannerCommitter(spannerClient),
+			products:     repo.NewProductRepo(spannerClient),
+			priceHistory: repo.NewPriceHistoryRepo(),
+			readModel:    repo.NewProductReadModel(spannerClient),
+			views:        repo.NewProductViewRepo(spannerClient),
+			outbox:       repo.NewOutboxRepo(spannerClient),
+		}, nil
+	case BackendPostgres:
+		if opts.Postgres == nil {
+			return nil, errors.New("postgres backend needs Options.Postgres")
+		}
+		db := opts.Postgres
+		return &storage{
+			committer:    committer.NewPostgresCommitter(db),
+			products:     postgres.NewProductRepo(db),
+			priceHistory: postgres.NewPriceHistoryRepo(),
+			readModel:    postgres.NewProductReadModel(db),
+			views:        postgres.NewProductViewRepo(db),
+			outbox:       postgres.NewOutboxRepo(db),
+		}, nil
+	case BackendSQLite:
+		if opts.SQLite == nil {
+			return nil, errors.New("sqlite backend needs Options.SQLite")
+		}
+		db := opts.SQLite
+		return &storage{
+			committer:    committer.NewSQLiteCommitter(db),
+			products:     sqlite.NewProductRepo(db),
+			priceHistory: sqlite.NewPriceHistoryRepo(),
+			readModel:    sqlite.NewProductReadModel(db),
+			views:        sqlite.NewProductViewRepo(db),
+			outbox:       sqlite.NewOutboxRepo(db),
+		}, nil
+	case BackendMemory:
+		store := memory.NewStore()
+		return &storage{
+			committer:    memory.NewCommitter(store),
+			products:     memory.NewProductRepo(store),
+			priceHistory: memory.NewPriceHistoryRepo(),
+			readModel:    memory.NewProductReadModel(store),
+			views:        memory.NewProductViewRepo(store),
+			outbox:       memory.NewOutboxRepo(store),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", opts.Backend)
+	}
+}
+
+// NewContainer wires the services onto the backend opts selects.
+// spannerClient is only used by BackendSpanner and may be nil otherwise.
+func NewContainer(spannerClient *spanner.Client, opts Options) (*Container, error) {
+	st, err := newStorage(spannerClient, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	clk := clock.RealClock{}
+	cm := st.committer
+	productRepo := st.products
+	outboxRepo := st.outbox
+	priceHistoryRepo := st.priceHistory
+	readModel := st.readModel
+	switch opts.ReadModel {
+	case "", ReadModelProducts:
+	case ReadModelViews:
+		readModel = st.views
+	default:
+		return nil, fmt.Errorf("unknown read model %q", opts.ReadModel)
+	}
+	var cache *cached.ReadModel
+	if opts.Cache != nil {
+		cache = cached.New(readModel, clk, *opts.Cache)
+		readModel = cache
+	}
+	// Over products, writes drop what they commit from this replica's
+	// cache, so it reads its own writes; the relay below covers writes made
+	// on other replicas. Over product_views that would only re-cache the
+	// view the write hasn't reached yet, so the relay alone invalidates,
+	// once it has projected the event.
+	writeCm := cm
+	if cache != nil && opts.ReadModel != ReadModelViews {
+		writeCm = cached.NewCommitter(cm, cache)
+	}
+
+	createUC := create_product.NewInteractor(productRepo, outboxRepo, writeCm, clk)
+	updateUC := update_product.NewInteractor(productRepo, outboxRepo, writeCm, clk)
+	applyUC := apply_discount.NewApplyInteractor(productRepo, outboxRepo, writeCm, clk)
+	removeUC := apply_discount.NewRemoveInteractor(productRepo, outboxRepo, writeCm, clk)
+	activateUC := activate_product.NewActivateInteractor(productRepo, outboxRepo, writeCm, clk)
+	deactivateUC := activate_product.NewDeactivateInteractor(productRepo, outboxRepo, writeCm, clk)
+	archiveUC := activate_product.NewArchiveInteractor(productRepo, outboxRepo, writeCm, clk)
+	changePriceUC := change_price.NewInteractor(productRepo, priceHistoryRepo, outboxRepo, writeCm, clk)
 
 	getQ := get_product.NewHandler(readModel, clk)
 	listQ := list_products.NewHandler(readModel, clk)
 
 	handler := transport.NewHandler(
 		createUC, updateUC, applyUC, removeUC,
-		activateUC, deactivateUC, archiveUC,
+		activateUC, deactivateUC, archiveUC, changePriceUC,
 		getQ, listQ,
 	)
 
-	return &Container{Handler: handler}
+	publisher := opts.Publisher
+	if publisher == nil {
+		if publisher, err = outbox.NewPublisher(opts.PublisherConfig); err != nil {
+			return nil, err
+		}
+	}
+	bus, _ := publisher.(*outbox.Bus)
+
+	views := project_views.NewInteractor(
+		st.views, repo.NewEventStoreOn(outboxRepo, usecases.DecodeEvent), productRepo,
+		usecases.DecodeEvent, cm, clk,
+	)
+	// The cache is invalidated after the view is projected, so a miss
+	// right after it reads the new view.
+	relayed := publisher
+	if cache != nil {
+		relayed = outbox.Before(cache.Invalidate, relayed)
+	}
+	if opts.ReadModel == ReadModelViews {
+		relayed = outbox.Before(views.Project, relayed)
+	}
+	relay := outbox.NewRelay(outboxRepo, relayed, cm, clk, opts.Relay)
+
+	return &Container{
+		Handler:     handler,
+		OutboxAdmin: outboxadmin.NewHandler(outbox.NewAdmin(outboxRepo, cm)),
+		Relay:       relay,
+		Retention:   outbox.NewRetention(outboxRepo, clk, opts.Retention),
+		Bus:         bus,
+		Views:       views,
+		Cache:       cache,
+		publisher:   publisher,
+	}, nil
 }
@@ -0,0 +1,75 @@
+package outboxadmin
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/outbox"
+	pb "github.com/tshubham2/catalog-proj/proto/product/v1"
+)
+
+type Handler struct {
+	pb.UnimplementedOutboxAdminServiceServer
+
+	admin *outbox.Admin
+}
+
+func NewHandler(admin *outbox.Admin) *Handler {
+	return &Handler{admin: admin}
+}
+
+func (h *Handler) ListDeadLetters(ctx context.Context, req *pb.ListDeadLettersRequest) (*pb.ListDeadLettersReply, error) {
+	events, nextToken, err := h.admin.ListDeadLetters(ctx, int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	reply := &pb.ListDeadLettersReply{
+		NextPageToken: nextToken,
+		Events:        make([]*pb.DeadLetter, 0, len(events)),
+	}
+	for _, e := range events {
+		reply.Events = append(reply.Events, deadLetterToProto(e))
+	}
+	return reply, nil
+}
+
+func (h *Handler) RequeueDeadLetters(ctx context.Context, req *pb.RequeueDeadLettersRequest) (*pb.RequeueDeadLettersReply, error) {
+	n, err := h.admin.Requeue(ctx, req.GetEventId(), req.GetAggregateId())
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &pb.RequeueDeadLettersReply{Requeued: int32(n)}, nil
+}
+
+func deadLetterToProto(e contracts.OutboxEvent) *pb.DeadLetter {
+	dl := &pb.DeadLetter{
+		EventId:     e.ID,
+		EventType:   e.EventType,
+		AggregateId: e.AggregateID,
+		Payload:     string(e.Payload),
+		Attempts:    e.Attempts,
+		LastError:   e.LastError,
+		CreatedAt:   timestamppb.New(e.CreatedAt),
+	}
+	if e.ProcessedAt != nil {
+		dl.DeadLetteredAt = timestamppb.New(*e.ProcessedAt)
+	}
+	return dl
+}
+
+func mapError(err error) error {
+	switch {
+	case errors.Is(err, outbox.ErrRequeueTarget):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, contracts.ErrOutboxEventNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
@@ -17,6 +17,7 @@ func mapDomainError(err error) error {
 	case errors.Is(err, domain.ErrProductNameRequired),
 		errors.Is(err, domain.ErrCategoryRequired),
 		errors.Is(err, domain.ErrInvalidPrice),
+		errors.Is(err, domain.ErrPriceNotRepresentable),
 		errors.Is(err, domain.ErrInvalidDiscountPercent),
 		errors.Is(err, domain.ErrInvalidDiscountPeriod):
 		return status.Error(codes.InvalidArgument, err.Error())
@@ -29,6 +30,12 @@ func mapDomainError(err error) error {
 		errors.Is(err, domain.ErrNoActiveDiscount):
 		return status.Error(codes.FailedPrecondition, err.Error())
 
+	case errors.Is(err, domain.ErrVersionConflict):
+		return status.Error(codes.Aborted, err.Error())
+
+	case errors.Is(err, domain.ErrCorruptProduct):
+		return status.Error(codes.DataLoss, err.Error())
+
 	default:
 		return status.Error(codes.Internal, "internal error")
 	}
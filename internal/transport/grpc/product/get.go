@@ -41,6 +41,7 @@ func (h *Handler) ApplyDiscount(ctx context.Context, req *pb.ApplyDiscountReques
 		req.GetProductId(), pct,
 		req.GetStartDate().AsTime(),
 		req.GetEndDate().AsTime(),
+		req.GetExpectedVersion(),
 	))
 	if err != nil {
 		return nil, mapDomainError(err)
@@ -54,7 +55,7 @@ func (h *Handler) RemoveDiscount(ctx context.Context, req *pb.RemoveDiscountRequ
 		return nil, status.Error(codes.InvalidArgument, "product_id is required")
 	}
 
-	if err := h.removeDiscount.Execute(ctx, remove_discount_request(req.GetProductId())); err != nil {
+	if err := h.removeDiscount.Execute(ctx, remove_discount_request(req.GetProductId(), req.GetExpectedVersion())); err != nil {
 		return nil, mapDomainError(err)
 	}
 
@@ -5,6 +5,7 @@ import (
 	"github.com/tshubham2/catalog-proj/internal/app/product/queries/list_products"
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/activate_product"
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/apply_discount"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/change_price"
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/create_product"
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/update_product"
 	pb "github.com/tshubham2/catalog-proj/proto/product/v1"
@@ -20,6 +21,7 @@ type Handler struct {
 	activate       *activate_product.ActivateInteractor
 	deactivate     *activate_product.DeactivateInteractor
 	archive        *activate_product.ArchiveInteractor
+	changePrice    *change_price.Interactor
 	getProduct     *get_product.Handler
 	listProducts   *list_products.Handler
 }
@@ -32,6 +34,7 @@ func NewHandler(
 	act *activate_product.ActivateInteractor,
 	deact *activate_product.DeactivateInteractor,
 	arch *activate_product.ArchiveInteractor,
+	chp *change_price.Interactor,
 	gp *get_product.Handler,
 	lp *list_products.Handler,
 ) *Handler {
@@ -43,6 +46,7 @@ func NewHandler(
 		activate:       act,
 		deactivate:     deact,
 		archive:        arch,
+		changePrice:    chp,
 		getProduct:     gp,
 		listProducts:   lp,
 	}
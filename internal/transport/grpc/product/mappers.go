@@ -44,6 +44,7 @@ func productDTOToProto(dto *get_product.ProductDTO) *pb.Product {
 		Status:         dto.Status,
 		CreatedAt:      timestamppb.New(dto.CreatedAt),
 		UpdatedAt:      timestamppb.New(dto.UpdatedAt),
+		Version:        dto.Version,
 	}
 	if dto.DiscountPercent != nil {
 		p.DiscountPercent = dto.DiscountPercent
@@ -63,19 +64,20 @@ func productSummaryToProto(s list_products.ProductSummary) *pb.ProductSummary {
 	}
 }
 
-func activate_product_request(productID string) activate_product.Request {
-	return activate_product.Request{ProductID: productID}
+func activate_product_request(productID string, expectedVersion int64) activate_product.Request {
+	return activate_product.Request{ProductID: productID, ExpectedVersion: expectedVersion}
 }
 
-func apply_discount_request(productID string, pct *big.Rat, start, end time.Time) apply_discount.ApplyRequest {
+func apply_discount_request(productID string, pct *big.Rat, start, end time.Time, expectedVersion int64) apply_discount.ApplyRequest {
 	return apply_discount.ApplyRequest{
-		ProductID:  productID,
-		Percentage: pct,
-		StartDate:  start,
-		EndDate:    end,
+		ProductID:       productID,
+		Percentage:      pct,
+		StartDate:       start,
+		EndDate:         end,
+		ExpectedVersion: expectedVersion,
 	}
 }
 
-func remove_discount_request(productID string) apply_discount.RemoveRequest {
-	return apply_discount.RemoveRequest{ProductID: productID}
+func remove_discount_request(productID string, expectedVersion int64) apply_discount.RemoveRequest {
+	return apply_discount.RemoveRequest{ProductID: productID, ExpectedVersion: expectedVersion}
 }
@@ -0,0 +1,36 @@
+package product
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/change_price"
+	pb "github.com/tshubham2/catalog-proj/proto/product/v1"
+)
+
+func (h *Handler) ChangeBasePrice(ctx context.Context, req *pb.ChangeBasePriceRequest) (*pb.ChangeBasePriceReply, error) {
+	if req.GetProductId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "product_id is required")
+	}
+	if req.GetNewPrice() == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_price is required")
+	}
+
+	price, err := parseMoneyString(req.GetNewPrice())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = h.changePrice.Execute(ctx, change_price.Request{
+		ProductID:       req.GetProductId(),
+		NewPrice:        price,
+		ExpectedVersion: req.GetExpectedVersion(),
+	})
+	if err != nil {
+		return nil, mapDomainError(err)
+	}
+
+	return &pb.ChangeBasePriceReply{}, nil
+}
@@ -16,10 +16,11 @@ func (h *Handler) UpdateProduct(ctx context.Context, req *pb.UpdateProductReques
 	}
 
 	err := h.updateProduct.Execute(ctx, update_product.Request{
-		ProductID:   req.GetProductId(),
-		Name:        req.Name,
-		Description: req.Description,
-		Category:    req.Category,
+		ProductID:       req.GetProductId(),
+		Name:            req.Name,
+		Description:     req.Description,
+		Category:        req.Category,
+		ExpectedVersion: req.GetExpectedVersion(),
 	})
 	if err != nil {
 		return nil, mapDomainError(err)
@@ -33,7 +34,7 @@ func (h *Handler) ActivateProduct(ctx context.Context, req *pb.ActivateProductRe
 		return nil, status.Error(codes.InvalidArgument, "product_id is required")
 	}
 
-	if err := h.activate.Execute(ctx, activate_product_request(req.GetProductId())); err != nil {
+	if err := h.activate.Execute(ctx, activate_product_request(req.GetProductId(), req.GetExpectedVersion())); err != nil {
 		return nil, mapDomainError(err)
 	}
 	return &pb.ActivateProductReply{}, nil
@@ -44,7 +45,7 @@ func (h *Handler) DeactivateProduct(ctx context.Context, req *pb.DeactivateProdu
 		return nil, status.Error(codes.InvalidArgument, "product_id is required")
 	}
 
-	if err := h.deactivate.Execute(ctx, activate_product_request(req.GetProductId())); err != nil {
+	if err := h.deactivate.Execute(ctx, activate_product_request(req.GetProductId(), req.GetExpectedVersion())); err != nil {
 		return nil, mapDomainError(err)
 	}
 	return &pb.DeactivateProductReply{}, nil
@@ -55,7 +56,7 @@ func (h *Handler) ArchiveProduct(ctx context.Context, req *pb.ArchiveProductRequ
 		return nil, status.Error(codes.InvalidArgument, "product_id is required")
 	}
 
-	if err := h.archive.Execute(ctx, activate_product_request(req.GetProductId())); err != nil {
+	if err := h.archive.Execute(ctx, activate_product_request(req.GetProductId(), req.GetExpectedVersion())); err != nil {
 		return nil, mapDomainError(err)
 	}
 	return &pb.ArchiveProductReply{}, nil
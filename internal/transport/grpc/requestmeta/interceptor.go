@@ -0,0 +1,83 @@
+// Package requestmeta captures event metadata from incoming gRPC requests
+// and puts it in the context for the usecases (see eventmeta).
+package requestmeta
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/tshubham2/catalog-proj/internal/pkg/eventmeta"
+)
+
+// Incoming metadata keys. traceparent and tracestate are the W3C names.
+const (
+	KeyCorrelationID = "x-correlation-id"
+	KeyCausationID   = "x-causation-id"
+	KeyActor         = "x-actor"
+	KeyClient        = "x-client-name"
+	KeyTraceParent   = "traceparent"
+	KeyTraceState    = "tracestate"
+)
+
+// maxValueLen bounds what a caller can make us store per field.
+const maxValueLen = 256
+
+// UnaryServerInterceptor reads the keys above into eventmeta.Metadata. A
+// request without a correlation ID gets a fresh one, which is sent back in
+// the x-correlation-id response header so the caller can quote it. The
+// client name falls back to the user agent. A malformed traceparent is
+// dropped along with its tracestate, as the W3C spec requires.
+//
+// Actor and client are whatever the caller says: they tell well-behaved
+// tools apart, they don't authenticate anyone.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		meta := FromIncoming(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(KeyCorrelationID, meta.CorrelationID))
+		return handler(eventmeta.NewContext(ctx, meta), req)
+	}
+}
+
+// FromIncoming builds the metadata for a request from its gRPC metadata.
+func FromIncoming(ctx context.Context) eventmeta.Metadata {
+	md, _ := metadata.FromIncomingContext(ctx)
+	get := func(key string) string {
+		vals := md.Get(key)
+		if len(vals) == 0 {
+			return ""
+		}
+		v := vals[0]
+		if len(v) > maxValueLen {
+			// Cut on a rune boundary: event metadata ends up in proto
+			// string fields, which must be valid UTF-8.
+			n := maxValueLen
+			for n > 0 && !utf8.RuneStart(v[n]) {
+				n--
+			}
+			v = v[:n]
+		}
+		return v
+	}
+
+	meta := eventmeta.Metadata{
+		CorrelationID: get(KeyCorrelationID),
+		CausationID:   get(KeyCausationID),
+		Actor:         get(KeyActor),
+		Client:        get(KeyClient),
+	}
+	if meta.CorrelationID == "" {
+		meta.CorrelationID = uuid.NewString()
+	}
+	if meta.Client == "" {
+		meta.Client = get("user-agent")
+	}
+	if tp := get(KeyTraceParent); eventmeta.ValidTraceParent(tp) {
+		meta.TraceParent = tp
+		meta.TraceState = get(KeyTraceState)
+	}
+	return meta
+}
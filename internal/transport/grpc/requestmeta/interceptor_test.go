@@ -0,0 +1,85 @@
+package requestmeta_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/tshubham2/catalog-proj/internal/pkg/eventmeta"
+	"github.com/tshubham2/catalog-proj/internal/transport/grpc/requestmeta"
+)
+
+const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestUnaryServerInterceptor_PutsMetadataInContext(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		requestmeta.KeyCorrelationID, "corr-1",
+		requestmeta.KeyCausationID, "cause-1",
+		requestmeta.KeyActor, "alice@example.com",
+		requestmeta.KeyClient, "pricing-tool",
+		requestmeta.KeyTraceParent, traceParent,
+		requestmeta.KeyTraceState, "vendor=abc",
+	))
+
+	var got eventmeta.Metadata
+	_, err := requestmeta.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, _ interface{}) (interface{}, error) {
+			got = eventmeta.FromContext(ctx)
+			return nil, nil
+		})
+	require.NoError(t, err)
+
+	assert.Equal(t, eventmeta.Metadata{
+		CorrelationID: "corr-1",
+		CausationID:   "cause-1",
+		Actor:         "alice@example.com",
+		Client:        "pricing-tool",
+		TraceParent:   traceParent,
+		TraceState:    "vendor=abc",
+	}, got)
+}
+
+func TestFromIncoming_Defaults(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"user-agent", "grpc-go/1.79",
+		requestmeta.KeyTraceParent, "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		requestmeta.KeyTraceState, "vendor=abc",
+	))
+
+	meta := requestmeta.FromIncoming(ctx)
+	assert.NotEmpty(t, meta.CorrelationID, "generated when absent")
+	assert.Equal(t, "grpc-go/1.79", meta.Client)
+	assert.Empty(t, meta.TraceParent, "all-zero trace ID is invalid")
+	assert.Empty(t, meta.TraceState, "dropped with its traceparent")
+
+	assert.NotEqual(t, meta.CorrelationID, requestmeta.FromIncoming(context.Background()).CorrelationID)
+}
+
+func TestFromIncoming_TruncatesOnRuneBoundary(t *testing.T) {
+	// 'é' is two bytes, so the 256-byte limit falls inside the last one.
+	actor := "x" + strings.Repeat("é", 200)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestmeta.KeyActor, actor))
+
+	meta := requestmeta.FromIncoming(ctx)
+	assert.True(t, utf8.ValidString(meta.Actor))
+	assert.Equal(t, actor[:255], meta.Actor)
+}
+
+func TestValidTraceParent(t *testing.T) {
+	assert.True(t, eventmeta.ValidTraceParent(traceParent))
+	for _, tp := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+	} {
+		assert.False(t, eventmeta.ValidTraceParent(tp), tp)
+	}
+}
@@ -0,0 +1,12 @@
+CREATE TABLE product_price_history (
+    product_id STRING(36) NOT NULL,
+    change_id STRING(36) NOT NULL,
+    old_price_numerator INT64 NOT NULL,
+    old_price_denominator INT64 NOT NULL,
+    new_price_numerator INT64 NOT NULL,
+    new_price_denominator INT64 NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+) PRIMARY KEY (product_id, change_id),
+  INTERLEAVE IN PARENT products ON DELETE CASCADE;
+
+CREATE INDEX idx_price_history_changed_at ON product_price_history(product_id, changed_at DESC);
@@ -0,0 +1,5 @@
+ALTER TABLE outbox_events ADD COLUMN attempts INT64 NOT NULL DEFAULT (0);
+
+ALTER TABLE outbox_events ADD COLUMN last_error STRING(MAX);
+
+ALTER TABLE outbox_events ADD COLUMN next_attempt_at TIMESTAMP;
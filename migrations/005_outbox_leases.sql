@@ -0,0 +1,3 @@
+ALTER TABLE outbox_events ADD COLUMN claimed_by STRING(64);
+
+ALTER TABLE outbox_events ADD COLUMN lease_expires_at TIMESTAMP;
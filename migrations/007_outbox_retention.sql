@@ -0,0 +1 @@
+CREATE INDEX idx_outbox_settled ON outbox_events(status, processed_at);
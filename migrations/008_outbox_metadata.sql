@@ -0,0 +1 @@
+ALTER TABLE outbox_events ADD COLUMN metadata JSON;
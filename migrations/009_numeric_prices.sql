@@ -0,0 +1,35 @@
+ALTER TABLE products ADD CONSTRAINT ck_products_price_representable CHECK (MOD(CAST(base_price_numerator AS NUMERIC) * 1000000000, CAST(base_price_denominator AS NUMERIC)) = 0);
+
+ALTER TABLE product_price_history ADD CONSTRAINT ck_price_history_price_representable CHECK (MOD(CAST(old_price_numerator AS NUMERIC) * 1000000000, CAST(old_price_denominator AS NUMERIC)) = 0 AND MOD(CAST(new_price_numerator AS NUMERIC) * 1000000000, CAST(new_price_denominator AS NUMERIC)) = 0);
+
+ALTER TABLE products ADD COLUMN base_price NUMERIC;
+
+UPDATE products SET base_price = CAST(base_price_numerator AS NUMERIC) / CAST(base_price_denominator AS NUMERIC) WHERE base_price IS NULL;
+
+ALTER TABLE products ALTER COLUMN base_price NUMERIC NOT NULL;
+
+ALTER TABLE products ALTER COLUMN base_price_numerator INT64;
+
+ALTER TABLE products ALTER COLUMN base_price_denominator INT64;
+
+ALTER TABLE product_price_history ADD COLUMN old_price NUMERIC;
+
+ALTER TABLE product_price_history ADD COLUMN new_price NUMERIC;
+
+UPDATE product_price_history SET old_price = CAST(old_price_numerator AS NUMERIC) / CAST(old_price_denominator AS NUMERIC), new_price = CAST(new_price_numerator AS NUMERIC) / CAST(new_price_denominator AS NUMERIC) WHERE old_price IS NULL;
+
+ALTER TABLE product_price_history ALTER COLUMN old_price NUMERIC NOT NULL;
+
+ALTER TABLE product_price_history ALTER COLUMN new_price NUMERIC NOT NULL;
+
+ALTER TABLE product_price_history ALTER COLUMN old_price_numerator INT64;
+
+ALTER TABLE product_price_history ALTER COLUMN old_price_denominator INT64;
+
+ALTER TABLE product_price_history ALTER COLUMN new_price_numerator INT64;
+
+ALTER TABLE product_price_history ALTER COLUMN new_price_denominator INT64;
+
+ALTER TABLE products DROP CONSTRAINT ck_products_price_representable;
+
+ALTER TABLE product_price_history DROP CONSTRAINT ck_price_history_price_representable;
@@ -0,0 +1,5 @@
+CREATE TABLE product_quarantine (
+    product_id STRING(36) NOT NULL,
+    violations STRING(MAX) NOT NULL,
+    found_at TIMESTAMP NOT NULL,
+) PRIMARY KEY (product_id);
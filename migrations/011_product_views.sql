@@ -0,0 +1,21 @@
+CREATE TABLE product_views (
+    product_id STRING(36) NOT NULL,
+    name STRING(255) NOT NULL,
+    description STRING(MAX),
+    category STRING(100) NOT NULL,
+    base_price NUMERIC NOT NULL,
+    discount_percent NUMERIC,
+    discount_start_date TIMESTAMP,
+    discount_end_date TIMESTAMP,
+    discounted_price NUMERIC,
+    status STRING(20) NOT NULL,
+    search_text STRING(MAX) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP,
+    version INT64 NOT NULL,
+    projected_at TIMESTAMP NOT NULL,
+) PRIMARY KEY (product_id);
+
+CREATE INDEX idx_product_views_status_category ON product_views(status, category, product_id);
+CREATE INDEX idx_product_views_search ON product_views(status, search_text, product_id);
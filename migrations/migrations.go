@@ -0,0 +1,35 @@
+// Package migrations embeds each backend's schema migrations, so binaries
+// can apply or verify them without the source tree. Files are named
+// NNN_description.sql and applied in version order by internal/migrate.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+var (
+	//go:embed *.sql
+	spannerFiles embed.FS
+	//go:embed postgres/*.sql
+	postgresFiles embed.FS
+	//go:embed sqlite/*.sql
+	sqliteFiles embed.FS
+)
+
+// Spanner returns the Cloud Spanner migrations, this directory's own files.
+func Spanner() fs.FS { return spannerFiles }
+
+// Postgres returns the migrations in postgres/.
+func Postgres() fs.FS { return sub(postgresFiles, "postgres") }
+
+// SQLite returns the migrations in sqlite/.
+func SQLite() fs.FS { return sub(sqliteFiles, "sqlite") }
+
+func sub(fsys embed.FS, dir string) fs.FS {
+	s, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err) // dir is a literal embedded above
+	}
+	return s
+}
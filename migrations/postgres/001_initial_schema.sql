@@ -0,0 +1,31 @@
+CREATE TABLE products (
+    product_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    category VARCHAR(100) NOT NULL,
+    base_price_numerator BIGINT NOT NULL,
+    base_price_denominator BIGINT NOT NULL,
+    discount_percent NUMERIC,
+    discount_start_date TIMESTAMPTZ,
+    discount_end_date TIMESTAMPTZ,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    archived_at TIMESTAMPTZ,
+    PRIMARY KEY (product_id)
+);
+
+CREATE TABLE outbox_events (
+    event_id VARCHAR(36) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    aggregate_id VARCHAR(36) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    processed_at TIMESTAMPTZ,
+    PRIMARY KEY (event_id)
+);
+
+CREATE INDEX idx_outbox_status ON outbox_events(status, created_at);
+
+CREATE INDEX idx_products_category ON products(category, status);
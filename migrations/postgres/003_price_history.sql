@@ -0,0 +1,12 @@
+CREATE TABLE product_price_history (
+    product_id VARCHAR(36) NOT NULL REFERENCES products (product_id) ON DELETE CASCADE,
+    change_id VARCHAR(36) NOT NULL,
+    old_price_numerator BIGINT NOT NULL,
+    old_price_denominator BIGINT NOT NULL,
+    new_price_numerator BIGINT NOT NULL,
+    new_price_denominator BIGINT NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (product_id, change_id)
+);
+
+CREATE INDEX idx_price_history_changed_at ON product_price_history(product_id, changed_at DESC);
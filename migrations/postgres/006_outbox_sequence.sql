@@ -0,0 +1,3 @@
+ALTER TABLE outbox_events ADD COLUMN sequence BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_outbox_aggregate_sequence ON outbox_events(aggregate_id, sequence);
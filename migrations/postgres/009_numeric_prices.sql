@@ -0,0 +1,35 @@
+ALTER TABLE products ADD CONSTRAINT products_price_representable CHECK (base_price_numerator::NUMERIC * 1000000000 % base_price_denominator = 0);
+
+ALTER TABLE product_price_history ADD CONSTRAINT price_history_price_representable CHECK (old_price_numerator::NUMERIC * 1000000000 % old_price_denominator = 0 AND new_price_numerator::NUMERIC * 1000000000 % new_price_denominator = 0);
+
+ALTER TABLE products ADD COLUMN base_price NUMERIC;
+
+UPDATE products SET base_price = ROUND(base_price_numerator::NUMERIC / base_price_denominator, 9) WHERE base_price IS NULL;
+
+ALTER TABLE products ALTER COLUMN base_price SET NOT NULL;
+
+ALTER TABLE products ALTER COLUMN base_price_numerator DROP NOT NULL;
+
+ALTER TABLE products ALTER COLUMN base_price_denominator DROP NOT NULL;
+
+ALTER TABLE product_price_history ADD COLUMN old_price NUMERIC;
+
+ALTER TABLE product_price_history ADD COLUMN new_price NUMERIC;
+
+UPDATE product_price_history SET old_price = ROUND(old_price_numerator::NUMERIC / old_price_denominator, 9), new_price = ROUND(new_price_numerator::NUMERIC / new_price_denominator, 9) WHERE old_price IS NULL;
+
+ALTER TABLE product_price_history ALTER COLUMN old_price SET NOT NULL;
+
+ALTER TABLE product_price_history ALTER COLUMN new_price SET NOT NULL;
+
+ALTER TABLE product_price_history ALTER COLUMN old_price_numerator DROP NOT NULL;
+
+ALTER TABLE product_price_history ALTER COLUMN old_price_denominator DROP NOT NULL;
+
+ALTER TABLE product_price_history ALTER COLUMN new_price_numerator DROP NOT NULL;
+
+ALTER TABLE product_price_history ALTER COLUMN new_price_denominator DROP NOT NULL;
+
+ALTER TABLE products DROP CONSTRAINT products_price_representable;
+
+ALTER TABLE product_price_history DROP CONSTRAINT price_history_price_representable;
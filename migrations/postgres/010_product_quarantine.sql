@@ -0,0 +1,5 @@
+CREATE TABLE product_quarantine (
+    product_id VARCHAR(36) NOT NULL PRIMARY KEY,
+    violations TEXT NOT NULL,
+    found_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,22 @@
+CREATE TABLE product_views (
+    product_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    category VARCHAR(100) NOT NULL,
+    base_price NUMERIC NOT NULL,
+    discount_percent NUMERIC,
+    discount_start_date TIMESTAMPTZ,
+    discount_end_date TIMESTAMPTZ,
+    discounted_price NUMERIC,
+    status VARCHAR(20) NOT NULL,
+    search_text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    archived_at TIMESTAMPTZ,
+    version BIGINT NOT NULL,
+    projected_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (product_id)
+);
+
+CREATE INDEX idx_product_views_status_category ON product_views(status, category, product_id);
+CREATE INDEX idx_product_views_search ON product_views(status, search_text text_pattern_ops, product_id);
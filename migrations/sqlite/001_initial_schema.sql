@@ -0,0 +1,31 @@
+CREATE TABLE products (
+    product_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    category TEXT NOT NULL,
+    base_price_numerator INTEGER NOT NULL,
+    base_price_denominator INTEGER NOT NULL,
+    discount_percent TEXT,
+    discount_start_date TEXT,
+    discount_end_date TEXT,
+    status TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    archived_at TEXT,
+    PRIMARY KEY (product_id)
+);
+
+CREATE TABLE outbox_events (
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    payload TEXT NOT NULL CHECK (json_valid(payload)),
+    status TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    processed_at TEXT,
+    PRIMARY KEY (event_id)
+);
+
+CREATE INDEX idx_outbox_status ON outbox_events(status, created_at);
+
+CREATE INDEX idx_products_category ON products(category, status);
@@ -0,0 +1,12 @@
+CREATE TABLE product_price_history (
+    product_id TEXT NOT NULL REFERENCES products (product_id) ON DELETE CASCADE,
+    change_id TEXT NOT NULL,
+    old_price_numerator INTEGER NOT NULL,
+    old_price_denominator INTEGER NOT NULL,
+    new_price_numerator INTEGER NOT NULL,
+    new_price_denominator INTEGER NOT NULL,
+    changed_at TEXT NOT NULL,
+    PRIMARY KEY (product_id, change_id)
+);
+
+CREATE INDEX idx_price_history_changed_at ON product_price_history(product_id, changed_at DESC);
@@ -0,0 +1,5 @@
+ALTER TABLE outbox_events ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE outbox_events ADD COLUMN last_error TEXT;
+
+ALTER TABLE outbox_events ADD COLUMN next_attempt_at TEXT;
@@ -0,0 +1 @@
+ALTER TABLE outbox_events ADD COLUMN metadata TEXT CHECK (json_valid(metadata));
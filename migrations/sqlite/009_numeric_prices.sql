@@ -0,0 +1,29 @@
+CREATE TEMP TABLE unrepresentable_prices (product_id TEXT, CONSTRAINT price_not_representable CHECK (product_id IS NULL));
+
+INSERT INTO temp.unrepresentable_prices SELECT product_id FROM products WHERE base_price_numerator % base_price_denominator * 1000000000 % base_price_denominator != 0;
+
+INSERT INTO temp.unrepresentable_prices SELECT product_id FROM product_price_history WHERE old_price_numerator % old_price_denominator * 1000000000 % old_price_denominator != 0 OR new_price_numerator % new_price_denominator * 1000000000 % new_price_denominator != 0;
+
+DROP TABLE temp.unrepresentable_prices;
+
+ALTER TABLE products ADD COLUMN base_price TEXT NOT NULL DEFAULT '';
+
+UPDATE products SET base_price = base_price_numerator || '/' || base_price_denominator;
+
+ALTER TABLE products DROP COLUMN base_price_numerator;
+
+ALTER TABLE products DROP COLUMN base_price_denominator;
+
+ALTER TABLE product_price_history ADD COLUMN old_price TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE product_price_history ADD COLUMN new_price TEXT NOT NULL DEFAULT '';
+
+UPDATE product_price_history SET old_price = old_price_numerator || '/' || old_price_denominator, new_price = new_price_numerator || '/' || new_price_denominator;
+
+ALTER TABLE product_price_history DROP COLUMN old_price_numerator;
+
+ALTER TABLE product_price_history DROP COLUMN old_price_denominator;
+
+ALTER TABLE product_price_history DROP COLUMN new_price_numerator;
+
+ALTER TABLE product_price_history DROP COLUMN new_price_denominator;
@@ -0,0 +1,5 @@
+CREATE TABLE product_quarantine (
+    product_id TEXT NOT NULL PRIMARY KEY,
+    violations TEXT NOT NULL,
+    found_at TEXT NOT NULL
+);
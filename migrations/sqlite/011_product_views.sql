@@ -0,0 +1,22 @@
+CREATE TABLE product_views (
+    product_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    category TEXT NOT NULL,
+    base_price TEXT NOT NULL,
+    discount_percent TEXT,
+    discount_start_date TEXT,
+    discount_end_date TEXT,
+    discounted_price TEXT,
+    status TEXT NOT NULL,
+    search_text TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    archived_at TEXT,
+    version INTEGER NOT NULL,
+    projected_at TEXT NOT NULL,
+    PRIMARY KEY (product_id)
+);
+
+CREATE INDEX idx_product_views_status_category ON product_views(status, category, product_id);
+CREATE INDEX idx_product_views_search ON product_views(status, search_text, product_id);
@@ -135,13 +135,14 @@ func (x *CreateProductReply) GetProductId() string {
 }
 
 type UpdateProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Name          *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
-	Description   *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
-	Category      *string                `protobuf:"bytes,4,opt,name=category,proto3,oneof" json:"category,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProductId       string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Name            *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Description     *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Category        *string                `protobuf:"bytes,4,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *UpdateProductRequest) Reset() {
@@ -202,6 +203,13 @@ func (x *UpdateProductRequest) GetCategory() string {
 	return ""
 }
 
+func (x *UpdateProductRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
 type UpdateProductReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -239,10 +247,11 @@ func (*UpdateProductReply) Descriptor() ([]byte, []int) {
 }
 
 type ActivateProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProductId       string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ActivateProductRequest) Reset() {
@@ -282,6 +291,13 @@ func (x *ActivateProductRequest) GetProductId() string {
 	return ""
 }
 
+func (x *ActivateProductRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
 type ActivateProductReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -319,10 +335,11 @@ func (*ActivateProductReply) Descriptor() ([]byte, []int) {
 }
 
 type DeactivateProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProductId       string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *DeactivateProductRequest) Reset() {
@@ -362,6 +379,13 @@ func (x *DeactivateProductRequest) GetProductId() string {
 	return ""
 }
 
+func (x *DeactivateProductRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
 type DeactivateProductReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -399,10 +423,11 @@ func (*DeactivateProductReply) Descriptor() ([]byte, []int) {
 }
 
 type ArchiveProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProductId       string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ArchiveProductRequest) Reset() {
@@ -442,6 +467,13 @@ func (x *ArchiveProductRequest) GetProductId() string {
 	return ""
 }
 
+func (x *ArchiveProductRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
 type ArchiveProductReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -479,13 +511,14 @@ func (*ArchiveProductReply) Descriptor() ([]byte, []int) {
 }
 
 type ApplyDiscountRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Percentage    string                 `protobuf:"bytes,2,opt,name=percentage,proto3" json:"percentage,omitempty"` // whole-number percentage, e.g. "20"
-	StartDate     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
-	EndDate       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProductId       string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Percentage      string                 `protobuf:"bytes,2,opt,name=percentage,proto3" json:"percentage,omitempty"` // whole-number percentage, e.g. "20"
+	StartDate       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ApplyDiscountRequest) Reset() {
@@ -546,6 +579,13 @@ func (x *ApplyDiscountRequest) GetEndDate() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *ApplyDiscountRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
 type ApplyDiscountReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -583,10 +623,11 @@ func (*ApplyDiscountReply) Descriptor() ([]byte, []int) {
 }
 
 type RemoveDiscountRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProductId       string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *RemoveDiscountRequest) Reset() {
@@ -626,6 +667,13 @@ func (x *RemoveDiscountRequest) GetProductId() string {
 	return ""
 }
 
+func (x *RemoveDiscountRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
 type RemoveDiscountReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -662,6 +710,102 @@ func (*RemoveDiscountReply) Descriptor() ([]byte, []int) {
 	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{13}
 }
 
+type ChangeBasePriceRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProductId       string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	NewPrice        string                 `protobuf:"bytes,2,opt,name=new_price,json=newPrice,proto3" json:"new_price,omitempty"` // decimal string, e.g. "24.99"
+	ExpectedVersion *int64                 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangeBasePriceRequest) Reset() {
+	*x = ChangeBasePriceRequest{}
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeBasePriceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeBasePriceRequest) ProtoMessage() {}
+
+func (x *ChangeBasePriceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeBasePriceRequest.ProtoReflect.Descriptor instead.
+func (*ChangeBasePriceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ChangeBasePriceRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ChangeBasePriceRequest) GetNewPrice() string {
+	if x != nil {
+		return x.NewPrice
+	}
+	return ""
+}
+
+func (x *ChangeBasePriceRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type ChangeBasePriceReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeBasePriceReply) Reset() {
+	*x = ChangeBasePriceReply{}
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeBasePriceReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeBasePriceReply) ProtoMessage() {}
+
+func (x *ChangeBasePriceReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeBasePriceReply.ProtoReflect.Descriptor instead.
+func (*ChangeBasePriceReply) Descriptor() ([]byte, []int) {
+	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{15}
+}
+
 type GetProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
@@ -671,7 +815,7 @@ type GetProductRequest struct {
 
 func (x *GetProductRequest) Reset() {
 	*x = GetProductRequest{}
-	mi := &file_proto_product_v1_product_service_proto_msgTypes[14]
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -683,7 +827,7 @@ func (x *GetProductRequest) String() string {
 func (*GetProductRequest) ProtoMessage() {}
 
 func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_v1_product_service_proto_msgTypes[14]
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -696,7 +840,7 @@ func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
 func (*GetProductRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{14}
+	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetProductRequest) GetProductId() string {
@@ -715,7 +859,7 @@ type GetProductReply struct {
 
 func (x *GetProductReply) Reset() {
 	*x = GetProductReply{}
-	mi := &file_proto_product_v1_product_service_proto_msgTypes[15]
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -727,7 +871,7 @@ func (x *GetProductReply) String() string {
 func (*GetProductReply) ProtoMessage() {}
 
 func (x *GetProductReply) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_v1_product_service_proto_msgTypes[15]
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -740,7 +884,7 @@ func (x *GetProductReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductReply.ProtoReflect.Descriptor instead.
 func (*GetProductReply) Descriptor() ([]byte, []int) {
-	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{15}
+	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetProductReply) GetProduct() *Product {
@@ -761,7 +905,7 @@ type ListProductsRequest struct {
 
 func (x *ListProductsRequest) Reset() {
 	*x = ListProductsRequest{}
-	mi := &file_proto_product_v1_product_service_proto_msgTypes[16]
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -773,7 +917,7 @@ func (x *ListProductsRequest) String() string {
 func (*ListProductsRequest) ProtoMessage() {}
 
 func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_v1_product_service_proto_msgTypes[16]
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -786,7 +930,7 @@ func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
 func (*ListProductsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{16}
+	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *ListProductsRequest) GetPageSize() int32 {
@@ -820,7 +964,7 @@ type ListProductsReply struct {
 
 func (x *ListProductsReply) Reset() {
 	*x = ListProductsReply{}
-	mi := &file_proto_product_v1_product_service_proto_msgTypes[17]
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -832,7 +976,7 @@ func (x *ListProductsReply) String() string {
 func (*ListProductsReply) ProtoMessage() {}
 
 func (x *ListProductsReply) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_v1_product_service_proto_msgTypes[17]
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -845,7 +989,7 @@ func (x *ListProductsReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsReply.ProtoReflect.Descriptor instead.
 func (*ListProductsReply) Descriptor() ([]byte, []int) {
-	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{17}
+	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ListProductsReply) GetProducts() []*ProductSummary {
@@ -874,13 +1018,14 @@ type Product struct {
 	Status          string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
 	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Version         int64                  `protobuf:"varint,11,opt,name=version,proto3" json:"version,omitempty"` // pass back as expected_version to guard against lost updates
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Product) Reset() {
 	*x = Product{}
-	mi := &file_proto_product_v1_product_service_proto_msgTypes[18]
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -892,7 +1037,7 @@ func (x *Product) String() string {
 func (*Product) ProtoMessage() {}
 
 func (x *Product) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_v1_product_service_proto_msgTypes[18]
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -905,7 +1050,7 @@ func (x *Product) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Product.ProtoReflect.Descriptor instead.
 func (*Product) Descriptor() ([]byte, []int) {
-	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{18}
+	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *Product) GetId() string {
@@ -978,6 +1123,13 @@ func (x *Product) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Product) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type ProductSummary struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -993,7 +1145,7 @@ type ProductSummary struct {
 
 func (x *ProductSummary) Reset() {
 	*x = ProductSummary{}
-	mi := &file_proto_product_v1_product_service_proto_msgTypes[19]
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1005,7 +1157,7 @@ func (x *ProductSummary) String() string {
 func (*ProductSummary) ProtoMessage() {}
 
 func (x *ProductSummary) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_v1_product_service_proto_msgTypes[19]
+	mi := &file_proto_product_v1_product_service_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1018,7 +1170,7 @@ func (x *ProductSummary) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductSummary.ProtoReflect.Descriptor instead.
 func (*ProductSummary) Descriptor() ([]byte, []int) {
-	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{19}
+	return file_proto_product_v1_product_service_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *ProductSummary) GetId() string {
@@ -1084,29 +1236,37 @@ const file_proto_product_v1_product_service_proto_rawDesc = "" +
 	"base_price\x18\x04 \x01(\tR\tbasePrice\"3\n" +
 	"\x12CreateProductReply\x12\x1d\n" +
 	"\n" +
-	"product_id\x18\x01 \x01(\tR\tproductId\"\xbc\x01\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"\x81\x02\n" +
 	"\x14UpdateProductRequest\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12\x17\n" +
 	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12%\n" +
 	"\vdescription\x18\x03 \x01(\tH\x01R\vdescription\x88\x01\x01\x12\x1f\n" +
-	"\bcategory\x18\x04 \x01(\tH\x02R\bcategory\x88\x01\x01B\a\n" +
+	"\bcategory\x18\x04 \x01(\tH\x02R\bcategory\x88\x01\x01\x12.\n" +
+	"\x10expected_version\x18\x05 \x01(\x03H\x03R\x0fexpectedVersion\x88\x01\x01B\a\n" +
 	"\x05_nameB\x0e\n" +
 	"\f_descriptionB\v\n" +
-	"\t_category\"\x14\n" +
-	"\x12UpdateProductReply\"7\n" +
+	"\t_categoryB\x13\n" +
+	"\x11_expected_version\"\x14\n" +
+	"\x12UpdateProductReply\"|\n" +
 	"\x16ActivateProductRequest\x12\x1d\n" +
 	"\n" +
-	"product_id\x18\x01 \x01(\tR\tproductId\"\x16\n" +
-	"\x14ActivateProductReply\"9\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12.\n" +
+	"\x10expected_version\x18\x02 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\x16\n" +
+	"\x14ActivateProductReply\"~\n" +
 	"\x18DeactivateProductRequest\x12\x1d\n" +
 	"\n" +
-	"product_id\x18\x01 \x01(\tR\tproductId\"\x18\n" +
-	"\x16DeactivateProductReply\"6\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12.\n" +
+	"\x10expected_version\x18\x02 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\x18\n" +
+	"\x16DeactivateProductReply\"{\n" +
 	"\x15ArchiveProductRequest\x12\x1d\n" +
 	"\n" +
-	"product_id\x18\x01 \x01(\tR\tproductId\"\x15\n" +
-	"\x13ArchiveProductReply\"\xc7\x01\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12.\n" +
+	"\x10expected_version\x18\x02 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\x15\n" +
+	"\x13ArchiveProductReply\"\x8c\x02\n" +
 	"\x14ApplyDiscountRequest\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1e\n" +
@@ -1115,12 +1275,23 @@ const file_proto_product_v1_product_service_proto_rawDesc = "" +
 	"percentage\x129\n" +
 	"\n" +
 	"start_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
-	"\bend_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\"\x14\n" +
-	"\x12ApplyDiscountReply\"6\n" +
+	"\bend_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12.\n" +
+	"\x10expected_version\x18\x05 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\x14\n" +
+	"\x12ApplyDiscountReply\"{\n" +
 	"\x15RemoveDiscountRequest\x12\x1d\n" +
 	"\n" +
-	"product_id\x18\x01 \x01(\tR\tproductId\"\x15\n" +
-	"\x13RemoveDiscountReply\"2\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12.\n" +
+	"\x10expected_version\x18\x02 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\x15\n" +
+	"\x13RemoveDiscountReply\"\x99\x01\n" +
+	"\x16ChangeBasePriceRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1b\n" +
+	"\tnew_price\x18\x02 \x01(\tR\bnewPrice\x12.\n" +
+	"\x10expected_version\x18\x03 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\x16\n" +
+	"\x14ChangeBasePriceReply\"2\n" +
 	"\x11GetProductRequest\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\"@\n" +
@@ -1133,7 +1304,7 @@ const file_proto_product_v1_product_service_proto_rawDesc = "" +
 	"\bcategory\x18\x03 \x01(\tR\bcategory\"s\n" +
 	"\x11ListProductsReply\x126\n" +
 	"\bproducts\x18\x01 \x03(\v2\x1a.product.v1.ProductSummaryR\bproducts\x12&\n" +
-	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x86\x03\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xa0\x03\n" +
 	"\aProduct\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
@@ -1148,7 +1319,8 @@ const file_proto_product_v1_product_service_proto_rawDesc = "" +
 	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
 	"updated_at\x18\n" +
-	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x13\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\v \x01(\x03R\aversionB\x13\n" +
 	"\x11_discount_percent\"\xeb\x01\n" +
 	"\x0eProductSummary\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
@@ -1159,7 +1331,7 @@ const file_proto_product_v1_product_service_proto_rawDesc = "" +
 	"\x0feffective_price\x18\x05 \x01(\tR\x0eeffectivePrice\x12\x16\n" +
 	"\x06status\x18\x06 \x01(\tR\x06status\x129\n" +
 	"\n" +
-	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt2\x87\x06\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt2\xe0\x06\n" +
 	"\x0eProductService\x12Q\n" +
 	"\rCreateProduct\x12 .product.v1.CreateProductRequest\x1a\x1e.product.v1.CreateProductReply\x12Q\n" +
 	"\rUpdateProduct\x12 .product.v1.UpdateProductRequest\x1a\x1e.product.v1.UpdateProductReply\x12W\n" +
@@ -1167,7 +1339,8 @@ const file_proto_product_v1_product_service_proto_rawDesc = "" +
 	"\x11DeactivateProduct\x12$.product.v1.DeactivateProductRequest\x1a\".product.v1.DeactivateProductReply\x12T\n" +
 	"\x0eArchiveProduct\x12!.product.v1.ArchiveProductRequest\x1a\x1f.product.v1.ArchiveProductReply\x12Q\n" +
 	"\rApplyDiscount\x12 .product.v1.ApplyDiscountRequest\x1a\x1e.product.v1.ApplyDiscountReply\x12T\n" +
-	"\x0eRemoveDiscount\x12!.product.v1.RemoveDiscountRequest\x1a\x1f.product.v1.RemoveDiscountReply\x12H\n" +
+	"\x0eRemoveDiscount\x12!.product.v1.RemoveDiscountRequest\x1a\x1f.product.v1.RemoveDiscountReply\x12W\n" +
+	"\x0fChangeBasePrice\x12\".product.v1.ChangeBasePriceRequest\x1a .product.v1.ChangeBasePriceReply\x12H\n" +
 	"\n" +
 	"GetProduct\x12\x1d.product.v1.GetProductRequest\x1a\x1b.product.v1.GetProductReply\x12N\n" +
 	"\fListProducts\x12\x1f.product.v1.ListProductsRequest\x1a\x1d.product.v1.ListProductsReplyB>Z<github.com/tshubham2/catalog-proj/proto/product/v1;productv1b\x06proto3"
@@ -1184,7 +1357,7 @@ func file_proto_product_v1_product_service_proto_rawDescGZIP() []byte {
 	return file_proto_product_v1_product_service_proto_rawDescData
 }
 
-var file_proto_product_v1_product_service_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_proto_product_v1_product_service_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_proto_product_v1_product_service_proto_goTypes = []any{
 	(*CreateProductRequest)(nil),     // 0: product.v1.CreateProductRequest
 	(*CreateProductReply)(nil),       // 1: product.v1.CreateProductReply
@@ -1200,22 +1373,24 @@ var file_proto_product_v1_product_service_proto_goTypes = []any{
 	(*ApplyDiscountReply)(nil),       // 11: product.v1.ApplyDiscountReply
 	(*RemoveDiscountRequest)(nil),    // 12: product.v1.RemoveDiscountRequest
 	(*RemoveDiscountReply)(nil),      // 13: product.v1.RemoveDiscountReply
-	(*GetProductRequest)(nil),        // 14: product.v1.GetProductRequest
-	(*GetProductReply)(nil),          // 15: product.v1.GetProductReply
-	(*ListProductsRequest)(nil),      // 16: product.v1.ListProductsRequest
-	(*ListProductsReply)(nil),        // 17: product.v1.ListProductsReply
-	(*Product)(nil),                  // 18: product.v1.Product
-	(*ProductSummary)(nil),           // 19: product.v1.ProductSummary
-	(*timestamppb.Timestamp)(nil),    // 20: google.protobuf.Timestamp
+	(*ChangeBasePriceRequest)(nil),   // 14: product.v1.ChangeBasePriceRequest
+	(*ChangeBasePriceReply)(nil),     // 15: product.v1.ChangeBasePriceReply
+	(*GetProductRequest)(nil),        // 16: product.v1.GetProductRequest
+	(*GetProductReply)(nil),          // 17: product.v1.GetProductReply
+	(*ListProductsRequest)(nil),      // 18: product.v1.ListProductsRequest
+	(*ListProductsReply)(nil),        // 19: product.v1.ListProductsReply
+	(*Product)(nil),                  // 20: product.v1.Product
+	(*ProductSummary)(nil),           // 21: product.v1.ProductSummary
+	(*timestamppb.Timestamp)(nil),    // 22: google.protobuf.Timestamp
 }
 var file_proto_product_v1_product_service_proto_depIdxs = []int32{
-	20, // 0: product.v1.ApplyDiscountRequest.start_date:type_name -> google.protobuf.Timestamp
-	20, // 1: product.v1.ApplyDiscountRequest.end_date:type_name -> google.protobuf.Timestamp
-	18, // 2: product.v1.GetProductReply.product:type_name -> product.v1.Product
-	19, // 3: product.v1.ListProductsReply.products:type_name -> product.v1.ProductSummary
-	20, // 4: product.v1.Product.created_at:type_name -> google.protobuf.Timestamp
-	20, // 5: product.v1.Product.updated_at:type_name -> google.protobuf.Timestamp
-	20, // 6: product.v1.ProductSummary.created_at:type_name -> google.protobuf.Timestamp
+	22, // 0: product.v1.ApplyDiscountRequest.start_date:type_name -> google.protobuf.Timestamp
+	22, // 1: product.v1.ApplyDiscountRequest.end_date:type_name -> google.protobuf.Timestamp
+	20, // 2: product.v1.GetProductReply.product:type_name -> product.v1.Product
+	21, // 3: product.v1.ListProductsReply.products:type_name -> product.v1.ProductSummary
+	22, // 4: product.v1.Product.created_at:type_name -> google.protobuf.Timestamp
+	22, // 5: product.v1.Product.updated_at:type_name -> google.protobuf.Timestamp
+	22, // 6: product.v1.ProductSummary.created_at:type_name -> google.protobuf.Timestamp
 	0,  // 7: product.v1.ProductService.CreateProduct:input_type -> product.v1.CreateProductRequest
 	2,  // 8: product.v1.ProductService.UpdateProduct:input_type -> product.v1.UpdateProductRequest
 	4,  // 9: product.v1.ProductService.ActivateProduct:input_type -> product.v1.ActivateProductRequest
@@ -1223,19 +1398,21 @@ var file_proto_product_v1_product_service_proto_depIdxs = []int32{
 	8,  // 11: product.v1.ProductService.ArchiveProduct:input_type -> product.v1.ArchiveProductRequest
 	10, // 12: product.v1.ProductService.ApplyDiscount:input_type -> product.v1.ApplyDiscountRequest
 	12, // 13: product.v1.ProductService.RemoveDiscount:input_type -> product.v1.RemoveDiscountRequest
-	14, // 14: product.v1.ProductService.GetProduct:input_type -> product.v1.GetProductRequest
-	16, // 15: product.v1.ProductService.ListProducts:input_type -> product.v1.ListProductsRequest
-	1,  // 16: product.v1.ProductService.CreateProduct:output_type -> product.v1.CreateProductReply
-	3,  // 17: product.v1.ProductService.UpdateProduct:output_type -> product.v1.UpdateProductReply
-	5,  // 18: product.v1.ProductService.ActivateProduct:output_type -> product.v1.ActivateProductReply
-	7,  // 19: product.v1.ProductService.DeactivateProduct:output_type -> product.v1.DeactivateProductReply
-	9,  // 20: product.v1.ProductService.ArchiveProduct:output_type -> product.v1.ArchiveProductReply
-	11, // 21: product.v1.ProductService.ApplyDiscount:output_type -> product.v1.ApplyDiscountReply
-	13, // 22: product.v1.ProductService.RemoveDiscount:output_type -> product.v1.RemoveDiscountReply
-	15, // 23: product.v1.ProductService.GetProduct:output_type -> product.v1.GetProductReply
-	17, // 24: product.v1.ProductService.ListProducts:output_type -> product.v1.ListProductsReply
-	16, // [16:25] is the sub-list for method output_type
-	7,  // [7:16] is the sub-list for method input_type
+	14, // 14: product.v1.ProductService.ChangeBasePrice:input_type -> product.v1.ChangeBasePriceRequest
+	16, // 15: product.v1.ProductService.GetProduct:input_type -> product.v1.GetProductRequest
+	18, // 16: product.v1.ProductService.ListProducts:input_type -> product.v1.ListProductsRequest
+	1,  // 17: product.v1.ProductService.CreateProduct:output_type -> product.v1.CreateProductReply
+	3,  // 18: product.v1.ProductService.UpdateProduct:output_type -> product.v1.UpdateProductReply
+	5,  // 19: product.v1.ProductService.ActivateProduct:output_type -> product.v1.ActivateProductReply
+	7,  // 20: product.v1.ProductService.DeactivateProduct:output_type -> product.v1.DeactivateProductReply
+	9,  // 21: product.v1.ProductService.ArchiveProduct:output_type -> product.v1.ArchiveProductReply
+	11, // 22: product.v1.ProductService.ApplyDiscount:output_type -> product.v1.ApplyDiscountReply
+	13, // 23: product.v1.ProductService.RemoveDiscount:output_type -> product.v1.RemoveDiscountReply
+	15, // 24: product.v1.ProductService.ChangeBasePrice:output_type -> product.v1.ChangeBasePriceReply
+	17, // 25: product.v1.ProductService.GetProduct:output_type -> product.v1.GetProductReply
+	19, // 26: product.v1.ProductService.ListProducts:output_type -> product.v1.ListProductsReply
+	17, // [17:27] is the sub-list for method output_type
+	7,  // [7:17] is the sub-list for method input_type
 	7,  // [7:7] is the sub-list for extension type_name
 	7,  // [7:7] is the sub-list for extension extendee
 	0,  // [0:7] is the sub-list for field type_name
@@ -1247,14 +1424,20 @@ func file_proto_product_v1_product_service_proto_init() {
 		return
 	}
 	file_proto_product_v1_product_service_proto_msgTypes[2].OneofWrappers = []any{}
-	file_proto_product_v1_product_service_proto_msgTypes[18].OneofWrappers = []any{}
+	file_proto_product_v1_product_service_proto_msgTypes[4].OneofWrappers = []any{}
+	file_proto_product_v1_product_service_proto_msgTypes[6].OneofWrappers = []any{}
+	file_proto_product_v1_product_service_proto_msgTypes[8].OneofWrappers = []any{}
+	file_proto_product_v1_product_service_proto_msgTypes[10].OneofWrappers = []any{}
+	file_proto_product_v1_product_service_proto_msgTypes[12].OneofWrappers = []any{}
+	file_proto_product_v1_product_service_proto_msgTypes[14].OneofWrappers = []any{}
+	file_proto_product_v1_product_service_proto_msgTypes[20].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_product_v1_product_service_proto_rawDesc), len(file_proto_product_v1_product_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   20,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -26,6 +26,7 @@ const (
 	ProductService_ArchiveProduct_FullMethodName    = "/product.v1.ProductService/ArchiveProduct"
 	ProductService_ApplyDiscount_FullMethodName     = "/product.v1.ProductService/ApplyDiscount"
 	ProductService_RemoveDiscount_FullMethodName    = "/product.v1.ProductService/RemoveDiscount"
+	ProductService_ChangeBasePrice_FullMethodName   = "/product.v1.ProductService/ChangeBasePrice"
 	ProductService_GetProduct_FullMethodName        = "/product.v1.ProductService/GetProduct"
 	ProductService_ListProducts_FullMethodName      = "/product.v1.ProductService/ListProducts"
 )
@@ -42,6 +43,7 @@ type ProductServiceClient interface {
 	ArchiveProduct(ctx context.Context, in *ArchiveProductRequest, opts ...grpc.CallOption) (*ArchiveProductReply, error)
 	ApplyDiscount(ctx context.Context, in *ApplyDiscountRequest, opts ...grpc.CallOption) (*ApplyDiscountReply, error)
 	RemoveDiscount(ctx context.Context, in *RemoveDiscountRequest, opts ...grpc.CallOption) (*RemoveDiscountReply, error)
+	ChangeBasePrice(ctx context.Context, in *ChangeBasePriceRequest, opts ...grpc.CallOption) (*ChangeBasePriceReply, error)
 	// Queries
 	GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*GetProductReply, error)
 	ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsReply, error)
@@ -125,6 +127,16 @@ func (c *productServiceClient) RemoveDiscount(ctx context.Context, in *RemoveDis
 	return out, nil
 }
 
+func (c *productServiceClient) ChangeBasePrice(ctx context.Context, in *ChangeBasePriceRequest, opts ...grpc.CallOption) (*ChangeBasePriceReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeBasePriceReply)
+	err := c.cc.Invoke(ctx, ProductService_ChangeBasePrice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *productServiceClient) GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*GetProductReply, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetProductReply)
@@ -157,6 +169,7 @@ type ProductServiceServer interface {
 	ArchiveProduct(context.Context, *ArchiveProductRequest) (*ArchiveProductReply, error)
 	ApplyDiscount(context.Context, *ApplyDiscountRequest) (*ApplyDiscountReply, error)
 	RemoveDiscount(context.Context, *RemoveDiscountRequest) (*RemoveDiscountReply, error)
+	ChangeBasePrice(context.Context, *ChangeBasePriceRequest) (*ChangeBasePriceReply, error)
 	// Queries
 	GetProduct(context.Context, *GetProductRequest) (*GetProductReply, error)
 	ListProducts(context.Context, *ListProductsRequest) (*ListProductsReply, error)
@@ -191,6 +204,9 @@ func (UnimplementedProductServiceServer) ApplyDiscount(context.Context, *ApplyDi
 func (UnimplementedProductServiceServer) RemoveDiscount(context.Context, *RemoveDiscountRequest) (*RemoveDiscountReply, error) {
 	return nil, status.Error(codes.Unimplemented, "method RemoveDiscount not implemented")
 }
+func (UnimplementedProductServiceServer) ChangeBasePrice(context.Context, *ChangeBasePriceRequest) (*ChangeBasePriceReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method ChangeBasePrice not implemented")
+}
 func (UnimplementedProductServiceServer) GetProduct(context.Context, *GetProductRequest) (*GetProductReply, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetProduct not implemented")
 }
@@ -344,6 +360,24 @@ func _ProductService_RemoveDiscount_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProductService_ChangeBasePrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeBasePriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ChangeBasePrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_ChangeBasePrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ChangeBasePrice(ctx, req.(*ChangeBasePriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ProductService_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetProductRequest)
 	if err := dec(in); err != nil {
@@ -415,6 +449,10 @@ var ProductService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveDiscount",
 			Handler:    _ProductService_RemoveDiscount_Handler,
 		},
+		{
+			MethodName: "ChangeBasePrice",
+			Handler:    _ProductService_ChangeBasePrice_Handler,
+		},
 		{
 			MethodName: "GetProduct",
 			Handler:    _ProductService_GetProduct_Handler,
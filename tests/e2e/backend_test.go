@@ -0,0 +1,85 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
+	"github.com/tshubham2/catalog-proj/internal/migrate"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+)
+
+// errUnavailable marks setup failures that mean the database isn't running
+// here; the suite is skipped rather than failed.
+var errUnavailable = errors.New("database not available")
+
+// outboxStore is everything the tests use of an outbox repository.
+type outboxStore interface {
+	contracts.OutboxRepository
+	contracts.OutboxStore
+	contracts.DeadLetterStore
+	contracts.OutboxRetentionStore
+	FindByAggregate(ctx context.Context, aggregateID string) ([]contracts.OutboxEvent, error)
+}
+
+// productViews is a backend's product_views repository.
+type productViews interface {
+	contracts.ProductReadModel
+	contracts.ProductViewStore
+}
+
+// harness is one backend's repositories plus the raw access the tests need
+// for assertions and for writes that bypass the aggregate.
+type harness struct {
+	committer    committer.Committer
+	products     contracts.ProductRepository
+	outbox       outboxStore
+	priceHistory contracts.PriceHistoryRepository
+	readModel    contracts.ProductReadModel
+	records      contracts.ProductRecordStore
+	quarantine   contracts.QuarantineRepository
+	views        productViews
+
+	priceChanges  func(ctx context.Context, productID string) ([]priceChangeRow, error)
+	outboxRows    func(ctx context.Context, aggregateID string) ([]outboxRow, error)
+	renameProduct func(ctx context.Context, productID, name string) error
+	close         func()
+}
+
+type priceChangeRow struct {
+	oldPrice, newPrice *big.Rat
+}
+
+// parsePriceChange reads prices the SQL backends return as text.
+func parsePriceChange(oldPrice, newPrice string) (priceChangeRow, error) {
+	row := priceChangeRow{new(big.Rat), new(big.Rat)}
+	if _, ok := row.oldPrice.SetString(oldPrice); !ok {
+		return priceChangeRow{}, fmt.Errorf("malformed old_price %q", oldPrice)
+	}
+	if _, ok := row.newPrice.SetString(newPrice); !ok {
+		return priceChangeRow{}, fmt.Errorf("malformed new_price %q", newPrice)
+	}
+	return row, nil
+}
+
+type outboxRow struct {
+	eventID     string
+	eventType   string
+	aggregateID string
+	status      string
+	payload     string
+}
+
+// migrateSchema brings a fresh database up to date with the runner the
+// cmd/migrate binary uses.
+func migrateSchema(ctx context.Context, target migrate.Target, files fs.FS) error {
+	ms, err := migrate.Load(files)
+	if err != nil {
+		return err
+	}
+	_, err = migrate.NewRunner(target, ms).Up(ctx, false)
+	return err
+}
@@ -0,0 +1,79 @@
+package e2e
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/memory"
+	"github.com/tshubham2/catalog-proj/internal/models/m_outbox"
+	"github.com/tshubham2/catalog-proj/internal/models/m_price_history"
+	"github.com/tshubham2/catalog-proj/internal/models/m_product"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	memdriver "github.com/tshubham2/commitplan/drivers/memory"
+)
+
+// setupMemory runs the suite against the in-process memory backend. It
+// needs nothing running and keeps nothing afterwards.
+func setupMemory(context.Context) (*harness, error) {
+	store := memory.NewStore()
+	cm := memory.NewCommitter(store)
+	products := memory.NewProductRepo(store)
+	return &harness{
+		committer:    cm,
+		products:     products,
+		outbox:       memory.NewOutboxRepo(store),
+		priceHistory: memory.NewPriceHistoryRepo(),
+		readModel:    memory.NewProductReadModel(store),
+		records:      products,
+		quarantine:   memory.NewQuarantineRepo(),
+		views:        memory.NewProductViewRepo(store),
+
+		priceChanges: func(_ context.Context, productID string) ([]priceChangeRow, error) {
+			var out []priceChangeRow
+			store.DB().Scan(m_price_history.Table, func(row memdriver.Row) bool {
+				if row[m_price_history.ProductID] == productID {
+					oldPrice := row[m_price_history.OldPrice].(big.Rat)
+					newPrice := row[m_price_history.NewPrice].(big.Rat)
+					out = append(out, priceChangeRow{&oldPrice, &newPrice})
+				}
+				return true
+			})
+			return out, nil
+		},
+		outboxRows: func(_ context.Context, aggregateID string) ([]outboxRow, error) {
+			type created struct {
+				row outboxRow
+				at  time.Time
+			}
+			var rows []created
+			store.DB().Scan(m_outbox.Table, func(row memdriver.Row) bool {
+				if row[m_outbox.AggregateID] != aggregateID {
+					return true
+				}
+				r := outboxRow{}
+				r.eventID, _ = row[m_outbox.EventID].(string)
+				r.eventType, _ = row[m_outbox.EventType].(string)
+				r.aggregateID = aggregateID
+				r.status, _ = row[m_outbox.Status].(string)
+				r.payload, _ = row[m_outbox.Payload].(string)
+				at, _ := row[m_outbox.CreatedAt].(time.Time)
+				rows = append(rows, created{r, at})
+				return true
+			})
+			sort.SliceStable(rows, func(i, j int) bool { return rows[i].at.Before(rows[j].at) })
+			out := make([]outboxRow, len(rows))
+			for i, r := range rows {
+				out[i] = r.row
+			}
+			return out, nil
+		},
+		renameProduct: func(ctx context.Context, productID, name string) error {
+			plan := committer.NewPlan()
+			plan.Add(m_product.New().UpdateMap(productID, map[string]interface{}{m_product.Name: name}))
+			return cm.Apply(ctx, plan)
+		},
+		close: func() {},
+	}, nil
+}
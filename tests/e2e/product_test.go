@@ -3,79 +3,100 @@ package e2e
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
-	"strings"
+	"sync"
 	"testing"
+	"testing/quick"
 	"time"
 
-	"cloud.google.com/go/spanner"
-	database "cloud.google.com/go/spanner/admin/database/apiv1"
-	databasepb "cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
-	instance "cloud.google.com/go/spanner/admin/instance/apiv1"
-	instancepb "cloud.google.com/go/spanner/admin/instance/apiv1/instancepb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"google.golang.org/api/iterator"
 
+	"github.com/tshubham2/catalog-proj/internal/app/product/contracts"
 	"github.com/tshubham2/catalog-proj/internal/app/product/domain"
 	"github.com/tshubham2/catalog-proj/internal/app/product/queries/get_product"
 	"github.com/tshubham2/catalog-proj/internal/app/product/queries/list_products"
 	"github.com/tshubham2/catalog-proj/internal/app/product/repo"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases"
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/activate_product"
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/apply_discount"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/change_price"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/check_integrity"
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/create_product"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/project_views"
 	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/update_product"
+	"github.com/tshubham2/catalog-proj/internal/app/product/usecases/verify_history"
+	"github.com/tshubham2/catalog-proj/internal/models/m_product"
+	"github.com/tshubham2/catalog-proj/internal/outbox"
 	"github.com/tshubham2/catalog-proj/internal/pkg/clock"
+	"github.com/tshubham2/catalog-proj/internal/pkg/cloudevents"
 	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
-)
-
-const (
-	projectID  = "test-project"
-	instanceID = "test-instance"
+	"github.com/tshubham2/catalog-proj/internal/pkg/eventmeta"
 )
 
 var (
-	spannerClient     *spanner.Client
+	backend           *harness
+	productRepo       contracts.ProductRepository
+	outboxRepo        outboxStore
+	commitPlanner     committer.Committer
 	createProductUC   *create_product.Interactor
 	updateProductUC   *update_product.Interactor
 	applyDiscountUC   *apply_discount.ApplyInteractor
 	removeDiscountUC  *apply_discount.RemoveInteractor
+	changePriceUC     *change_price.Interactor
 	activateUC        *activate_product.ActivateInteractor
 	deactivateUC      *activate_product.DeactivateInteractor
+	archiveUC         *activate_product.ArchiveInteractor
+	verifyHistoryUC   *verify_history.Interactor
+	eventStore        contracts.ProductEventStore
 	getProductQuery   *get_product.Handler
 	listProductsQuery *list_products.Handler
 	testClock         clock.Clock
 )
 
+// E2E_BACKEND picks the database the suite runs against: "spanner" (the
+// default, via the emulator), "postgres" (via POSTGRES_URL), "sqlite" (a
+// temporary file) or "memory" (in process, for fast runs).
 func TestMain(m *testing.M) {
-	if os.Getenv("SPANNER_EMULATOR_HOST") == "" {
-		os.Setenv("SPANNER_EMULATOR_HOST", "localhost:9010")
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	databaseID := fmt.Sprintf("testdb_%d", time.Now().UnixNano())
-	dbPath := fmt.Sprintf("projects/%s/instances/%s/databases/%s", projectID, instanceID, databaseID)
-
-	if err := provisionEmulator(ctx, databaseID); err != nil {
-		fmt.Fprintf(os.Stderr, "skipping E2E tests (emulator not available): %v\n", err)
+	name := os.Getenv("E2E_BACKEND")
+	var (
+		h   *harness
+		err error
+	)
+	switch name {
+	case "", "spanner":
+		name = "spanner"
+		h, err = setupSpanner(ctx)
+	case "postgres":
+		h, err = setupPostgres(ctx)
+	case "sqlite":
+		h, err = setupSQLite(ctx)
+	case "memory":
+		h, err = setupMemory(ctx)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown E2E_BACKEND %q\n", name)
+		os.Exit(1)
+	}
+	if errors.Is(err, errUnavailable) {
+		fmt.Fprintf(os.Stderr, "skipping E2E tests (%s not available): %v\n", name, err)
 		os.Exit(0)
 	}
-
-	client, err := spanner.NewClient(ctx, dbPath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "cannot create spanner client: %v\n", err)
+		fmt.Fprintf(os.Stderr, "cannot set up %s: %v\n", name, err)
 		os.Exit(1)
 	}
-	spannerClient = client
+	backend = h
 
-	wireUsecases(client)
+	wireUsecases(h)
 
 	code := m.Run()
-	client.Close()
+	h.close()
 	os.Exit(code)
 }
 
@@ -144,7 +165,7 @@ func TestDiscountApplicationFlow(t *testing.T) {
 	product, err := getProductQuery.Execute(ctx, productID)
 	require.NoError(t, err)
 	assert.Equal(t, "49.99", product.BasePrice)
-	assert.Equal(t, "37.49", product.EffectivePrice) // 49.99 * 0.75
+	assert.Equal(t, "37.4925", product.EffectivePrice) // 49.99 * 0.75
 	require.NotNil(t, product.DiscountPercent)
 	assert.Equal(t, "25.00", *product.DiscountPercent)
 
@@ -178,6 +199,200 @@ func TestRemoveDiscountFlow(t *testing.T) {
 	assert.Nil(t, product.DiscountPercent)
 }
 
+func TestChangeBasePriceFlow(t *testing.T) {
+	ctx := context.Background()
+
+	productID := createTestProduct(t, ctx, "Repriced Item", "electronics")
+
+	now := time.Now().UTC()
+	require.NoError(t, applyDiscountUC.Execute(ctx, apply_discount.ApplyRequest{
+		ProductID:  productID,
+		Percentage: big.NewRat(10, 1),
+		StartDate:  now.Add(-time.Hour),
+		EndDate:    now.Add(24 * time.Hour),
+	}))
+
+	err := changePriceUC.Execute(ctx, change_price.Request{
+		ProductID: productID,
+		NewPrice:  big.NewRat(5999, 100),
+	})
+	require.NoError(t, err)
+
+	product, err := getProductQuery.Execute(ctx, productID)
+	require.NoError(t, err)
+	assert.Equal(t, "59.99", product.BasePrice)
+	assert.Equal(t, "53.991", product.EffectivePrice) // discount still applies
+
+	changes, err := backend.priceChanges(ctx, productID)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, 0, changes[0].oldPrice.Cmp(big.NewRat(4999, 100)))
+	assert.Equal(t, 0, changes[0].newPrice.Cmp(big.NewRat(5999, 100)))
+
+	events := getOutboxEvents(t, ctx, productID)
+	types := make([]string, len(events))
+	for i, e := range events {
+		types[i] = e.eventType
+	}
+	assert.Contains(t, types, "product.price_changed")
+}
+
+// Any price the domain accepts reads back exactly, through both
+// ProductRepository (toDomain) and the read model.
+func TestBasePrice_RoundTripsExactly(t *testing.T) {
+	ctx := context.Background()
+
+	roundTrip := func(raw uint64, scale uint8) bool {
+		price := new(big.Rat).SetFrac(
+			new(big.Int).SetUint64(raw>>1), // non-negative and fits int64
+			new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale%(domain.PriceScale+1))), nil),
+		)
+		id, err := createProductUC.Execute(ctx, create_product.Request{
+			Name:      "Round Trip",
+			Category:  "roundtrip",
+			BasePrice: price,
+		})
+		if !assert.NoError(t, err, "create at %s", price.RatString()) {
+			return false
+		}
+
+		product, err := productRepo.FindByID(ctx, id)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		view, err := backend.readModel.GetByID(ctx, id)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		return assert.Zero(t, price.Cmp(product.BasePrice().Amount()), "repo: %s != %s", product.BasePrice().Amount().RatString(), price.RatString()) &&
+			assert.Zero(t, price.Cmp(view.BasePrice), "read model: %s != %s", view.BasePrice.RatString(), price.RatString())
+	}
+	require.NoError(t, quick.Check(roundTrip, &quick.Config{MaxCount: 25}))
+
+	_, err := createProductUC.Execute(ctx, create_product.Request{
+		Name:      "Third",
+		Category:  "roundtrip",
+		BasePrice: big.NewRat(1, 3),
+	})
+	assert.ErrorIs(t, err, domain.ErrPriceNotRepresentable)
+}
+
+// A row written around the aggregate with half a discount is refused on
+// both sides, reported and quarantined, and then repaired.
+func TestCorruptProduct_DetectedAndRepaired(t *testing.T) {
+	ctx := context.Background()
+	productID := createTestProduct(t, ctx, "Half Discount", "corrupt")
+
+	err := commitPlanner.RunInTransaction(ctx, func(context.Context) (*committer.Plan, error) {
+		plan := committer.NewPlan()
+		plan.Add(m_product.New().UpdateMap(productID, map[string]interface{}{
+			m_product.DiscountStartDate: time.Now().UTC(),
+		}))
+		return plan, nil
+	})
+	require.NoError(t, err)
+
+	_, err = productRepo.FindByID(ctx, productID)
+	var corrupt *domain.CorruptProductError
+	require.True(t, errors.As(err, &corrupt), "got %v", err)
+	assert.Equal(t, domain.FieldDiscount, corrupt.Violations[0].Field)
+	_, err = getProductQuery.Execute(ctx, productID)
+	assert.ErrorIs(t, err, domain.ErrCorruptProduct)
+
+	verified, err := verifyHistoryUC.Execute(ctx, verify_history.Request{ProductID: productID})
+	require.NoError(t, err, "a corrupt row is drift, not a failed check")
+	require.Len(t, verified.Drift, 1)
+	assert.Equal(t, domain.FieldDiscount, verified.Drift[0].Field)
+	assert.Contains(t, verified.Drift[0].Stored, "corrupt: ")
+	assert.Equal(t, "<none>", verified.Drift[0].Replayed)
+
+	checker := check_integrity.NewInteractor(backend.records, backend.quarantine, commitPlanner, testClock)
+	report, err := checker.Execute(ctx, check_integrity.Request{ProductID: productID, Quarantine: true})
+	require.NoError(t, err)
+	require.Len(t, report.Violations, 1)
+	assert.Empty(t, report.Repaired)
+
+	report, err = checker.Execute(ctx, check_integrity.Request{ProductID: productID, Repair: true, Quarantine: true})
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+	require.Len(t, report.Repaired, 1)
+
+	product, err := productRepo.FindByID(ctx, productID)
+	require.NoError(t, err)
+	assert.Nil(t, product.Discount())
+	assert.Equal(t, int64(1), product.Version(), "a repair isn't an event")
+}
+
+func TestProductViews_ProjectedFromEvents(t *testing.T) {
+	ctx := context.Background()
+	projector := project_views.NewInteractor(backend.views, eventStore, productRepo, usecases.DecodeEvent, commitPlanner, testClock)
+
+	productID := createTestProduct(t, ctx, "Projected Item", "projected")
+	require.NoError(t, changePriceUC.Execute(ctx, change_price.Request{ProductID: productID, NewPrice: big.NewRat(40, 1)}))
+	now := time.Now().UTC()
+	require.NoError(t, applyDiscountUC.Execute(ctx, apply_discount.ApplyRequest{
+		ProductID:  productID,
+		Percentage: big.NewRat(25, 1),
+		StartDate:  now.Add(-time.Hour),
+		EndDate:    now.Add(time.Hour),
+	}))
+
+	events, err := backend.outbox.FindByAggregate(ctx, productID)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	_, err = backend.views.GetByID(ctx, productID)
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	for _, e := range events {
+		require.NoError(t, projector.Project(ctx, e))
+	}
+	require.NoError(t, projector.Project(ctx, events[1]), "redelivery is skipped")
+
+	views := get_product.NewHandler(backend.views, testClock)
+	view, err := views.Execute(ctx, productID)
+	require.NoError(t, err)
+	product, err := getProductQuery.Execute(ctx, productID)
+	require.NoError(t, err)
+	assert.Equal(t, product, view, "the view matches the products row")
+	assert.Equal(t, "30.00", view.EffectivePrice)
+	raw, err := backend.views.GetByID(ctx, productID)
+	require.NoError(t, err)
+	require.NotNil(t, raw.DiscountedPrice, "the view serves its precomputed price")
+	assert.Equal(t, "30", raw.DiscountedPrice.RatString())
+	assert.Equal(t, "projected item projected", raw.SearchText)
+
+	// A view that fell behind is rebuilt from the whole history.
+	require.NoError(t, removeDiscountUC.Execute(ctx, apply_discount.RemoveRequest{ProductID: productID}))
+	name := "Renamed Item"
+	require.NoError(t, updateProductUC.Execute(ctx, update_product.Request{ProductID: productID, Name: &name}))
+	events, err = backend.outbox.FindByAggregate(ctx, productID)
+	require.NoError(t, err)
+	require.NoError(t, projector.Project(ctx, events[len(events)-1]))
+
+	view, err = views.Execute(ctx, productID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed Item", view.Name)
+	assert.Equal(t, "40.00", view.EffectivePrice)
+	raw, err = backend.views.GetByID(ctx, productID)
+	require.NoError(t, err)
+	assert.Nil(t, raw.DiscountedPrice)
+	assert.Equal(t, "renamed item projected", raw.SearchText)
+	assert.Equal(t, int64(5), view.Version)
+
+	report, err := projector.Rebuild(ctx, productID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), report.Version)
+	assert.False(t, report.FromRow)
+	raw, err = backend.views.GetByID(ctx, productID)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed item projected", raw.SearchText, "the rebuild precomputes it too")
+
+	list, _, err := backend.views.ListActive(ctx, 10, "", "projected")
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+	assert.Equal(t, productID, list[0].ID)
+}
+
 func TestProductActivationDeactivation(t *testing.T) {
 	ctx := context.Background()
 
@@ -200,6 +415,101 @@ func TestProductActivationDeactivation(t *testing.T) {
 	assert.Equal(t, "active", product.Status)
 }
 
+func TestProductArchive_WritesOutboxEvent(t *testing.T) {
+	ctx := context.Background()
+
+	productID := createTestProduct(t, ctx, "Archive Item", "electronics")
+	require.NoError(t, archiveUC.Execute(ctx, activate_product.Request{ProductID: productID}))
+
+	product, err := getProductQuery.Execute(ctx, productID)
+	require.NoError(t, err)
+	assert.Equal(t, "archived", product.Status)
+
+	var archived *outboxRow
+	for _, e := range getOutboxEvents(t, ctx, productID) {
+		if e.eventType == "product.archived" {
+			archived = &e
+		}
+	}
+	require.NotNil(t, archived, "archiving writes a product.archived outbox row")
+
+	ce, err := cloudevents.Parse([]byte(archived.payload))
+	require.NoError(t, err)
+	var data struct {
+		ArchivedAt string `json:"archived_at"`
+	}
+	require.NoError(t, json.Unmarshal(ce.Data, &data))
+	assert.NotEmpty(t, data.ArchivedAt)
+}
+
+func TestOutboxEventMetadata(t *testing.T) {
+	meta := eventmeta.Metadata{
+		CorrelationID: "corr-e2e",
+		CausationID:   "cause-e2e",
+		Actor:         "alice@example.com",
+		Client:        "pricing-tool",
+		TraceParent:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}
+	ctx := eventmeta.NewContext(context.Background(), meta)
+
+	productID := createTestProduct(t, ctx, "Traced", "electronics")
+	newPrice := big.NewRat(5999, 100)
+	require.NoError(t, changePriceUC.Execute(ctx, change_price.Request{ProductID: productID, NewPrice: newPrice}))
+
+	events, err := outboxRepo.FindByAggregate(context.Background(), productID)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, e := range events {
+		assert.Equal(t, meta, e.Metadata, e.EventType)
+		ce, err := cloudevents.Parse(e.Payload)
+		require.NoError(t, err)
+		assert.Equal(t, meta.TraceParent, ce.TraceParent)
+	}
+
+	// Writes made without metadata still get a correlation ID.
+	plainID := createTestProduct(t, context.Background(), "Untraced", "electronics")
+	events, err = outboxRepo.FindByAggregate(context.Background(), plainID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, events[0].ID, events[0].Metadata.CorrelationID)
+}
+
+func TestVerifyHistory_ReportsDrift(t *testing.T) {
+	ctx := context.Background()
+
+	productID := createTestProduct(t, ctx, "Replayed", "electronics")
+	name := "Replayed Again"
+	require.NoError(t, updateProductUC.Execute(ctx, update_product.Request{ProductID: productID, Name: &name}))
+	now := time.Now().UTC()
+	require.NoError(t, applyDiscountUC.Execute(ctx, apply_discount.ApplyRequest{
+		ProductID:  productID,
+		Percentage: big.NewRat(25, 2),
+		StartDate:  now.Add(-time.Hour),
+		EndDate:    now.Add(24 * time.Hour),
+	}))
+	require.NoError(t, deactivateUC.Execute(ctx, activate_product.Request{ProductID: productID}))
+
+	replayed, err := eventStore.LoadFromEvents(ctx, productID)
+	require.NoError(t, err)
+	assert.Equal(t, name, replayed.Name())
+	assert.Equal(t, domain.ProductStatusInactive, replayed.Status())
+	assert.Equal(t, int64(4), replayed.Version())
+
+	report, err := verifyHistoryUC.Execute(ctx, verify_history.Request{ProductID: productID})
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "%+v", report)
+
+	// A write that bypasses the aggregate records no event.
+	require.NoError(t, backend.renameProduct(ctx, productID, "Sneaky"))
+
+	report, err = verifyHistoryUC.Execute(ctx, verify_history.Request{ProductID: productID})
+	require.NoError(t, err)
+	assert.Equal(t, []verify_history.Drift{{Field: "name", Stored: "Sneaky", Replayed: name}}, report.Drift)
+
+	_, err = verifyHistoryUC.Execute(ctx, verify_history.Request{ProductID: "no-such-product"})
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+}
+
 func TestBusinessRuleValidation(t *testing.T) {
 	ctx := context.Background()
 
@@ -305,89 +615,387 @@ func TestOutboxEventCreation(t *testing.T) {
 	for _, e := range events {
 		assert.Equal(t, "pending", e.status)
 		assert.Equal(t, productID, e.aggregateID)
-		assert.NotEmpty(t, e.payload)
+
+		ce, err := cloudevents.Parse([]byte(e.payload))
+		require.NoError(t, err)
+		assert.Equal(t, e.eventID, ce.ID)
+		assert.Equal(t, e.eventType, ce.Type)
+		assert.Equal(t, productID, ce.Subject)
+		assert.NotEmpty(t, ce.Data)
 	}
 }
 
-// --- setup helpers ---
+func TestOptimisticConcurrency(t *testing.T) {
+	ctx := context.Background()
 
-func provisionEmulator(ctx context.Context, databaseID string) error {
-	instAdmin, err := instance.NewInstanceAdminClient(ctx)
-	if err != nil {
-		return fmt.Errorf("instance admin: %w", err)
-	}
-	defer instAdmin.Close()
+	t.Run("stale expected version is rejected", func(t *testing.T) {
+		productID := createTestProduct(t, ctx, "Versioned", "electronics")
 
-	instPath := fmt.Sprintf("projects/%s/instances/%s", projectID, instanceID)
+		product, err := getProductQuery.Execute(ctx, productID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), product.Version)
+
+		name := "First Editor"
+		require.NoError(t, updateProductUC.Execute(ctx, update_product.Request{
+			ProductID:       productID,
+			Name:            &name,
+			ExpectedVersion: product.Version,
+		}))
+
+		name = "Second Editor"
+		err = updateProductUC.Execute(ctx, update_product.Request{
+			ProductID:       productID,
+			Name:            &name,
+			ExpectedVersion: product.Version,
+		})
+		assert.ErrorIs(t, err, domain.ErrVersionConflict)
 
-	// Attempt to create (ignore AlreadyExists).
-	op, err := instAdmin.CreateInstance(ctx, &instancepb.CreateInstanceRequest{
-		Parent:     "projects/" + projectID,
-		InstanceId: instanceID,
-		Instance: &instancepb.Instance{
-			Config:      fmt.Sprintf("projects/%s/instanceConfigs/emulator-config", projectID),
-			DisplayName: "Test",
-			NodeCount:   1,
-		},
+		product, err = getProductQuery.Execute(ctx, productID)
+		require.NoError(t, err)
+		assert.Equal(t, "First Editor", product.Name)
+		assert.Equal(t, int64(2), product.Version)
 	})
-	if err == nil {
-		if _, err := op.Wait(ctx); err != nil {
-			return fmt.Errorf("wait instance: %w", err)
+
+	t.Run("concurrent writers cannot both commit", func(t *testing.T) {
+		productID := createTestProduct(t, ctx, "Contended", "electronics")
+
+		first, err := productRepo.FindByID(ctx, productID)
+		require.NoError(t, err)
+		second, err := productRepo.FindByID(ctx, productID)
+		require.NoError(t, err)
+
+		now := time.Now().UTC()
+		require.NoError(t, first.UpdateDetails("Writer A", first.Description(), first.Category(), now))
+		require.NoError(t, second.UpdateDetails("Writer B", second.Description(), second.Category(), now))
+
+		planA := committer.NewPlan()
+		planA.Require(productRepo.VersionCheck(first))
+		planA.Add(productRepo.UpdateMut(first))
+		require.NoError(t, commitPlanner.Apply(ctx, planA))
+
+		planB := committer.NewPlan()
+		planB.Require(productRepo.VersionCheck(second))
+		planB.Add(productRepo.UpdateMut(second))
+		assert.ErrorIs(t, commitPlanner.Apply(ctx, planB), domain.ErrVersionConflict)
+
+		product, err := getProductQuery.Execute(ctx, productID)
+		require.NoError(t, err)
+		assert.Equal(t, "Writer A", product.Name)
+	})
+}
+
+func TestConcurrentActivation_OnlyOneWins(t *testing.T) {
+	ctx := context.Background()
+
+	productID := createTestProduct(t, ctx, "Race Item", "electronics")
+	require.NoError(t, deactivateUC.Execute(ctx, activate_product.Request{ProductID: productID}))
+
+	const workers = 5
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = activateUC.Execute(ctx, activate_product.Request{ProductID: productID})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
 		}
+		assert.ErrorIs(t, err, domain.ErrProductAlreadyActive)
 	}
+	assert.Equal(t, 1, succeeded)
 
-	dbAdmin, err := database.NewDatabaseAdminClient(ctx)
-	if err != nil {
-		return fmt.Errorf("database admin: %w", err)
+	var activated int
+	for _, e := range getOutboxEvents(t, ctx, productID) {
+		if e.eventType == "product.activated" {
+			activated++
+		}
 	}
-	defer dbAdmin.Close()
+	assert.Equal(t, 1, activated)
+}
 
-	ddl, err := os.ReadFile("../../migrations/001_initial_schema.sql")
-	if err != nil {
-		return fmt.Errorf("read migration: %w", err)
+func TestOutboxRelay(t *testing.T) {
+	ctx := context.Background()
+
+	productID := createTestProduct(t, ctx, "Relayed Item", "electronics")
+	require.NoError(t, deactivateUC.Execute(ctx, activate_product.Request{ProductID: productID}))
+
+	pub := &recordingPublisher{}
+	relay := outbox.NewRelay(outboxRepo, pub, commitPlanner, testClock, outbox.Config{
+		BatchSize: 50,
+	})
+	drainOutbox(t, ctx, relay)
+
+	var relayed []string
+	for _, e := range pub.events() {
+		if e.AggregateID == productID {
+			relayed = append(relayed, e.EventType)
+		}
+	}
+	assert.Equal(t, []string{"product.created", "product.deactivated"}, relayed)
+
+	for _, e := range getOutboxEvents(t, ctx, productID) {
+		assert.Equal(t, "processed", e.status)
 	}
-	statements := splitDDL(string(ddl))
 
-	dbOp, err := dbAdmin.CreateDatabase(ctx, &databasepb.CreateDatabaseRequest{
-		Parent:          instPath,
-		CreateStatement: fmt.Sprintf("CREATE DATABASE `%s`", databaseID),
-		ExtraStatements: statements,
+	n, err := relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestOutboxRelay_DeadLetterAndRequeue(t *testing.T) {
+	ctx := context.Background()
+
+	productID := createTestProduct(t, ctx, "Poison Item", "electronics")
+
+	pub := &recordingPublisher{}
+	pub.failFor(productID)
+	relay := outbox.NewRelay(outboxRepo, pub, commitPlanner, testClock, outbox.Config{
+		BatchSize: 50,
+		Retry: outbox.RetryPolicy{
+			MaxAttempts: 2,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    time.Millisecond,
+		},
 	})
-	if err != nil {
-		return fmt.Errorf("create database: %w", err)
+	drainOutbox(t, ctx, relay)
+
+	events := getOutboxEvents(t, ctx, productID)
+	require.Len(t, events, 1)
+	assert.Equal(t, "dead_letter", events[0].status)
+
+	admin := outbox.NewAdmin(outboxRepo, commitPlanner)
+	var found *contracts.OutboxEvent
+	token := ""
+	for found == nil {
+		page, next, err := admin.ListDeadLetters(ctx, 50, token)
+		require.NoError(t, err)
+		for i := range page {
+			if page[i].AggregateID == productID {
+				found = &page[i]
+			}
+		}
+		if next == "" {
+			break
+		}
+		token = next
 	}
-	if _, err := dbOp.Wait(ctx); err != nil {
-		return fmt.Errorf("wait database: %w", err)
+	require.NotNil(t, found)
+	assert.Equal(t, int64(2), found.Attempts)
+	assert.Contains(t, found.LastError, "refusing")
+
+	_, err := admin.Requeue(ctx, "no-such-event", "")
+	assert.ErrorIs(t, err, contracts.ErrOutboxEventNotFound)
+
+	n, err := admin.Requeue(ctx, "", productID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	pub.failFor("")
+	drainOutbox(t, ctx, relay)
+
+	events = getOutboxEvents(t, ctx, productID)
+	require.Len(t, events, 1)
+	assert.Equal(t, "processed", events[0].status)
+}
+
+func TestOutboxRelay_ConcurrentReplicasDeliverOnce(t *testing.T) {
+	ctx := context.Background()
+
+	var productIDs []string
+	for i := 0; i < 10; i++ {
+		id := createTestProduct(t, ctx, fmt.Sprintf("Replica Item %d", i), "electronics")
+		require.NoError(t, deactivateUC.Execute(ctx, activate_product.Request{ProductID: id}))
+		productIDs = append(productIDs, id)
 	}
 
-	return nil
+	pub := &recordingPublisher{}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		relay := outbox.NewRelay(outboxRepo, pub, commitPlanner, testClock, outbox.Config{
+			BatchSize: 5,
+			Owner:     fmt.Sprintf("replica-%d", i),
+			Lease:     10 * time.Second,
+		})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			relayUntilIdle(t, ctx, relay)
+		}()
+	}
+	wg.Wait()
+
+	delivered := pub.deliveries()
+	for _, id := range productIDs {
+		events := getOutboxEvents(t, ctx, id)
+		require.Len(t, events, 2)
+		for _, e := range events {
+			assert.Equal(t, "processed", e.status)
+			assert.Equal(t, 1, delivered[e.eventID], "event %s (%s)", e.eventID, e.eventType)
+		}
+	}
 }
 
-func splitDDL(raw string) []string {
-	parts := strings.Split(raw, ";")
-	var out []string
-	for _, p := range parts {
-		s := strings.TrimSpace(p)
-		if s != "" {
-			out = append(out, s)
+func TestOutboxRelay_DeliversInSequencePerAggregate(t *testing.T) {
+	ctx := context.Background()
+
+	var productIDs []string
+	for i := 0; i < 3; i++ {
+		id := createTestProduct(t, ctx, fmt.Sprintf("Ordered Item %d", i), "clothing")
+		for j := 0; j < 3; j++ {
+			now := time.Now().UTC()
+			require.NoError(t, applyDiscountUC.Execute(ctx, apply_discount.ApplyRequest{
+				ProductID:  id,
+				Percentage: big.NewRat(int64(10+j), 1),
+				StartDate:  now.Add(-time.Hour),
+				EndDate:    now.Add(24 * time.Hour),
+			}))
+			require.NoError(t, removeDiscountUC.Execute(ctx, apply_discount.RemoveRequest{ProductID: id}))
 		}
+		productIDs = append(productIDs, id)
+	}
+
+	pub := &recordingPublisher{}
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		relay := outbox.NewRelay(outboxRepo, pub, commitPlanner, testClock, outbox.Config{
+			BatchSize: 50,
+			Owner:     fmt.Sprintf("ordered-%d", i),
+			Workers:   4,
+		})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			relayUntilIdle(t, ctx, relay)
+		}()
+	}
+	wg.Wait()
+
+	for _, id := range productIDs {
+		var seqs []int64
+		var types []string
+		for _, e := range pub.events() {
+			if e.AggregateID == id {
+				seqs = append(seqs, e.Sequence)
+				types = append(types, e.EventType)
+			}
+		}
+		assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7}, seqs, "product %s", id)
+		assert.Equal(t, []string{
+			"product.created",
+			"discount.applied", "discount.removed",
+			"discount.applied", "discount.removed",
+			"discount.applied", "discount.removed",
+		}, types)
 	}
-	return out
 }
 
-func wireUsecases(client *spanner.Client) {
+func TestOutboxRelay_ReclaimsExpiredLease(t *testing.T) {
+	ctx := context.Background()
+
+	productID := createTestProduct(t, ctx, "Orphaned Item", "electronics")
+	events := getOutboxEvents(t, ctx, productID)
+	require.Len(t, events, 1)
+
+	// A replica claims the event and dies without settling it.
+	plan := committer.NewPlan()
+	plan.Add(outboxRepo.ClaimMut(events[0].eventID, "dead-replica", time.Now().UTC().Add(200*time.Millisecond)))
+	require.NoError(t, commitPlanner.Apply(ctx, plan))
+
+	pub := &recordingPublisher{}
+	relay := outbox.NewRelay(outboxRepo, pub, commitPlanner, testClock, outbox.Config{
+		BatchSize: 50,
+		Owner:     "survivor",
+		Lease:     10 * time.Second,
+	})
+
+	drainOutbox(t, ctx, relay)
+	assert.Zero(t, pub.deliveries()[events[0].eventID], "lease still valid, event must not be taken over")
+
+	time.Sleep(250 * time.Millisecond)
+	drainOutbox(t, ctx, relay)
+	assert.Equal(t, 1, pub.deliveries()[events[0].eventID])
+	assert.Equal(t, "processed", getOutboxEvents(t, ctx, productID)[0].status)
+}
+
+func TestOutboxRetention(t *testing.T) {
+	ctx := context.Background()
+
+	deliveredID := createTestProduct(t, ctx, "Retained Item", "electronics")
+	poisonID := createTestProduct(t, ctx, "Retained Poison", "electronics")
+
+	pub := &recordingPublisher{}
+	pub.failFor(poisonID)
+	relay := outbox.NewRelay(outboxRepo, pub, commitPlanner, testClock, outbox.Config{
+		BatchSize: 50,
+		Retry:     outbox.RetryPolicy{MaxAttempts: 1},
+	})
+	drainOutbox(t, ctx, relay)
+	require.Equal(t, "processed", getOutboxEvents(t, ctx, deliveredID)[0].status)
+	require.Equal(t, "dead_letter", getOutboxEvents(t, ctx, poisonID)[0].status)
+
+	pendingID := createTestProduct(t, ctx, "Retained Pending", "electronics")
+
+	// Events are product history: without PurgeHistory nothing goes.
+	keeper := outbox.NewRetention(outboxRepo, clock.FixedClock{T: time.Now().UTC().Add(365 * 24 * time.Hour)}, outbox.RetentionConfig{})
+	_, err := keeper.PurgeOnce(ctx)
+	require.ErrorIs(t, err, outbox.ErrHistoryRetained)
+	assert.Len(t, getOutboxEvents(t, ctx, deliveredID), 1)
+
+	purgeAt := func(after time.Duration) outbox.PurgeStats {
+		retention := outbox.NewRetention(outboxRepo, clock.FixedClock{T: time.Now().UTC().Add(after)}, outbox.RetentionConfig{
+			ProcessedTTL:  7 * 24 * time.Hour,
+			DeadLetterTTL: 30 * 24 * time.Hour,
+			PurgeHistory:  true,
+		})
+		stats, err := retention.PurgeOnce(ctx)
+		require.NoError(t, err)
+		return stats
+	}
+
+	// Within both TTLs nothing of ours goes.
+	purgeAt(0)
+	assert.Len(t, getOutboxEvents(t, ctx, deliveredID), 1)
+
+	stats := purgeAt(8 * 24 * time.Hour)
+	assert.Positive(t, stats.Processed)
+	assert.Empty(t, getOutboxEvents(t, ctx, deliveredID))
+	assert.Len(t, getOutboxEvents(t, ctx, poisonID), 1, "dead letters are kept longer")
+
+	stats = purgeAt(31 * 24 * time.Hour)
+	assert.Positive(t, stats.DeadLetters)
+	assert.Empty(t, getOutboxEvents(t, ctx, poisonID))
+	assert.Len(t, getOutboxEvents(t, ctx, pendingID), 1, "pending events are never purged")
+}
+
+// --- setup helpers ---
+
+func wireUsecases(h *harness) {
 	testClock = clock.RealClock{}
-	cm := committer.NewCommitter(client)
-	productRepo := repo.NewProductRepo(client)
-	outboxRepo := repo.NewOutboxRepo()
-	readModel := repo.NewProductReadModel(client)
+	cm := h.committer
+	commitPlanner = cm
+	productRepo = h.products
+	outboxRepo = h.outbox
+	priceHistoryRepo := h.priceHistory
+	readModel := h.readModel
+	eventStore = repo.NewEventStoreOn(h.outbox, usecases.DecodeEvent)
 
 	createProductUC = create_product.NewInteractor(productRepo, outboxRepo, cm, testClock)
 	updateProductUC = update_product.NewInteractor(productRepo, outboxRepo, cm, testClock)
 	applyDiscountUC = apply_discount.NewApplyInteractor(productRepo, outboxRepo, cm, testClock)
 	removeDiscountUC = apply_discount.NewRemoveInteractor(productRepo, outboxRepo, cm, testClock)
+	changePriceUC = change_price.NewInteractor(productRepo, priceHistoryRepo, outboxRepo, cm, testClock)
 	activateUC = activate_product.NewActivateInteractor(productRepo, outboxRepo, cm, testClock)
 	deactivateUC = activate_product.NewDeactivateInteractor(productRepo, outboxRepo, cm, testClock)
+	archiveUC = activate_product.NewArchiveInteractor(productRepo, outboxRepo, cm, testClock)
+	verifyHistoryUC = verify_history.NewInteractor(productRepo, eventStore, cm)
 	getProductQuery = get_product.NewHandler(readModel, testClock)
 	listProductsQuery = list_products.NewHandler(readModel, testClock)
 }
@@ -409,37 +1017,81 @@ func createTestProductWithCategory(t *testing.T, ctx context.Context, name, cate
 	return createTestProduct(t, ctx, name, category)
 }
 
-type outboxRow struct {
-	eventType   string
-	aggregateID string
-	status      string
-	payload     string
+func getOutboxEvents(t *testing.T, ctx context.Context, aggregateID string) []outboxRow {
+	t.Helper()
+	rows, err := backend.outboxRows(ctx, aggregateID)
+	require.NoError(t, err)
+	return rows
 }
 
-func getOutboxEvents(t *testing.T, ctx context.Context, aggregateID string) []outboxRow {
+// drainOutbox runs the relay until nothing is due. Other tests leave pending
+// rows behind, so this can relay more than the calling test created.
+func drainOutbox(t *testing.T, ctx context.Context, relay *outbox.Relay) {
 	t.Helper()
-	stmt := spanner.Statement{
-		SQL: `SELECT event_type, aggregate_id, status, payload
-			  FROM outbox_events WHERE aggregate_id = @id ORDER BY created_at`,
-		Params: map[string]interface{}{"id": aggregateID},
+	for i := 0; i < 100; i++ {
+		n, err := relay.RelayOnce(ctx)
+		require.NoError(t, err)
+		if n == 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond) // let retry backoffs expire
 	}
+	t.Fatal("outbox did not drain")
+}
 
-	iter := spannerClient.Single().Query(ctx, stmt)
-	defer iter.Stop()
-
-	var rows []outboxRow
-	for {
-		row, err := iter.Next()
-		if err == iterator.Done {
-			break
+// relayUntilIdle is drainOutbox for relays running side by side: a relay
+// can see nothing claimable while another one holds the next event, so it
+// only stops after a few empty polls. Safe to call from a goroutine.
+func relayUntilIdle(t *testing.T, ctx context.Context, relay *outbox.Relay) {
+	for idle := 0; idle < 3; {
+		n, err := relay.RelayOnce(ctx)
+		if !assert.NoError(t, err) {
+			return
 		}
-		require.NoError(t, err)
+		if n == 0 {
+			idle++
+			time.Sleep(10 * time.Millisecond)
+		} else {
+			idle = 0
+		}
+	}
+}
+
+type recordingPublisher struct {
+	mu        sync.Mutex
+	published []contracts.OutboxEvent
+	failing   string // aggregate whose events are refused
+}
 
-		var r outboxRow
-		var payload json.RawMessage
-		require.NoError(t, row.Columns(&r.eventType, &r.aggregateID, &r.status, &payload))
-		r.payload = string(payload)
-		rows = append(rows, r)
+func (p *recordingPublisher) Publish(_ context.Context, event contracts.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failing != "" && event.AggregateID == p.failing {
+		return fmt.Errorf("refusing %s", event.ID)
 	}
-	return rows
+	p.published = append(p.published, event)
+	return nil
+}
+
+// deliveries counts successful publishes per event ID.
+func (p *recordingPublisher) deliveries() map[string]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	counts := make(map[string]int, len(p.published))
+	for _, e := range p.published {
+		counts[e.ID]++
+	}
+	return counts
+}
+
+func (p *recordingPublisher) failFor(aggregateID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failing = aggregateID
+}
+
+func (p *recordingPublisher) events() []contracts.OutboxEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]contracts.OutboxEvent(nil), p.published...)
 }
@@ -0,0 +1,172 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	database "cloud.google.com/go/spanner/admin/database/apiv1"
+	databasepb "cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
+	instance "cloud.google.com/go/spanner/admin/instance/apiv1"
+	instancepb "cloud.google.com/go/spanner/admin/instance/apiv1/instancepb"
+	"google.golang.org/api/iterator"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo"
+	"github.com/tshubham2/catalog-proj/internal/migrate"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/catalog-proj/migrations"
+)
+
+const (
+	projectID  = "test-project"
+	instanceID = "test-instance"
+)
+
+// setupSpanner creates an empty database on the emulator and migrates it.
+func setupSpanner(ctx context.Context) (*harness, error) {
+	if os.Getenv("SPANNER_EMULATOR_HOST") == "" {
+		os.Setenv("SPANNER_EMULATOR_HOST", "localhost:9010")
+	}
+
+	databaseID := fmt.Sprintf("testdb_%d", time.Now().UnixNano())
+	dbPath := fmt.Sprintf("projects/%s/instances/%s/databases/%s", projectID, instanceID, databaseID)
+
+	if err := provisionEmulator(ctx, databaseID); err != nil {
+		return nil, fmt.Errorf("%w: %v", errUnavailable, err)
+	}
+
+	client, err := spanner.NewClient(ctx, dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("create spanner client: %w", err)
+	}
+	dbAdmin, err := database.NewDatabaseAdminClient(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("database admin: %w", err)
+	}
+	defer dbAdmin.Close()
+	if err := migrateSchema(ctx, migrate.NewSpannerTarget(client, dbAdmin), migrations.Spanner()); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	products := repo.NewProductRepo(client)
+	return &harness{
+		committer:    committer.NewSpannerCommitter(client),
+		products:     products,
+		outbox:       repo.NewOutboxRepo(client),
+		priceHistory: repo.NewPriceHistoryRepo(),
+		readModel:    repo.NewProductReadModel(client),
+		records:      products,
+		quarantine:   repo.NewQuarantineRepo(),
+		views:        repo.NewProductViewRepo(client),
+
+		priceChanges: func(ctx context.Context, productID string) ([]priceChangeRow, error) {
+			stmt := spanner.Statement{
+				SQL: `SELECT old_price, new_price
+					  FROM product_price_history WHERE product_id = @id`,
+				Params: map[string]interface{}{"id": productID},
+			}
+			var out []priceChangeRow
+			err := client.Single().Query(ctx, stmt).Do(func(row *spanner.Row) error {
+				var oldPrice, newPrice big.Rat
+				if err := row.Columns(&oldPrice, &newPrice); err != nil {
+					return err
+				}
+				out = append(out, priceChangeRow{&oldPrice, &newPrice})
+				return nil
+			})
+			return out, err
+		},
+		outboxRows: func(ctx context.Context, aggregateID string) ([]outboxRow, error) {
+			return spannerOutboxRows(ctx, client, aggregateID)
+		},
+		renameProduct: func(ctx context.Context, productID, name string) error {
+			_, err := client.Apply(ctx, []*spanner.Mutation{
+				spanner.Update("products", []string{"product_id", "name"}, []interface{}{productID, name}),
+			})
+			return err
+		},
+		close: client.Close,
+	}, nil
+}
+
+func spannerOutboxRows(ctx context.Context, client *spanner.Client, aggregateID string) ([]outboxRow, error) {
+	stmt := spanner.Statement{
+		SQL: `SELECT event_id, event_type, aggregate_id, status, payload
+			  FROM outbox_events WHERE aggregate_id = @id ORDER BY created_at`,
+		Params: map[string]interface{}{"id": aggregateID},
+	}
+
+	iter := client.Single().Query(ctx, stmt)
+	defer iter.Stop()
+
+	var rows []outboxRow
+	for {
+		row, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var r outboxRow
+		var payload json.RawMessage
+		if err := row.Columns(&r.eventID, &r.eventType, &r.aggregateID, &r.status, &payload); err != nil {
+			return nil, err
+		}
+		r.payload = string(payload)
+		rows = append(rows, r)
+	}
+	return rows, nil
+}
+
+func provisionEmulator(ctx context.Context, databaseID string) error {
+	instAdmin, err := instance.NewInstanceAdminClient(ctx)
+	if err != nil {
+		return fmt.Errorf("instance admin: %w", err)
+	}
+	defer instAdmin.Close()
+
+	instPath := fmt.Sprintf("projects/%s/instances/%s", projectID, instanceID)
+
+	// Attempt to create (ignore AlreadyExists).
+	op, err := instAdmin.CreateInstance(ctx, &instancepb.CreateInstanceRequest{
+		Parent:     "projects/" + projectID,
+		InstanceId: instanceID,
+		Instance: &instancepb.Instance{
+			Config:      fmt.Sprintf("projects/%s/instanceConfigs/emulator-config", projectID),
+			DisplayName: "Test",
+			NodeCount:   1,
+		},
+	})
+	if err == nil {
+		if _, err := op.Wait(ctx); err != nil {
+			return fmt.Errorf("wait instance: %w", err)
+		}
+	}
+
+	dbAdmin, err := database.NewDatabaseAdminClient(ctx)
+	if err != nil {
+		return fmt.Errorf("database admin: %w", err)
+	}
+	defer dbAdmin.Close()
+
+	dbOp, err := dbAdmin.CreateDatabase(ctx, &databasepb.CreateDatabaseRequest{
+		Parent:          instPath,
+		CreateStatement: fmt.Sprintf("CREATE DATABASE `%s`", databaseID),
+	})
+	if err != nil {
+		return fmt.Errorf("create database: %w", err)
+	}
+	if _, err := dbOp.Wait(ctx); err != nil {
+		return fmt.Errorf("wait database: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package e2e
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/tshubham2/catalog-proj/internal/app/product/repo/sqlite"
+	"github.com/tshubham2/catalog-proj/internal/migrate"
+	"github.com/tshubham2/catalog-proj/internal/pkg/committer"
+	"github.com/tshubham2/catalog-proj/migrations"
+	sqlitedriver "github.com/tshubham2/commitplan/drivers/sqlite"
+)
+
+// setupSQLite migrates a database file in a temporary directory, removed
+// when the suite is done. It needs nothing running.
+func setupSQLite(ctx context.Context) (*harness, error) {
+	dir, err := os.MkdirTemp("", "catalog-e2e-")
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", sqlitedriver.DSN(filepath.Join(dir, "catalog.db")))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	cleanup := func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+
+	if err := migrateSchema(ctx, migrate.NewSQLiteTarget(db), migrations.SQLite()); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	products := sqlite.NewProductRepo(db)
+	return &harness{
+		committer:    committer.NewSQLiteCommitter(db),
+		products:     products,
+		outbox:       sqlite.NewOutboxRepo(db),
+		priceHistory: sqlite.NewPriceHistoryRepo(),
+		readModel:    sqlite.NewProductReadModel(db),
+		records:      products,
+		quarantine:   sqlite.NewQuarantineRepo(),
+		views:        sqlite.NewProductViewRepo(db),
+
+		priceChanges: func(ctx context.Context, productID string) ([]priceChangeRow, error) {
+			rows, err := db.QueryContext(ctx, `SELECT old_price, new_price
+				FROM product_price_history WHERE product_id = ?1`, productID)
+			if err != nil {
+				return nil, err
+			}
+			defer rows.Close()
+
+			var out []priceChangeRow
+			for rows.Next() {
+				var oldPrice, newPrice string
+				if err := rows.Scan(&oldPrice, &newPrice); err != nil {
+					return nil, err
+				}
+				row, err := parsePriceChange(oldPrice, newPrice)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, row)
+			}
+			return out, rows.Err()
+		},
+		outboxRows: func(ctx context.Context, aggregateID string) ([]outboxRow, error) {
+			rows, err := db.QueryContext(ctx, `SELECT event_id, event_type, aggregate_id, status, payload
+				FROM outbox_events WHERE aggregate_id = ?1 ORDER BY created_at`, aggregateID)
+			if err != nil {
+				return nil, err
+			}
+			defer rows.Close()
+
+			var out []outboxRow
+			for rows.Next() {
+				var r outboxRow
+				if err := rows.Scan(&r.eventID, &r.eventType, &r.aggregateID, &r.status, &r.payload); err != nil {
+					return nil, err
+				}
+				out = append(out, r)
+			}
+			return out, rows.Err()
+		},
+		renameProduct: func(ctx context.Context, productID, name string) error {
+			_, err := db.ExecContext(ctx, `UPDATE products SET name = ?1 WHERE product_id = ?2`, name, productID)
+			return err
+		},
+		close: cleanup,
+	}, nil
+}